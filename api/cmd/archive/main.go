@@ -0,0 +1,113 @@
+// Package main 全站备份命令
+// 用法：
+//
+//	archive export [-o 文件]   导出全站数据归档
+//	archive restore <文件>     将归档恢复到空数据库
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"kuaiyu/internal/archive"
+	"kuaiyu/internal/config"
+	"kuaiyu/internal/database"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	config.LoadEnvFiles()
+
+	// 初始化数据库
+	if err := database.Init(); err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer database.Close()
+
+	if err := database.Migrate(); err != nil {
+		log.Fatalf("Failed to migrate database: %v", err)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "export":
+		err = runExport(os.Args[2:])
+	case "restore":
+		err = runRestore(os.Args[2:])
+	default:
+		usage()
+	}
+	if err != nil {
+		log.Fatalf("%s failed: %v", os.Args[1], err)
+	}
+}
+
+// usage 打印用法并退出
+func usage() {
+	fmt.Fprintln(os.Stderr, "用法: archive export [-o 文件] | archive restore <文件>")
+	os.Exit(2)
+}
+
+// runExport 导出归档到文件
+func runExport(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	output := fs.String("o", "kuaiyu-backup-"+time.Now().Format("20060102-150405")+".zip", "输出文件")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	f, err := os.Create(*output)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	manifest, err := archive.Export(f)
+	if err != nil {
+		os.Remove(*output)
+		return err
+	}
+
+	fmt.Printf("已导出到 %s\n", *output)
+	for _, name := range manifest.Files {
+		fmt.Printf("  %-20s %d\n", name, manifest.Counts[name])
+	}
+	return nil
+}
+
+// runRestore 从归档文件恢复
+func runRestore(args []string) error {
+	if len(args) != 1 {
+		usage()
+	}
+
+	f, err := os.Open(args[0])
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	report, err := archive.Restore(f, info.Size())
+	if err != nil {
+		return err
+	}
+
+	// 同步重建检索索引和相关文章，命令结束前完成
+	archive.Reindex()
+
+	out, _ := json.MarshalIndent(report, "", "  ")
+	fmt.Println(string(out))
+	return nil
+}
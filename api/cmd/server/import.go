@@ -0,0 +1,82 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"kuaiyu/internal/database"
+	"kuaiyu/internal/importer"
+	"kuaiyu/internal/model"
+	"kuaiyu/internal/related"
+)
+
+// ===========================================
+// 文章导入子命令
+// ===========================================
+
+// runImport 执行 import 子命令
+// 用法：server import [-commit] [-author ID] <目录|zip|md 文件>
+// 默认只打印预演报告，加 -commit 才实际写入
+func runImport(args []string) error {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	commit := fs.Bool("commit", false, "实际写入数据库（默认仅预演）")
+	authorID := fs.Uint("author", 0, "文章作者 ID（默认第一个用户）")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errors.New("用法: server import [-commit] [-author ID] <目录|zip|md 文件>")
+	}
+
+	files, err := importer.ReadPath(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	im := importer.NewImporter()
+	if !*commit {
+		printReport(im.Plan(files))
+		return nil
+	}
+
+	if *authorID == 0 {
+		var user model.User
+		if err := database.Get().Order("id ASC").First(&user).Error; err != nil {
+			return fmt.Errorf("未找到可用的作者: %w", err)
+		}
+		*authorID = user.ID
+	}
+
+	report := im.Apply(files, *authorID)
+	printReport(report)
+
+	if report.Created > 0 {
+		if _, err := related.Rebuild(); err != nil {
+			return fmt.Errorf("重新计算相关文章失败: %w", err)
+		}
+	}
+	return nil
+}
+
+// printReport 以表格形式打印导入报告
+func printReport(report *model.ImportReport) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ACTION\tFILE\tSLUG\tSTATUS\tREASON")
+	for _, item := range report.Items {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", item.Action, item.File, item.Slug, item.Status, item.Reason)
+	}
+	w.Flush()
+
+	mode := "已导入"
+	if report.DryRun {
+		mode = "预演（未写入，加 -commit 执行导入）"
+	}
+	fmt.Printf("\n%s：共 %d 个文件，新建 %d，跳过 %d，冲突 %d\n",
+		mode, report.Total, report.Created, report.Skipped, report.Conflicted)
+	if len(report.NewTags) > 0 {
+		fmt.Printf("新建标签：%v\n", report.NewTags)
+	}
+}
@@ -4,46 +4,23 @@ package main
 import (
 	"log"
 	"os"
-	"path/filepath"
 
 	"github.com/gin-gonic/gin"
-	"github.com/joho/godotenv"
 	"kuaiyu/internal/config"
 	"kuaiyu/internal/database"
+	"kuaiyu/internal/notify"
+	"kuaiyu/internal/ping"
 	"kuaiyu/internal/router"
+	"kuaiyu/internal/related"
+	"kuaiyu/internal/render"
+	"kuaiyu/internal/repository"
+	"kuaiyu/internal/scheduler"
+	"kuaiyu/internal/search"
 )
 
 func main() {
-	// 加载 .env 文件（支持 .env 和 .env.local）
-	// 查找项目根目录（向上查找包含 docker-compose.yml 的目录）
-	workDir, _ := os.Getwd()
-	rootDir := workDir
-	for {
-		if _, err := os.Stat(filepath.Join(rootDir, "docker-compose.yml")); err == nil {
-			break
-		}
-		parent := filepath.Dir(rootDir)
-		if parent == rootDir {
-			// 已到达根目录，使用当前目录
-			rootDir = workDir
-			break
-		}
-		rootDir = parent
-	}
-	
-	// 加载 .env 文件（先加载 .env，然后加载 .env.local，.env.local 会覆盖 .env 的值）
-	envFiles := []string{".env", ".env.local"}
-	for _, envFile := range envFiles {
-		envPath := filepath.Join(rootDir, envFile)
-		if _, err := os.Stat(envPath); err == nil {
-			// 使用 Overload 确保后面的文件可以覆盖前面的值
-			if err := godotenv.Overload(envPath); err != nil {
-				log.Printf("Warning: Failed to load %s: %v", envPath, err)
-			} else {
-				log.Printf("Loaded environment file: %s", envPath)
-			}
-		}
-	}
+	// 加载项目根目录下的 .env 和 .env.local（.env.local 覆盖 .env 的值）
+	config.LoadEnvFiles()
 	
 	// 加载配置
 	cfg := config.Get()
@@ -68,6 +45,52 @@ func main() {
 		log.Fatalf("Failed to seed database: %v", err)
 	}
 	
+	// 为升级前的评论生成主题结构
+	if err := repository.NewCommentRepository().EnsurePaths(); err != nil {
+		log.Fatalf("Failed to build comment threads: %v", err)
+	}
+	
+	// 命令行子命令：导入文章后直接退出
+	if len(os.Args) > 1 && os.Args[1] == "import" {
+		if err := runImport(os.Args[2:]); err != nil {
+			log.Fatalf("Import failed: %v", err)
+		}
+		return
+	}
+	
+	// 首次部署时构建检索索引和相关文章，并清理过期的渲染缓存
+	go search.EnsureIndex()
+	go related.EnsureComputed()
+	go render.EnsurePruned()
+	
+	// 启动定时发布调度器
+	if cfg.Scheduler.Enabled {
+		publisher := scheduler.NewPublisher(cfg.Scheduler.PublishInterval)
+		publisher.OnPublish(search.OnPublish)
+		publisher.OnPublish(related.OnPublish)
+		publisher.OnPublish(ping.OnPublish)
+		publisher.Start()
+		defer publisher.Stop()
+	}
+	
+	// 启动搜索引擎推送队列
+	if cfg.Ping.Enabled {
+		pingQueue := ping.NewQueue(cfg.Ping.Interval)
+		pingQueue.Start()
+		defer pingQueue.Stop()
+	}
+	
+	// 启动邮件发件箱
+	if cfg.Mail.Enabled {
+		mailer, err := notify.NewMailer(cfg.Mail)
+		if err != nil {
+			log.Fatalf("Failed to create mailer: %v", err)
+		}
+		outbox := notify.NewQueue(mailer, cfg.Mail.Interval)
+		outbox.Start()
+		defer outbox.Stop()
+	}
+	
 	// 创建 Gin 实例
 	r := gin.New()
 	r.Use(gin.Logger())
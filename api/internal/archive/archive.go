@@ -0,0 +1,221 @@
+// Package archive 全站导出与恢复
+// 导出为 zip 归档：每类数据一个 JSON Lines 文件，文章另存为带 front matter 的 Markdown，
+// 并附带 manifest.json 描述格式版本和数量；恢复时为外键重新映射 ID
+package archive
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"kuaiyu/internal/model"
+	"kuaiyu/internal/related"
+	"kuaiyu/internal/search"
+)
+
+// ===========================================
+// 归档格式
+// ===========================================
+
+const (
+	// Format 归档格式标识
+	Format = "kuaiyu-archive"
+	// Version 归档格式版本，字段不兼容变化时递增
+	Version = 1
+)
+
+// 归档内的文件名
+const (
+	fileManifest     = "manifest.json"
+	fileUsers        = "users.jsonl"
+	fileCategories   = "categories.jsonl"
+	fileTags         = "tags.jsonl"
+	filePosts        = "posts.jsonl"
+	fileLife         = "life_records.jsonl"
+	fileComments     = "comments.jsonl"
+	fileBills        = "bills.jsonl"
+	fileSeries       = "series.jsonl"
+	fileTranslations = "post_translations.jsonl"
+	dirMarkdown      = "posts/"
+)
+
+// billDateLayout 账单日期格式
+const billDateLayout = "2006-01-02"
+
+// ErrNotEmpty 目标数据库已有内容，拒绝恢复
+var ErrNotEmpty = errors.New("数据库中已有内容，只能恢复到空数据库")
+
+// Manifest 归档清单
+type Manifest struct {
+	Format    string         `json:"format"`
+	Version   int            `json:"version"`
+	CreatedAt time.Time      `json:"created_at"`
+	Counts    map[string]int `json:"counts"` // 各文件的记录数
+	Files     []string       `json:"files"`
+}
+
+// RestoreReport 恢复结果
+type RestoreReport struct {
+	Version  int            `json:"version"`
+	Restored map[string]int `json:"restored"`
+	Skipped  map[string]int `json:"skipped"`  // 外键缺失等原因跳过的记录
+	Warnings []string       `json:"warnings"` // 需要人工处理的事项
+}
+
+// ===========================================
+// 记录结构
+// ===========================================
+
+// UserRecord 用户资料（不含密码）
+type UserRecord struct {
+	ID        uint      `json:"id"`
+	Username  string    `json:"username"`
+	Email     string    `json:"email"`
+	Avatar    string    `json:"avatar"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CategoryRecord 账单分类
+type CategoryRecord struct {
+	ID        uint      `json:"id"`
+	Name      string    `json:"name"`
+	Key       string    `json:"key"`
+	Type      string    `json:"type"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TagRecord 标签
+type TagRecord struct {
+	ID          uint      `json:"id"`
+	Name        string    `json:"name"`
+	Slug        string    `json:"slug"`
+	Description string    `json:"description"`
+	Color       string    `json:"color"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// PostRecord 文章
+type PostRecord struct {
+	ID           uint       `json:"id"`
+	Title        string     `json:"title"`
+	Slug         string     `json:"slug"`
+	Content      string     `json:"content"`
+	Excerpt      string     `json:"excerpt"`
+	CoverImage   string     `json:"cover_image"`
+	Status       string     `json:"status"`
+	Visibility   string     `json:"visibility,omitempty"`
+	PasswordHash string     `json:"password_hash,omitempty"` // 加密文章的 bcrypt 哈希
+	PinOrder     int        `json:"pin_order,omitempty"`
+	PinnedUntil  *time.Time `json:"pinned_until,omitempty"`
+	FeatureOrder int        `json:"feature_order,omitempty"`
+	ViewCount    int        `json:"view_count"`
+	AuthorID     uint       `json:"author_id"`
+	PublishedAt  *time.Time `json:"published_at"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+	TagIDs       []uint     `json:"tag_ids"`
+}
+
+// LifeRecord 生活记录
+type LifeRecord struct {
+	ID          uint       `json:"id"`
+	Title       string     `json:"title"`
+	Content     string     `json:"content"`
+	CoverImage  string     `json:"cover_image"`
+	Status      string     `json:"status"`
+	ViewCount   int        `json:"view_count"`
+	AuthorID    uint       `json:"author_id"`
+	PublishedAt *time.Time `json:"published_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// CommentRecord 评论
+type CommentRecord struct {
+	ID           uint       `json:"id"`
+	CommentType  string     `json:"comment_type"`
+	TargetID     *uint      `json:"target_id"`
+	PostID       *uint      `json:"post_id"`
+	LifeRecordID *uint      `json:"life_record_id"`
+	ParentID     *uint      `json:"parent_id"`
+	ReplyToID    *uint      `json:"reply_to_id"`
+	Nickname     string     `json:"nickname"`
+	Email        string     `json:"email"`
+	Avatar       string     `json:"avatar"`
+	Website      string     `json:"website"`
+	Content      string     `json:"content"`
+	IsAdmin      bool       `json:"is_admin"`
+	IsPinned     bool       `json:"is_pinned"`
+	Status       string     `json:"status"`
+	IPAddress    string     `json:"ip_address"`
+	UserAgent    string     `json:"user_agent"`
+	SpamScore    float64    `json:"spam_score"`   // 反垃圾检查总分
+	SpamReasons  string     `json:"spam_reasons"` // 各项检查结果（JSON）
+	EditedAt     *time.Time `json:"edited_at"`    // 作者最后编辑时间
+	Withdrawn    bool       `json:"withdrawn"`    // 作者已删除的占位评论
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// BillRecord 账单
+type BillRecord struct {
+	ID         uint      `json:"id"`
+	Type       string    `json:"type"`
+	CategoryID uint      `json:"category_id"`
+	Amount     float64   `json:"amount"`
+	Desc       string    `json:"desc"`
+	Date       string    `json:"date"`
+	PeriodType string    `json:"period_type"`
+	IsConsumed bool      `json:"is_consumed"`
+	Refund     float64   `json:"refund"`
+	RefundType int       `json:"refund_type"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// SeriesRecord 系列（PostIDs 按系列内顺序排列）
+type SeriesRecord struct {
+	ID          uint      `json:"id"`
+	Name        string    `json:"name"`
+	Slug        string    `json:"slug"`
+	Description string    `json:"description"`
+	CoverImage  string    `json:"cover_image"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	PostIDs     []uint    `json:"post_ids"`
+}
+
+// TranslationRecord 文章翻译
+type TranslationRecord struct {
+	PostID    uint      `json:"post_id"`
+	Locale    string    `json:"locale"`
+	Title     string    `json:"title"`
+	Slug      string    `json:"slug"`
+	Content   string    `json:"content"`
+	Excerpt   string    `json:"excerpt"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ===========================================
+// 恢复后处理
+// ===========================================
+
+// Reindex 恢复后重建检索索引和相关文章
+func Reindex() {
+	if _, err := search.Rebuild(); err != nil {
+		log.Printf("Failed to rebuild search index: %v", err)
+	}
+	if _, err := related.Rebuild(); err != nil {
+		log.Printf("Failed to rebuild related posts: %v", err)
+	}
+}
+
+// tagIDs 提取标签 ID
+func tagIDs(tags []model.Tag) []uint {
+	ids := make([]uint, len(tags))
+	for i, tag := range tags {
+		ids[i] = tag.ID
+	}
+	return ids
+}
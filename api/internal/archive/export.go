@@ -0,0 +1,431 @@
+package archive
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/goccy/go-yaml"
+	"gorm.io/gorm"
+	"kuaiyu/internal/database"
+	"kuaiyu/internal/model"
+	"kuaiyu/pkg/constants"
+)
+
+// ===========================================
+// 导出
+// ===========================================
+
+// exportBatchSize 分批读取的记录数
+const exportBatchSize = 200
+
+// exporter 单次导出的状态
+type exporter struct {
+	db       *gorm.DB
+	zw       *zip.Writer
+	manifest *Manifest
+}
+
+// Export 将全站数据写入 zip 归档
+func Export(w io.Writer) (*Manifest, error) {
+	e := &exporter{
+		db: database.Get(),
+		zw: zip.NewWriter(w),
+		manifest: &Manifest{
+			Format:    Format,
+			Version:   Version,
+			CreatedAt: time.Now(),
+			Counts:    make(map[string]int),
+			Files:     []string{},
+		},
+	}
+
+	steps := []func() error{
+		e.users,
+		e.categories,
+		e.tags,
+		e.posts,
+		e.life,
+		e.comments,
+		e.bills,
+		e.series,
+		e.translations,
+	}
+	for _, step := range steps {
+		if err := step(); err != nil {
+			return nil, err
+		}
+	}
+
+	// 清单最后写入，此时数量已统计完整
+	f, err := e.zw.Create(fileManifest)
+	if err != nil {
+		return nil, err
+	}
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(e.manifest); err != nil {
+		return nil, err
+	}
+
+	if err := e.zw.Close(); err != nil {
+		return nil, err
+	}
+	return e.manifest, nil
+}
+
+// jsonl 创建 JSON Lines 文件，返回逐条写入的函数
+func (e *exporter) jsonl(name string) (func(v interface{}) error, error) {
+	f, err := e.zw.Create(name)
+	if err != nil {
+		return nil, err
+	}
+	e.manifest.Files = append(e.manifest.Files, name)
+	e.manifest.Counts[name] = 0
+
+	enc := json.NewEncoder(f)
+	enc.SetEscapeHTML(false)
+	return func(v interface{}) error {
+		e.manifest.Counts[name]++
+		return enc.Encode(v)
+	}, nil
+}
+
+// users 导出用户资料
+func (e *exporter) users() error {
+	write, err := e.jsonl(fileUsers)
+	if err != nil {
+		return err
+	}
+
+	var users []model.User
+	return e.db.Order("id ASC").FindInBatches(&users, exportBatchSize, func(tx *gorm.DB, batch int) error {
+		for _, u := range users {
+			if err := write(UserRecord{
+				ID:        u.ID,
+				Username:  u.Username,
+				Email:     u.Email,
+				Avatar:    u.Avatar,
+				CreatedAt: u.CreatedAt,
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}).Error
+}
+
+// categories 导出账单分类
+func (e *exporter) categories() error {
+	write, err := e.jsonl(fileCategories)
+	if err != nil {
+		return err
+	}
+
+	var categories []model.Category
+	return e.db.Order("id ASC").FindInBatches(&categories, exportBatchSize, func(tx *gorm.DB, batch int) error {
+		for _, c := range categories {
+			if err := write(CategoryRecord{
+				ID:        c.ID,
+				Name:      c.Name,
+				Key:       c.Key,
+				Type:      c.Type,
+				CreatedAt: c.CreatedAt,
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}).Error
+}
+
+// tags 导出标签
+func (e *exporter) tags() error {
+	write, err := e.jsonl(fileTags)
+	if err != nil {
+		return err
+	}
+
+	var tags []model.Tag
+	return e.db.Order("id ASC").FindInBatches(&tags, exportBatchSize, func(tx *gorm.DB, batch int) error {
+		for _, t := range tags {
+			if err := write(TagRecord{
+				ID:          t.ID,
+				Name:        t.Name,
+				Slug:        t.Slug,
+				Description: t.Description,
+				Color:       t.Color,
+				CreatedAt:   t.CreatedAt,
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}).Error
+}
+
+// posts 导出文章，同时写入 Markdown 文件
+func (e *exporter) posts() error {
+	write, err := e.jsonl(filePosts)
+	if err != nil {
+		return err
+	}
+
+	var posts []model.Post
+	var markdown []model.Post
+	err = e.db.Preload("Tags").Order("id ASC").FindInBatches(&posts, exportBatchSize, func(tx *gorm.DB, batch int) error {
+		for _, p := range posts {
+			if err := write(PostRecord{
+				ID:           p.ID,
+				Title:        p.Title,
+				Slug:         p.Slug,
+				Content:      p.Content,
+				Excerpt:      p.Excerpt,
+				CoverImage:   p.CoverImage,
+				Status:       p.Status,
+				Visibility:   p.Visibility,
+				PasswordHash: p.PasswordHash,
+				PinOrder:     p.PinOrder,
+				PinnedUntil:  p.PinnedUntil,
+				FeatureOrder: p.FeatureOrder,
+				ViewCount:    p.ViewCount,
+				AuthorID:     p.AuthorID,
+				PublishedAt:  p.PublishedAt,
+				CreatedAt:    p.CreatedAt,
+				UpdatedAt:    p.UpdatedAt,
+				TagIDs:       tagIDs(p.Tags),
+			}); err != nil {
+				return err
+			}
+		}
+		markdown = append(markdown, posts...)
+		return nil
+	}).Error
+	if err != nil {
+		return err
+	}
+
+	// zip 同一时间只能写一个文件，JSON Lines 写完后再写 Markdown
+	for i := range markdown {
+		if err := e.markdown(&markdown[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// postFrontMatter Markdown 文件的 front matter，字段与文章导入兼容
+type postFrontMatter struct {
+	Title      string   `yaml:"title"`
+	Slug       string   `yaml:"slug"`
+	Date       string   `yaml:"date"`
+	Updated    string   `yaml:"updated"`
+	Tags       []string `yaml:"tags,omitempty"`
+	Draft      bool     `yaml:"draft,omitempty"`
+	Excerpt    string   `yaml:"excerpt,omitempty"`
+	CoverImage string   `yaml:"cover_image,omitempty"`
+}
+
+// markdown 写入单篇文章的 Markdown 文件
+func (e *exporter) markdown(p *model.Post) error {
+	date := p.CreatedAt
+	if p.PublishedAt != nil {
+		date = *p.PublishedAt
+	}
+
+	fm := postFrontMatter{
+		Title:      p.Title,
+		Slug:       p.Slug,
+		Date:       date.Format(time.RFC3339),
+		Updated:    p.UpdatedAt.Format(time.RFC3339),
+		Draft:      p.Status == string(constants.PostStatusDraft),
+		Excerpt:    p.Excerpt,
+		CoverImage: p.CoverImage,
+	}
+	for _, tag := range p.Tags {
+		fm.Tags = append(fm.Tags, tag.Name)
+	}
+
+	header, err := yaml.Marshal(fm)
+	if err != nil {
+		return err
+	}
+
+	name := p.Slug
+	if name == "" {
+		name = strconv.FormatUint(uint64(p.ID), 10)
+	}
+	f, err := e.zw.Create(dirMarkdown + name + ".md")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(f, "---\n%s---\n\n%s\n", header, p.Content)
+	return err
+}
+
+// life 导出生活记录
+func (e *exporter) life() error {
+	write, err := e.jsonl(fileLife)
+	if err != nil {
+		return err
+	}
+
+	var records []model.LifeRecord
+	return e.db.Order("id ASC").FindInBatches(&records, exportBatchSize, func(tx *gorm.DB, batch int) error {
+		for _, l := range records {
+			if err := write(LifeRecord{
+				ID:          l.ID,
+				Title:       l.Title,
+				Content:     l.Content,
+				CoverImage:  l.CoverImage,
+				Status:      l.Status,
+				ViewCount:   l.ViewCount,
+				AuthorID:    l.AuthorID,
+				PublishedAt: l.PublishedAt,
+				CreatedAt:   l.CreatedAt,
+				UpdatedAt:   l.UpdatedAt,
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}).Error
+}
+
+// comments 导出评论（按 ID 升序，父评论总在回复之前）
+func (e *exporter) comments() error {
+	write, err := e.jsonl(fileComments)
+	if err != nil {
+		return err
+	}
+
+	var comments []model.Comment
+	return e.db.Order("id ASC").FindInBatches(&comments, exportBatchSize, func(tx *gorm.DB, batch int) error {
+		for _, c := range comments {
+			if err := write(CommentRecord{
+				ID:           c.ID,
+				CommentType:  c.CommentType,
+				TargetID:     c.TargetID,
+				PostID:       c.PostID,
+				LifeRecordID: c.LifeRecordID,
+				ParentID:     c.ParentID,
+				ReplyToID:    c.ReplyToID,
+				Nickname:     c.Nickname,
+				Email:        c.Email,
+				Avatar:       c.Avatar,
+				Website:      c.Website,
+				Content:      c.Content,
+				IsAdmin:      c.IsAdmin,
+				IsPinned:     c.IsPinned,
+				Status:       c.Status,
+				IPAddress:    c.IPAddress,
+				UserAgent:    c.UserAgent,
+				SpamScore:    c.SpamScore,
+				SpamReasons:  c.SpamReasons,
+				EditedAt:     c.EditedAt,
+				Withdrawn:    c.Withdrawn,
+				CreatedAt:    c.CreatedAt,
+				UpdatedAt:    c.UpdatedAt,
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}).Error
+}
+
+// bills 导出账单
+func (e *exporter) bills() error {
+	write, err := e.jsonl(fileBills)
+	if err != nil {
+		return err
+	}
+
+	var bills []model.Bill
+	return e.db.Order("id ASC").FindInBatches(&bills, exportBatchSize, func(tx *gorm.DB, batch int) error {
+		for _, b := range bills {
+			if err := write(BillRecord{
+				ID:         b.ID,
+				Type:       b.Type,
+				CategoryID: b.CategoryID,
+				Amount:     b.Amount,
+				Desc:       b.Desc,
+				Date:       b.Date.Format(billDateLayout),
+				PeriodType: b.PeriodType,
+				IsConsumed: b.IsConsumed,
+				Refund:     b.Refund,
+				RefundType: b.RefundType,
+				CreatedAt:  b.CreatedAt,
+				UpdatedAt:  b.UpdatedAt,
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}).Error
+}
+
+// series 导出系列及成员顺序
+func (e *exporter) series() error {
+	write, err := e.jsonl(fileSeries)
+	if err != nil {
+		return err
+	}
+
+	var list []model.Series
+	if err := e.db.Order("id ASC").Find(&list).Error; err != nil {
+		return err
+	}
+	for _, s := range list {
+		var postIDs []uint
+		if err := e.db.Model(&model.SeriesPost{}).
+			Where("series_id = ?", s.ID).
+			Order("position ASC").
+			Pluck("post_id", &postIDs).Error; err != nil {
+			return err
+		}
+		if err := write(SeriesRecord{
+			ID:          s.ID,
+			Name:        s.Name,
+			Slug:        s.Slug,
+			Description: s.Description,
+			CoverImage:  s.CoverImage,
+			CreatedAt:   s.CreatedAt,
+			UpdatedAt:   s.UpdatedAt,
+			PostIDs:     postIDs,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// translations 导出文章翻译
+func (e *exporter) translations() error {
+	write, err := e.jsonl(fileTranslations)
+	if err != nil {
+		return err
+	}
+
+	var list []model.PostTranslation
+	return e.db.Order("id ASC").FindInBatches(&list, exportBatchSize, func(tx *gorm.DB, batch int) error {
+		for _, t := range list {
+			if err := write(TranslationRecord{
+				PostID:    t.PostID,
+				Locale:    t.Locale,
+				Title:     t.Title,
+				Slug:      t.Slug,
+				Content:   t.Content,
+				Excerpt:   t.Excerpt,
+				CreatedAt: t.CreatedAt,
+				UpdatedAt: t.UpdatedAt,
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}).Error
+}
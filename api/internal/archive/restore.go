@@ -0,0 +1,545 @@
+package archive
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"kuaiyu/internal/database"
+	"kuaiyu/internal/model"
+	"kuaiyu/internal/repository"
+	"kuaiyu/pkg/utils"
+)
+
+// ===========================================
+// 恢复
+// ===========================================
+
+// idMap 旧 ID -> 新 ID
+type idMap map[uint]uint
+
+// restorer 单次恢复的状态
+type restorer struct {
+	tx     *gorm.DB
+	files  map[string]*zip.File
+	report *RestoreReport
+
+	users      idMap
+	categories idMap
+	tags       idMap
+	posts      idMap
+	life       idMap
+	comments   idMap
+
+	defaultAuthor uint // 归档中作者缺失时使用的用户
+}
+
+// Restore 将归档恢复到空数据库，所有数据在同一事务中写入
+func Restore(r io.ReaderAt, size int64) (*RestoreReport, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("无效的归档文件: %w", err)
+	}
+
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	manifest, err := readManifest(files)
+	if err != nil {
+		return nil, err
+	}
+
+	db := database.Get()
+	if err := checkEmpty(db); err != nil {
+		return nil, err
+	}
+
+	report := &RestoreReport{
+		Version:  manifest.Version,
+		Restored: make(map[string]int),
+		Skipped:  make(map[string]int),
+		Warnings: []string{},
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		rs := &restorer{
+			tx:         tx,
+			files:      files,
+			report:     report,
+			users:      idMap{},
+			categories: idMap{},
+			tags:       idMap{},
+			posts:      idMap{},
+			life:       idMap{},
+			comments:   idMap{},
+		}
+
+		steps := []func() error{
+			rs.restoreUsers,
+			rs.restoreCategories,
+			rs.restoreTags,
+			rs.restorePosts,
+			rs.restoreLife,
+			rs.restoreComments,
+			rs.restoreBills,
+			rs.restoreSeries,
+			rs.restoreTranslations,
+		}
+		for _, step := range steps {
+			if err := step(); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// readManifest 读取并校验归档清单
+func readManifest(files map[string]*zip.File) (*Manifest, error) {
+	f, ok := files[fileManifest]
+	if !ok {
+		return nil, errors.New("归档缺少 manifest.json")
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	var manifest Manifest
+	if err := json.NewDecoder(rc).Decode(&manifest); err != nil {
+		return nil, fmt.Errorf("manifest.json 解析失败: %w", err)
+	}
+	if manifest.Format != Format {
+		return nil, fmt.Errorf("不支持的归档格式: %q", manifest.Format)
+	}
+	if manifest.Version < 1 || manifest.Version > Version {
+		return nil, fmt.Errorf("不支持的归档版本: %d（当前支持 %d）", manifest.Version, Version)
+	}
+	return &manifest, nil
+}
+
+// checkEmpty 检查内容表是否为空（包括软删除的记录，避免唯一索引冲突）
+func checkEmpty(db *gorm.DB) error {
+	tables := []interface{}{
+		&model.Post{},
+		&model.LifeRecord{},
+		&model.Comment{},
+		&model.Tag{},
+		&model.Bill{},
+		&model.Category{},
+		&model.Series{},
+		&model.PostTranslation{},
+	}
+	for _, table := range tables {
+		var count int64
+		if err := db.Unscoped().Model(table).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrNotEmpty
+		}
+	}
+	return nil
+}
+
+// each 逐条解码 JSON Lines 文件，文件不存在时跳过（兼容旧版本归档）
+func each[T any](rs *restorer, name string, fn func(rec *T) error) error {
+	f, ok := rs.files[name]
+	if !ok {
+		return nil
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	dec := json.NewDecoder(rc)
+	for line := 1; ; line++ {
+		var rec T
+		if err := dec.Decode(&rec); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("%s 第 %d 条记录解析失败: %w", name, line, err)
+		}
+		if err := fn(&rec); err != nil {
+			return fmt.Errorf("%s 第 %d 条记录写入失败: %w", name, line, err)
+		}
+	}
+}
+
+// restored 记录恢复数量
+func (rs *restorer) restored(file string) {
+	rs.report.Restored[strings.TrimSuffix(file, ".jsonl")]++
+}
+
+// skipped 记录跳过数量
+func (rs *restorer) skipped(file string) {
+	rs.report.Skipped[strings.TrimSuffix(file, ".jsonl")]++
+}
+
+// remap 映射可空外键；原值非空但找不到映射时返回 false
+func remap(id *uint, m idMap) (*uint, bool) {
+	if id == nil {
+		return nil, true
+	}
+	newID, ok := m[*id]
+	if !ok {
+		return nil, false
+	}
+	return &newID, true
+}
+
+// ===========================================
+// 各类数据
+// ===========================================
+
+// restoreUsers 按用户名匹配已有用户并更新资料，不存在时新建
+// 归档不含密码，新建的用户使用随机密码
+func (rs *restorer) restoreUsers() error {
+	err := each(rs, fileUsers, func(rec *UserRecord) error {
+		var user model.User
+		err := rs.tx.Where("username = ?", rec.Username).First(&user).Error
+		switch {
+		case err == nil:
+			if err := rs.tx.Model(&user).Updates(map[string]interface{}{
+				"email":  rec.Email,
+				"avatar": rec.Avatar,
+			}).Error; err != nil {
+				return err
+			}
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			hashed, err := utils.HashPassword(utils.GenerateRandomString(32))
+			if err != nil {
+				return err
+			}
+			user = model.User{
+				Username: rec.Username,
+				Password: hashed,
+				Email:    rec.Email,
+				Avatar:   rec.Avatar,
+			}
+			user.CreatedAt = rec.CreatedAt
+			if err := rs.tx.Create(&user).Error; err != nil {
+				return err
+			}
+			rs.report.Warnings = append(rs.report.Warnings,
+				fmt.Sprintf("已新建用户 %s（随机密码），需要登录时请重置密码", rec.Username))
+		default:
+			return err
+		}
+
+		rs.users[rec.ID] = user.ID
+		rs.restored(fileUsers)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// 作者缺失时归到第一个用户
+	var first model.User
+	if err := rs.tx.Order("id ASC").First(&first).Error; err == nil {
+		rs.defaultAuthor = first.ID
+	}
+	return nil
+}
+
+// author 映射作者 ID
+func (rs *restorer) author(id uint) uint {
+	if newID, ok := rs.users[id]; ok {
+		return newID
+	}
+	return rs.defaultAuthor
+}
+
+// restoreCategories 恢复账单分类
+func (rs *restorer) restoreCategories() error {
+	return each(rs, fileCategories, func(rec *CategoryRecord) error {
+		category := model.Category{
+			Name:      rec.Name,
+			Key:       rec.Key,
+			Type:      rec.Type,
+			CreatedAt: rec.CreatedAt,
+		}
+		if err := rs.tx.Create(&category).Error; err != nil {
+			return err
+		}
+		rs.categories[rec.ID] = category.ID
+		rs.restored(fileCategories)
+		return nil
+	})
+}
+
+// restoreTags 恢复标签
+func (rs *restorer) restoreTags() error {
+	return each(rs, fileTags, func(rec *TagRecord) error {
+		tag := model.Tag{
+			Name:        rec.Name,
+			Slug:        rec.Slug,
+			Description: rec.Description,
+			Color:       rec.Color,
+			CreatedAt:   rec.CreatedAt,
+		}
+		if err := rs.tx.Create(&tag).Error; err != nil {
+			return err
+		}
+		rs.tags[rec.ID] = tag.ID
+		rs.restored(fileTags)
+		return nil
+	})
+}
+
+// restorePosts 恢复文章及标签关联
+func (rs *restorer) restorePosts() error {
+	return each(rs, filePosts, func(rec *PostRecord) error {
+		post := model.Post{
+			Title:        rec.Title,
+			Slug:         rec.Slug,
+			Content:      rec.Content,
+			Excerpt:      rec.Excerpt,
+			CoverImage:   rec.CoverImage,
+			Status:       rec.Status,
+			Visibility:   rec.Visibility,
+			PasswordHash: rec.PasswordHash,
+			PinOrder:     rec.PinOrder,
+			PinnedUntil:  rec.PinnedUntil,
+			FeatureOrder: rec.FeatureOrder,
+			ViewCount:    rec.ViewCount,
+			AuthorID:     rs.author(rec.AuthorID),
+			PublishedAt:  rec.PublishedAt,
+		}
+		post.CreatedAt = rec.CreatedAt
+		post.UpdatedAt = rec.UpdatedAt
+		if err := rs.tx.Omit("Tags", "Author", "Comments").Create(&post).Error; err != nil {
+			return err
+		}
+		rs.posts[rec.ID] = post.ID
+
+		var links []model.PostTag
+		for _, tagID := range rec.TagIDs {
+			if newID, ok := rs.tags[tagID]; ok {
+				links = append(links, model.PostTag{PostID: post.ID, TagID: newID})
+			}
+		}
+		if len(links) > 0 {
+			if err := rs.tx.Create(&links).Error; err != nil {
+				return err
+			}
+		}
+
+		rs.restored(filePosts)
+		return nil
+	})
+}
+
+// restoreLife 恢复生活记录
+func (rs *restorer) restoreLife() error {
+	return each(rs, fileLife, func(rec *LifeRecord) error {
+		record := model.LifeRecord{
+			Title:       rec.Title,
+			Content:     rec.Content,
+			CoverImage:  rec.CoverImage,
+			Status:      rec.Status,
+			ViewCount:   rec.ViewCount,
+			AuthorID:    rs.author(rec.AuthorID),
+			PublishedAt: rec.PublishedAt,
+		}
+		record.CreatedAt = rec.CreatedAt
+		record.UpdatedAt = rec.UpdatedAt
+		if err := rs.tx.Omit("Author", "Comments").Create(&record).Error; err != nil {
+			return err
+		}
+		rs.life[rec.ID] = record.ID
+		rs.restored(fileLife)
+		return nil
+	})
+}
+
+// restoreComments 恢复评论，按评论类型映射目标 ID
+// 目标或父评论缺失的评论会被跳过
+func (rs *restorer) restoreComments() error {
+	return each(rs, fileComments, func(rec *CommentRecord) error {
+		targets := idMap{}
+		switch rec.CommentType {
+		case "post":
+			targets = rs.posts
+		case "life":
+			targets = rs.life
+		}
+
+		targetID, ok1 := remap(rec.TargetID, targets)
+		postID, ok2 := remap(rec.PostID, rs.posts)
+		lifeID, ok3 := remap(rec.LifeRecordID, rs.life)
+		parentID, ok4 := remap(rec.ParentID, rs.comments)
+		if !ok1 || !ok2 || !ok3 || !ok4 {
+			rs.skipped(fileComments)
+			return nil
+		}
+		// 被回复的评论缺失时退化为直接回复父评论
+		replyToID, _ := remap(rec.ReplyToID, rs.comments)
+
+		comment := model.Comment{
+			CommentType:  rec.CommentType,
+			TargetID:     targetID,
+			PostID:       postID,
+			LifeRecordID: lifeID,
+			ParentID:     parentID,
+			ReplyToID:    replyToID,
+			Nickname:     rec.Nickname,
+			Email:        rec.Email,
+			Avatar:       rec.Avatar,
+			Website:      rec.Website,
+			Content:      rec.Content,
+			IsAdmin:      rec.IsAdmin,
+			IsPinned:     rec.IsPinned,
+			Status:       rec.Status,
+			IPAddress:    rec.IPAddress,
+			UserAgent:    rec.UserAgent,
+			SpamScore:    rec.SpamScore,
+			SpamReasons:  rec.SpamReasons,
+			EditedAt:     rec.EditedAt,
+			Withdrawn:    rec.Withdrawn,
+		}
+		comment.CreatedAt = rec.CreatedAt
+		comment.UpdatedAt = rec.UpdatedAt
+		if err := rs.tx.Omit("Parent", "Replies").Create(&comment).Error; err != nil {
+			return err
+		}
+
+		// 按被回复的评论重建主题结构（旧版归档的回复都挂在顶层评论下）
+		var parent *model.Comment
+		for _, id := range []*uint{replyToID, parentID} {
+			var p model.Comment
+			if id != nil && rs.tx.First(&p, *id).Error == nil && p.CanReply() {
+				parent = &p
+				break
+			}
+		}
+		if err := repository.SaveThread(rs.tx, &comment, parent); err != nil {
+			return err
+		}
+		rs.comments[rec.ID] = comment.ID
+		rs.restored(fileComments)
+		return nil
+	})
+}
+
+// restoreBills 恢复账单，分类缺失的账单会被跳过
+func (rs *restorer) restoreBills() error {
+	return each(rs, fileBills, func(rec *BillRecord) error {
+		categoryID, ok := rs.categories[rec.CategoryID]
+		if !ok {
+			rs.skipped(fileBills)
+			return nil
+		}
+		date, err := time.ParseInLocation(billDateLayout, rec.Date, time.Local)
+		if err != nil {
+			return fmt.Errorf("无效的账单日期 %q", rec.Date)
+		}
+
+		bill := model.Bill{
+			Type:       rec.Type,
+			CategoryID: categoryID,
+			Amount:     rec.Amount,
+			Desc:       rec.Desc,
+			Date:       date,
+			PeriodType: rec.PeriodType,
+			IsConsumed: rec.IsConsumed,
+			Refund:     rec.Refund,
+			RefundType: rec.RefundType,
+		}
+		bill.CreatedAt = rec.CreatedAt
+		bill.UpdatedAt = rec.UpdatedAt
+
+		if err := rs.tx.Omit("Category").Create(&bill).Error; err != nil {
+			return err
+		}
+		// is_consumed 的数据库默认值为 true，零值不会随 Create 写入
+		if !rec.IsConsumed {
+			if err := rs.tx.Model(&bill).Update("is_consumed", false).Error; err != nil {
+				return err
+			}
+		}
+		rs.restored(fileBills)
+		return nil
+	})
+}
+
+// restoreSeries 恢复系列及成员顺序
+func (rs *restorer) restoreSeries() error {
+	return each(rs, fileSeries, func(rec *SeriesRecord) error {
+		series := model.Series{
+			Name:        rec.Name,
+			Slug:        rec.Slug,
+			Description: rec.Description,
+			CoverImage:  rec.CoverImage,
+			CreatedAt:   rec.CreatedAt,
+			UpdatedAt:   rec.UpdatedAt,
+		}
+		if err := rs.tx.Create(&series).Error; err != nil {
+			return err
+		}
+
+		var members []model.SeriesPost
+		for _, postID := range rec.PostIDs {
+			if newID, ok := rs.posts[postID]; ok {
+				members = append(members, model.SeriesPost{
+					SeriesID: series.ID,
+					PostID:   newID,
+					Position: len(members) + 1,
+				})
+			}
+		}
+		if len(members) > 0 {
+			if err := rs.tx.Create(&members).Error; err != nil {
+				return err
+			}
+		}
+
+		rs.restored(fileSeries)
+		return nil
+	})
+}
+
+// restoreTranslations 恢复文章翻译
+func (rs *restorer) restoreTranslations() error {
+	return each(rs, fileTranslations, func(rec *TranslationRecord) error {
+		postID, ok := rs.posts[rec.PostID]
+		if !ok {
+			rs.skipped(fileTranslations)
+			return nil
+		}
+
+		translation := model.PostTranslation{
+			PostID:    postID,
+			Locale:    rec.Locale,
+			Title:     rec.Title,
+			Slug:      rec.Slug,
+			Content:   rec.Content,
+			Excerpt:   rec.Excerpt,
+			CreatedAt: rec.CreatedAt,
+			UpdatedAt: rec.UpdatedAt,
+		}
+		if err := rs.tx.Create(&translation).Error; err != nil {
+			return err
+		}
+
+		rs.restored(fileTranslations)
+		return nil
+	})
+}
@@ -0,0 +1,195 @@
+// Package challenge 评论工作量证明
+// 提交评论前客户端先获取签名的题目，找到使 SHA-256(题目 + nonce) 具有足够前导零位的 nonce 后随评论提交。
+// 题目有效期内只能使用一次；难度随最近的垃圾评论数量自动提高，使轮换 IP 的批量提交代价随之增加
+package challenge
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"math/bits"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"gorm.io/gorm"
+	"kuaiyu/internal/config"
+	"kuaiyu/internal/database"
+	"kuaiyu/internal/model"
+	"kuaiyu/pkg/constants"
+)
+
+// ===========================================
+// 题目
+// ===========================================
+
+// audience 题目令牌的受众标识
+const audience = "challenge"
+
+// maxNonceLength nonce 的最大长度
+const maxNonceLength = 64
+
+var (
+	// ErrInvalid 题目无效或已过期
+	ErrInvalid = errors.New("invalid challenge")
+	// ErrUnsolved nonce 不满足难度要求
+	ErrUnsolved = errors.New("challenge not solved")
+	// ErrUsed 题目已使用
+	ErrUsed = errors.New("challenge already used")
+)
+
+// Claims 题目令牌声明
+type Claims struct {
+	Difficulty int `json:"difficulty"`
+	jwt.RegisteredClaims
+}
+
+// Issue 生成题目
+func Issue() (*model.ChallengeVO, error) {
+	cfg := config.Get()
+	id, err := randomID()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(cfg.Challenge.TTL)
+	difficulty := Difficulty()
+	claims := Claims{
+		Difficulty: difficulty,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        id,
+			Audience:  jwt.ClaimStrings{audience},
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(now),
+			Issuer:    cfg.JWT.Issuer,
+		},
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(signingKey())
+	if err != nil {
+		return nil, err
+	}
+	return &model.ChallengeVO{
+		Challenge:  token,
+		Algorithm:  model.ChallengeAlgorithm,
+		Difficulty: difficulty,
+		ExpiresAt:  expiresAt,
+	}, nil
+}
+
+// Redeem 校验题目的解并将题目标记为已使用
+func Redeem(challenge, nonce string) error {
+	claims, err := parse(challenge)
+	if err != nil {
+		return ErrInvalid
+	}
+	if nonce == "" || len(nonce) > maxNonceLength || !Solved(challenge, nonce, claims.Difficulty) {
+		return ErrUnsolved
+	}
+
+	db := database.Get()
+	now := time.Now()
+	db.Where("expires_at < ?", now).Delete(&model.ChallengeRedemption{})
+
+	redemption := model.ChallengeRedemption{
+		ID:        claims.ID,
+		ExpiresAt: claims.ExpiresAt.Time,
+	}
+	if err := db.Create(&redemption).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) || exists(claims.ID) {
+			return ErrUsed
+		}
+		return err
+	}
+	return nil
+}
+
+// Solved 判断 SHA-256(challenge + nonce) 是否至少有 difficulty 个前导零位
+func Solved(challenge, nonce string, difficulty int) bool {
+	sum := sha256.Sum256([]byte(challenge + nonce))
+	zeros := 0
+	for _, b := range sum {
+		if b != 0 {
+			zeros += bits.LeadingZeros8(b)
+			break
+		}
+		zeros += 8
+	}
+	return zeros >= difficulty
+}
+
+// parse 解析并校验题目令牌
+func parse(challenge string) (*Claims, error) {
+	token, err := jwt.ParseWithClaims(challenge, &Claims{}, func(token *jwt.Token) (interface{}, error) {
+		return signingKey(), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Name}), jwt.WithAudience(audience), jwt.WithExpirationRequired())
+	if err != nil {
+		return nil, err
+	}
+
+	if claims, ok := token.Claims.(*Claims); ok && token.Valid && claims.ID != "" {
+		return claims, nil
+	}
+	return nil, jwt.ErrSignatureInvalid
+}
+
+// exists 题目是否已使用
+func exists(id string) bool {
+	var count int64
+	database.Get().Model(&model.ChallengeRedemption{}).Where("id = ?", id).Count(&count)
+	return count > 0
+}
+
+// signingKey 题目令牌签名密钥
+// 由 JWT 密钥派生，与登录令牌、预览令牌和解锁令牌互不通用
+func signingKey() []byte {
+	return []byte(config.Get().JWT.Secret + ":" + audience)
+}
+
+func randomID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// ===========================================
+// 难度
+// ===========================================
+
+// difficultyTTL 难度的缓存时间
+const difficultyTTL = time.Minute
+
+var (
+	difficultyMu        sync.Mutex
+	cachedDifficulty    int
+	difficultyExpiresAt time.Time
+)
+
+// Difficulty 当前难度：基础难度加上最近垃圾评论数量带来的增量
+func Difficulty() int {
+	difficultyMu.Lock()
+	defer difficultyMu.Unlock()
+
+	now := time.Now()
+	if now.Before(difficultyExpiresAt) {
+		return cachedDifficulty
+	}
+
+	cfg := config.Get().Challenge
+	var spamCount int64
+	database.Get().Model(&model.Comment{}).
+		Where("status = ? AND created_at >= ?", constants.CommentStatusSpam, now.Add(-cfg.Window)).
+		Count(&spamCount)
+
+	difficulty := cfg.Difficulty
+	if cfg.SpamStep > 0 {
+		difficulty += int(spamCount) / cfg.SpamStep
+	}
+	cachedDifficulty = max(min(difficulty, cfg.MaxDifficulty), 0)
+	difficultyExpiresAt = now.Add(difficultyTTL)
+	return cachedDifficulty
+}
@@ -5,6 +5,7 @@ package config
 import (
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -16,8 +17,16 @@ import (
 type Config struct {
 	Server   ServerConfig
 	Database DatabaseConfig
-	JWT      JWTConfig
-	COS      COSConfig
+	JWT       JWTConfig
+	COS       COSConfig
+	Scheduler SchedulerConfig
+	Reaction  ReactionConfig
+	Site      SiteConfig
+	Ping      PingConfig
+	Mail      MailConfig
+	Spam      SpamConfig
+	Challenge ChallengeConfig
+	Comment   CommentConfig
 }
 
 // ServerConfig 服务器配置
@@ -57,6 +66,98 @@ type COSConfig struct {
 	ProxyURL  string // 代理 URL，格式：http://127.0.0.1:7890 或 socks5://127.0.0.1:7891
 }
 
+// SchedulerConfig 后台调度配置
+type SchedulerConfig struct {
+	Enabled         bool
+	PublishInterval time.Duration // 定时发布扫描间隔
+}
+
+// SiteConfig 站点信息（订阅源、站点地图等对外输出使用）
+type SiteConfig struct {
+	Name        string
+	URL         string // 站点地址，不含结尾的 /
+	Description string
+	Language    string
+	Image       string // 默认分享图，内容没有封面时使用
+	Twitter     string // Twitter 账号，如 @example
+	// RobotsDisallow robots.txt 中禁止爬取的路径
+	RobotsDisallow []string
+}
+
+// PingConfig 搜索引擎推送配置
+// 内容发布、更新或删除后通过 IndexNow 提交地址，并请求 sitemap ping 地址
+type PingConfig struct {
+	Enabled           bool
+	IndexNowKey       string        // IndexNow 密钥，为空时不使用 IndexNow
+	IndexNowEndpoints []string      // IndexNow 提交地址
+	SitemapEndpoints  []string      // sitemap ping 地址，站点地图地址作为 sitemap 参数附加
+	MaxAttempts       int           // 最多发送次数（含首次）
+	RetryDelay        time.Duration // 首次重试的等待时间，之后按指数退避
+	Timeout           time.Duration // 单次请求超时
+	Interval          time.Duration // 后台队列扫描间隔
+}
+
+// MailConfig 邮件通知配置
+type MailConfig struct {
+	Enabled        bool
+	Driver         string // smtp | file | log，file 将邮件写入目录，log 只输出日志，用于开发环境
+	Host           string
+	Port           int
+	Username       string
+	Password       string
+	TLS            string        // starttls | tls | none
+	From           string        // 发件人，如 Yu.kuai <noreply@example.com>
+	FileDir        string        // file 驱动的输出目录
+	OwnerEmail     string        // 站长邮箱，接收新评论通知，为空时不通知
+	Secret         string        // 退订链接签名密钥
+	UnsubscribeTTL time.Duration // 退订链接有效期
+	MaxAttempts    int           // 最多发送次数（含首次）
+	RetryDelay     time.Duration // 首次重试的等待时间，之后按指数退避
+	Timeout        time.Duration // 单次发送超时
+	Interval       time.Duration // 发件箱扫描间隔
+}
+
+// SpamConfig 评论反垃圾配置
+// 每项检查给出分数，总分达到 PendingScore 进入待审核，达到 SpamScore 直接标记为垃圾评论
+type SpamConfig struct {
+	Enabled           bool // 关闭时只保留首次评论需审核的规则
+	PendingScore      float64
+	SpamScore         float64
+	FirstCommentScore float64  // 首次评论（邮箱没有通过审核的评论）
+	Keywords          []string // 敏感词
+	KeywordsFile      string   // 敏感词文件，每行一个
+	KeywordScore      float64  // 每个命中的敏感词
+	MaxLinks          int      // 允许的链接数
+	LinkScore         float64  // 超出部分每个链接
+	HoneypotScore     float64  // 填写了隐藏字段
+	MinSubmitTime     time.Duration
+	TimingScore       float64 // 表单打开后提交过快
+	BayesMinSamples   int     // 垃圾和正常评论各至少有多少条训练样本才启用贝叶斯分类
+	BayesWeight       float64 // 贝叶斯分类的最大分数，判为正常评论时为负分
+}
+
+// CommentConfig 评论配置
+type CommentConfig struct {
+	EditWindow time.Duration // 评论作者发布后可修改内容的时长，删除不受限制
+}
+
+// ChallengeConfig 评论工作量证明配置
+// 难度为哈希前导零的位数，每增加一位计算量翻倍；
+// 最近 Window 内每出现 SpamStep 条垃圾评论，难度加一位，不超过 MaxDifficulty
+type ChallengeConfig struct {
+	Enabled       bool
+	Difficulty    int // 基础难度
+	MaxDifficulty int
+	SpamStep      int
+	Window        time.Duration
+	TTL           time.Duration // 题目有效期
+}
+
+// ReactionConfig 读者表态配置
+type ReactionConfig struct {
+	Emojis []string // 可用的表情，顺序即展示顺序
+}
+
 // ===========================================
 // 全局配置实例
 // ===========================================
@@ -103,6 +204,79 @@ func Load() *Config {
 			BaseURL:   getEnv("COS_BASE_URL", ""),
 			ProxyURL:  getEnv("COS_PROXY_URL", ""), // 支持从环境变量读取代理
 		},
+		Scheduler: SchedulerConfig{
+			Enabled:         getBoolEnv("SCHEDULER_ENABLED", true),
+			PublishInterval: getDurationEnv("SCHEDULER_PUBLISH_INTERVAL", time.Minute),
+		},
+		Site: SiteConfig{
+			Name:           getEnv("SITE_NAME", "Yu.kuai"),
+			URL:            strings.TrimRight(getEnv("SITE_URL", "https://kcat.site"), "/"),
+			Description:    getEnv("SITE_DESCRIPTION", "Yu.kuai 的博客"),
+			Language:       getEnv("SITE_LANGUAGE", "zh-CN"),
+			Image:          getEnv("SITE_IMAGE", ""),
+			Twitter:        getEnv("SITE_TWITTER", ""),
+			RobotsDisallow: getListEnv("ROBOTS_DISALLOW", []string{"/admin/", "/api/admin/"}),
+		},
+		Ping: PingConfig{
+			Enabled:           getBoolEnv("PING_ENABLED", false),
+			IndexNowKey:       getEnv("INDEXNOW_KEY", ""),
+			IndexNowEndpoints: getListEnv("INDEXNOW_ENDPOINTS", []string{"https://api.indexnow.org/indexnow"}),
+			SitemapEndpoints:  getListEnv("SITEMAP_PING_ENDPOINTS", nil),
+			MaxAttempts:       getIntEnv("PING_MAX_ATTEMPTS", 5),
+			RetryDelay:        getDurationEnv("PING_RETRY_DELAY", time.Minute),
+			Timeout:           getDurationEnv("PING_TIMEOUT", 10*time.Second),
+			Interval:          getDurationEnv("PING_INTERVAL", 30*time.Second),
+		},
+		Mail: MailConfig{
+			Enabled:        getBoolEnv("MAIL_ENABLED", false),
+			Driver:         getEnv("MAIL_DRIVER", "log"),
+			Host:           getEnv("MAIL_HOST", ""),
+			Port:           getIntEnv("MAIL_PORT", 587),
+			Username:       getEnv("MAIL_USERNAME", ""),
+			Password:       getEnv("MAIL_PASSWORD", ""),
+			TLS:            getEnv("MAIL_TLS", "starttls"),
+			From:           getEnv("MAIL_FROM", ""),
+			FileDir:        getEnv("MAIL_FILE_DIR", "./mail"),
+			OwnerEmail:     getEnv("MAIL_OWNER_EMAIL", ""),
+			Secret:         getEnv("MAIL_SECRET", getEnv("JWT_SECRET", "kuaiyu_jwt_secret")),
+			UnsubscribeTTL: getDurationEnv("MAIL_UNSUBSCRIBE_TTL", 180*24*time.Hour),
+			MaxAttempts:    getIntEnv("MAIL_MAX_ATTEMPTS", 5),
+			RetryDelay:     getDurationEnv("MAIL_RETRY_DELAY", time.Minute),
+			Timeout:        getDurationEnv("MAIL_TIMEOUT", 15*time.Second),
+			Interval:       getDurationEnv("MAIL_INTERVAL", 30*time.Second),
+		},
+		Spam: SpamConfig{
+			Enabled:           getBoolEnv("SPAM_ENABLED", true),
+			PendingScore:      getFloatEnv("SPAM_PENDING_SCORE", 3),
+			SpamScore:         getFloatEnv("SPAM_SPAM_SCORE", 8),
+			FirstCommentScore: getFloatEnv("SPAM_FIRST_COMMENT_SCORE", 3),
+			Keywords: getListEnv("SPAM_KEYWORDS", []string{
+				"加微信", "加qq", "代开发票", "博彩", "六合彩", "网赚", "刷单", "兼职日结", "私服", "casino", "viagra",
+			}),
+			KeywordsFile:    getEnv("SPAM_KEYWORDS_FILE", ""),
+			KeywordScore:    getFloatEnv("SPAM_KEYWORD_SCORE", 4),
+			MaxLinks:        getIntEnv("SPAM_MAX_LINKS", 2),
+			LinkScore:       getFloatEnv("SPAM_LINK_SCORE", 2),
+			HoneypotScore:   getFloatEnv("SPAM_HONEYPOT_SCORE", 10),
+			MinSubmitTime:   getDurationEnv("SPAM_MIN_SUBMIT_TIME", 3*time.Second),
+			TimingScore:     getFloatEnv("SPAM_TIMING_SCORE", 4),
+			BayesMinSamples: getIntEnv("SPAM_BAYES_MIN_SAMPLES", 10),
+			BayesWeight:     getFloatEnv("SPAM_BAYES_WEIGHT", 6),
+		},
+		Challenge: ChallengeConfig{
+			Enabled:       getBoolEnv("CHALLENGE_ENABLED", false),
+			Difficulty:    getIntEnv("CHALLENGE_DIFFICULTY", 16),
+			MaxDifficulty: getIntEnv("CHALLENGE_MAX_DIFFICULTY", 22),
+			SpamStep:      getIntEnv("CHALLENGE_SPAM_STEP", 5),
+			Window:        getDurationEnv("CHALLENGE_WINDOW", time.Hour),
+			TTL:           getDurationEnv("CHALLENGE_TTL", 10*time.Minute),
+		},
+		Comment: CommentConfig{
+			EditWindow: getDurationEnv("COMMENT_EDIT_WINDOW", 15*time.Minute),
+		},
+		Reaction: ReactionConfig{
+			Emojis: getListEnv("REACTION_EMOJIS", []string{"👍", "❤️", "🎉", "😄", "🤔", "👀"}),
+		},
 	}
 }
 
@@ -128,6 +302,16 @@ func getIntEnv(key string, defaultValue int) int {
 	return defaultValue
 }
 
+// getFloatEnv 获取浮点数环境变量
+func getFloatEnv(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatValue
+		}
+	}
+	return defaultValue
+}
+
 // getBoolEnv 获取布尔环境变量
 func getBoolEnv(key string, defaultValue bool) bool {
 	if value := os.Getenv(key); value != "" {
@@ -148,6 +332,25 @@ func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
 	return defaultValue
 }
 
+// getListEnv 获取逗号分隔的列表环境变量，忽略空项
+func getListEnv(key string, defaultValue []string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	if len(items) == 0 {
+		return defaultValue
+	}
+	return items
+}
+
 // ===========================================
 // 配置验证
 // ===========================================
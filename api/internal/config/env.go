@@ -0,0 +1,52 @@
+package config
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/joho/godotenv"
+)
+
+// ===========================================
+// 环境变量文件
+// ===========================================
+
+// envFiles 依次加载的环境变量文件，后加载的覆盖先加载的
+var envFiles = []string{".env", ".env.local"}
+
+// LoadEnvFiles 加载项目根目录下的 .env 和 .env.local
+// 项目根目录为从工作目录向上查找到的第一个包含 docker-compose.yml 的目录，找不到时使用工作目录。
+// 需在首次调用 Get 之前执行，否则已缓存的配置不会包含文件中的值
+func LoadEnvFiles() {
+	rootDir := projectRoot()
+	for _, envFile := range envFiles {
+		envPath := filepath.Join(rootDir, envFile)
+		if _, err := os.Stat(envPath); err != nil {
+			continue
+		}
+		// 使用 Overload 确保后面的文件可以覆盖前面的值
+		if err := godotenv.Overload(envPath); err != nil {
+			log.Printf("Warning: Failed to load %s: %v", envPath, err)
+		} else {
+			log.Printf("Loaded environment file: %s", envPath)
+		}
+	}
+}
+
+// projectRoot 向上查找包含 docker-compose.yml 的目录
+func projectRoot() string {
+	workDir, _ := os.Getwd()
+	dir := workDir
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "docker-compose.yml")); err == nil {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			// 已到达根目录，使用当前目录
+			return workDir
+		}
+		dir = parent
+	}
+}
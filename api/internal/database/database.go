@@ -25,6 +25,11 @@ func Get() *gorm.DB {
 	return db
 }
 
+// Use 使用已建立的数据库连接（测试使用）
+func Use(conn *gorm.DB) {
+	db = conn
+}
+
 // ===========================================
 // 数据库初始化
 // ===========================================
@@ -89,12 +94,37 @@ func Migrate() error {
 		&model.Comment{},
 		&model.PageView{},
 		&model.AnalyticsEvent{},
+		&model.PostRevision{},
+		&model.SearchDocument{},
+		&model.SearchTerm{},
+		&model.PreviewLink{},
+		&model.Series{},
+		&model.SeriesPost{},
+		&model.RelatedPost{},
+		&model.RelatedOverride{},
+		&model.RenderedContent{},
+		&model.SlugRedirect{},
+		&model.PostTranslation{},
+		&model.Reaction{},
+		&model.OGImage{},
+		&model.PingLog{},
+		&model.EmailOutbox{},
+		&model.EmailUnsubscribe{},
+		&model.SpamToken{},
+		&model.SpamTraining{},
+		&model.ChallengeRedemption{},
+		&model.CommentEdit{},
 	)
 	
 	if err != nil {
 		return fmt.Errorf("migration failed: %w", err)
 	}
 	
+	// 修正排序规则（AutoMigrate 不会修改已有列的排序规则）
+	if err := fixCollations(); err != nil {
+		return fmt.Errorf("failed to fix collations: %w", err)
+	}
+	
 	// 创建索引
 	if err := createIndexes(); err != nil {
 		return fmt.Errorf("failed to create indexes: %w", err)
@@ -105,6 +135,35 @@ func Migrate() error {
 	return nil
 }
 
+// fixCollations 修正需要按字节比较的列
+// 这些列在模型中声明了排序规则，但在此之前创建的表仍使用库的默认排序规则
+func fixCollations() error {
+	columns := []struct {
+		table      string
+		column     string
+		definition string
+		collation  string
+	}{
+		{"reactions", "emoji", "varchar(32) COLLATE " + model.ReactionEmojiCollation + " NOT NULL", model.ReactionEmojiCollation},
+	}
+
+	for _, col := range columns {
+		var collation string
+		if err := db.Raw("SELECT collation_name FROM information_schema.columns WHERE table_schema = DATABASE() AND table_name = ? AND column_name = ?", col.table, col.column).Scan(&collation).Error; err != nil {
+			return err
+		}
+		if collation == "" || collation == col.collation {
+			continue
+		}
+		if err := db.Exec(fmt.Sprintf("ALTER TABLE %s MODIFY %s %s", col.table, col.column, col.definition)).Error; err != nil {
+			return err
+		}
+		log.Printf("Changed collation of %s.%s from %s to %s", col.table, col.column, collation, col.collation)
+	}
+
+	return nil
+}
+
 // createIndexes 创建额外索引
 func createIndexes() error {
 	indexes := []struct {
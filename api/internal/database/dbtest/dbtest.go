@@ -0,0 +1,69 @@
+// Package dbtest 测试用数据库
+// 在进程内启动兼容 MySQL 协议的内存数据库，排序规则与生产环境一致（utf8mb4_unicode_ci），
+// 供需要真实 SQL 行为的测试使用
+package dbtest
+
+import (
+	"fmt"
+	"net"
+	"testing"
+
+	sqle "github.com/dolthub/go-mysql-server"
+	"github.com/dolthub/go-mysql-server/memory"
+	"github.com/dolthub/go-mysql-server/server"
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/sirupsen/logrus"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"kuaiyu/internal/database"
+)
+
+// dbName 测试库名
+const dbName = "kuaiyu_test"
+
+// Open 启动内存数据库并迁移给定模型，同时设为全局数据库实例
+// 测试结束时自动关闭
+func Open(t testing.TB, models ...interface{}) *gorm.DB {
+	t.Helper()
+	logrus.SetLevel(logrus.ErrorLevel)
+
+	provider := memory.NewDBProvider(memory.NewDatabase(dbName))
+	engine := sqle.NewDefault(provider)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+
+	srv, err := server.NewServer(server.Config{Protocol: "tcp", Address: addr}, engine, sql.NewContext, memory.NewSessionBuilder(provider), nil)
+	if err != nil {
+		t.Fatalf("start database: %v", err)
+	}
+	go srv.Start()
+	t.Cleanup(func() { srv.Close() })
+
+	dsn := fmt.Sprintf("root@tcp(%s)/%s?charset=utf8mb4&collation=utf8mb4_unicode_ci&parseTime=True&loc=Local", addr, dbName)
+	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{
+		Logger:                                   logger.Default.LogMode(logger.Silent),
+		DisableForeignKeyConstraintWhenMigrating: true,
+		// 内存数据库不支持 SAVEPOINT，事务内的分批写入等嵌套事务直接复用外层事务
+		DisableNestedTransaction: true,
+	})
+	if err != nil {
+		t.Fatalf("connect database: %v", err)
+	}
+	if err := db.Exec("ALTER DATABASE " + dbName + " COLLATE utf8mb4_unicode_ci").Error; err != nil {
+		t.Fatalf("set collation: %v", err)
+	}
+	if len(models) > 0 {
+		if err := db.AutoMigrate(models...); err != nil {
+			t.Fatalf("migrate: %v", err)
+		}
+	}
+
+	database.Use(db)
+	return db
+}
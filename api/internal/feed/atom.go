@@ -0,0 +1,109 @@
+package feed
+
+import (
+	"encoding/xml"
+	"strconv"
+	"time"
+)
+
+// ===========================================
+// Atom 1.0
+// ===========================================
+
+type atomFeed struct {
+	XMLName  xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Lang     string      `xml:"xml:lang,attr,omitempty"`
+	Title    string      `xml:"title"`
+	Subtitle string      `xml:"subtitle,omitempty"`
+	ID       string      `xml:"id"`
+	Updated  string      `xml:"updated"`
+	Links    []atomLink  `xml:"link"`
+	Author   *atomPerson `xml:"author"`
+	Entries  []atomEntry `xml:"entry"`
+}
+
+type atomEntry struct {
+	Title      string         `xml:"title"`
+	ID         string         `xml:"id"`
+	Links      []atomLink     `xml:"link"`
+	Published  string         `xml:"published,omitempty"`
+	Updated    string         `xml:"updated"`
+	Summary    *atomText      `xml:"summary"`
+	Content    *atomText      `xml:"content"`
+	Categories []atomCategory `xml:"category"`
+}
+
+type atomLink struct {
+	Href   string `xml:"href,attr"`
+	Rel    string `xml:"rel,attr,omitempty"`
+	Type   string `xml:"type,attr,omitempty"`
+	Length string `xml:"length,attr,omitempty"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+	URI  string `xml:"uri,omitempty"`
+}
+
+type atomText struct {
+	Type  string `xml:"type,attr,omitempty"`
+	Value string `xml:",chardata"`
+}
+
+type atomCategory struct {
+	Term   string `xml:"term,attr"`
+	Label  string `xml:"label,attr,omitempty"`
+	Scheme string `xml:"scheme,attr,omitempty"`
+}
+
+// Atom 输出 Atom 1.0，完整正文作为 type="html" 的 content
+func (f *Feed) Atom() ([]byte, error) {
+	doc := atomFeed{
+		Lang:     f.Language,
+		Title:    f.Title,
+		Subtitle: f.Description,
+		ID:       f.FeedURL,
+		Updated:  f.updated().Format(time.RFC3339),
+		Links: []atomLink{
+			{Href: f.SiteURL, Rel: "alternate", Type: "text/html"},
+			{Href: f.FeedURL, Rel: "self", Type: FormatAtom.mediaType()},
+		},
+		Author:  &atomPerson{Name: f.Author, URI: f.SiteURL},
+		Entries: make([]atomEntry, len(f.Items)),
+	}
+
+	for i, item := range f.Items {
+		entry := atomEntry{
+			Title:   item.Title,
+			ID:      item.ID,
+			Links:   []atomLink{{Href: item.Link, Rel: "alternate", Type: "text/html"}},
+			Updated: item.modified().Format(time.RFC3339),
+		}
+		if !item.Published.IsZero() {
+			entry.Published = item.Published.Format(time.RFC3339)
+		}
+		if item.Summary != "" {
+			entry.Summary = &atomText{Type: "text", Value: item.Summary}
+		}
+		if item.ContentHTML != "" {
+			entry.Content = &atomText{Type: "html", Value: item.ContentHTML}
+		}
+		for _, category := range item.Categories {
+			entry.Categories = append(entry.Categories, atomCategory{
+				Term:   category.Term,
+				Label:  category.Label,
+				Scheme: category.Domain,
+			})
+		}
+		if item.Enclosure != nil {
+			link := atomLink{Href: item.Enclosure.URL, Rel: "enclosure", Type: item.Enclosure.Type}
+			if item.Enclosure.Length > 0 {
+				link.Length = strconv.FormatInt(item.Enclosure.Length, 10)
+			}
+			entry.Links = append(entry.Links, link)
+		}
+		doc.Entries[i] = entry
+	}
+
+	return marshalXML(doc)
+}
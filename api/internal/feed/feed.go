@@ -0,0 +1,159 @@
+// Package feed 订阅源生成
+// 同一份 Feed 数据输出 RSS 2.0、Atom 1.0 和 JSON Feed 1.1，文本均由编码器转义
+package feed
+
+import (
+	"mime"
+	"path"
+	"strings"
+	"time"
+)
+
+// ===========================================
+// 输出格式
+// ===========================================
+
+// Format 订阅源格式
+type Format string
+
+const (
+	FormatRSS  Format = "rss"
+	FormatAtom Format = "atom"
+	FormatJSON Format = "json"
+)
+
+// ParseFormat 解析格式参数，不支持的格式返回 false
+func ParseFormat(s string) (Format, bool) {
+	switch Format(strings.ToLower(strings.TrimSpace(s))) {
+	case FormatRSS:
+		return FormatRSS, true
+	case FormatAtom:
+		return FormatAtom, true
+	case FormatJSON:
+		return FormatJSON, true
+	}
+	return "", false
+}
+
+// ContentType 格式对应的响应类型
+func (f Format) ContentType() string {
+	switch f {
+	case FormatAtom:
+		return "application/atom+xml; charset=utf-8"
+	case FormatJSON:
+		return "application/feed+json; charset=utf-8"
+	default:
+		return "application/rss+xml; charset=utf-8"
+	}
+}
+
+// mediaType 不含字符集参数的媒体类型
+func (f Format) mediaType() string {
+	mediaType, _, _ := strings.Cut(f.ContentType(), ";")
+	return mediaType
+}
+
+// ===========================================
+// 订阅源数据
+// ===========================================
+
+// Feed 订阅源
+type Feed struct {
+	Title       string
+	Description string
+	Language    string
+	SiteURL     string // 站点首页
+	FeedURL     string // 订阅源自身地址
+	Author      string
+	Updated     time.Time // 为空时取条目的最新更新时间
+	Items       []Item
+}
+
+// Item 订阅源条目
+type Item struct {
+	ID          string // 全局唯一标识，通常为永久链接
+	Title       string
+	Link        string
+	Summary     string // 纯文本摘要
+	ContentHTML string // 完整正文 HTML，为空时只输出摘要
+	Published   time.Time
+	Updated     time.Time
+	Categories  []Category
+	Enclosure   *Enclosure
+}
+
+// Category 条目分类
+type Category struct {
+	Term   string // 分类标识，如标签 slug
+	Label  string // 展示名称
+	Domain string // 分类体系地址，如 https://example.com/category/
+}
+
+// Enclosure 条目附件（封面图等）
+type Enclosure struct {
+	URL    string
+	Type   string
+	Length int64 // 未知时为 0
+}
+
+// NewImageEnclosure 根据图片地址创建附件，类型按扩展名推断
+func NewImageEnclosure(url string) *Enclosure {
+	if url == "" {
+		return nil
+	}
+
+	ext := strings.ToLower(path.Ext(strings.SplitN(url, "?", 2)[0]))
+	contentType := mime.TypeByExtension(ext)
+	if !strings.HasPrefix(contentType, "image/") {
+		contentType = "image/jpeg"
+	}
+	return &Enclosure{URL: url, Type: contentType}
+}
+
+// ===========================================
+// 输出
+// ===========================================
+
+// Render 按指定格式输出订阅源
+func (f *Feed) Render(format Format) ([]byte, error) {
+	switch format {
+	case FormatAtom:
+		return f.Atom()
+	case FormatJSON:
+		return f.JSON()
+	default:
+		return f.RSS()
+	}
+}
+
+// LastModified 订阅源内容的最后修改时间，没有条目时返回零值
+// 用于条件请求的 Last-Modified 响应头
+func (f *Feed) LastModified() time.Time {
+	if !f.Updated.IsZero() {
+		return f.Updated
+	}
+
+	var latest time.Time
+	for _, item := range f.Items {
+		if t := item.modified(); t.After(latest) {
+			latest = t
+		}
+	}
+	return latest
+}
+
+// updated 订阅源的更新时间，没有条目时取当前时间
+func (f *Feed) updated() time.Time {
+	if latest := f.LastModified(); !latest.IsZero() {
+		return latest
+	}
+	return time.Now()
+}
+
+// modified 条目的最后修改时间
+func (i *Item) modified() time.Time {
+	if i.Updated.After(i.Published) {
+		return i.Updated
+	}
+	return i.Published
+}
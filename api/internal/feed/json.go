@@ -0,0 +1,106 @@
+package feed
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+)
+
+// ===========================================
+// JSON Feed 1.1
+// ===========================================
+
+// jsonFeedVersion JSON Feed 版本标识
+const jsonFeedVersion = "https://jsonfeed.org/version/1.1"
+
+type jsonFeed struct {
+	Version     string       `json:"version"`
+	Title       string       `json:"title"`
+	HomePageURL string       `json:"home_page_url,omitempty"`
+	FeedURL     string       `json:"feed_url,omitempty"`
+	Description string       `json:"description,omitempty"`
+	Language    string       `json:"language,omitempty"`
+	Authors     []jsonAuthor `json:"authors,omitempty"`
+	Items       []jsonItem   `json:"items"`
+}
+
+type jsonAuthor struct {
+	Name string `json:"name"`
+	URL  string `json:"url,omitempty"`
+}
+
+type jsonItem struct {
+	ID            string           `json:"id"`
+	URL           string           `json:"url,omitempty"`
+	Title         string           `json:"title,omitempty"`
+	ContentHTML   string           `json:"content_html,omitempty"`
+	ContentText   string           `json:"content_text,omitempty"`
+	Summary       string           `json:"summary,omitempty"`
+	Image         string           `json:"image,omitempty"`
+	DatePublished string           `json:"date_published,omitempty"`
+	DateModified  string           `json:"date_modified,omitempty"`
+	Tags          []string         `json:"tags,omitempty"`
+	Attachments   []jsonAttachment `json:"attachments,omitempty"`
+}
+
+type jsonAttachment struct {
+	URL         string `json:"url"`
+	MimeType    string `json:"mime_type"`
+	SizeInBytes int64  `json:"size_in_bytes,omitempty"`
+}
+
+// JSON 输出 JSON Feed 1.1
+// 规范要求条目包含 content_html 或 content_text，没有完整正文时以摘要作为 content_text
+func (f *Feed) JSON() ([]byte, error) {
+	doc := jsonFeed{
+		Version:     jsonFeedVersion,
+		Title:       f.Title,
+		HomePageURL: f.SiteURL,
+		FeedURL:     f.FeedURL,
+		Description: f.Description,
+		Language:    f.Language,
+		Items:       make([]jsonItem, len(f.Items)),
+	}
+	if f.Author != "" {
+		doc.Authors = []jsonAuthor{{Name: f.Author, URL: f.SiteURL}}
+	}
+
+	for i, item := range f.Items {
+		entry := jsonItem{
+			ID:          item.ID,
+			URL:         item.Link,
+			Title:       item.Title,
+			ContentHTML: item.ContentHTML,
+			Summary:     item.Summary,
+		}
+		if entry.ContentHTML == "" {
+			entry.ContentText = item.Summary
+		}
+		if !item.Published.IsZero() {
+			entry.DatePublished = item.Published.Format(time.RFC3339)
+		}
+		if !item.Updated.IsZero() {
+			entry.DateModified = item.Updated.Format(time.RFC3339)
+		}
+		for _, category := range item.Categories {
+			entry.Tags = append(entry.Tags, category.Label)
+		}
+		if item.Enclosure != nil {
+			entry.Image = item.Enclosure.URL
+			entry.Attachments = []jsonAttachment{{
+				URL:         item.Enclosure.URL,
+				MimeType:    item.Enclosure.Type,
+				SizeInBytes: item.Enclosure.Length,
+			}}
+		}
+		doc.Items[i] = entry
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(doc); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,121 @@
+package feed
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+// ===========================================
+// RSS 2.0
+// ===========================================
+
+type rssDocument struct {
+	XMLName   xml.Name   `xml:"rss"`
+	Version   string     `xml:"version,attr"`
+	AtomNS    string     `xml:"xmlns:atom,attr"`
+	ContentNS string     `xml:"xmlns:content,attr"`
+	Channel   rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string      `xml:"title"`
+	Link          string      `xml:"link"`
+	Description   string      `xml:"description"`
+	Language      string      `xml:"language,omitempty"`
+	LastBuildDate string      `xml:"lastBuildDate"`
+	AtomLink      rssAtomLink `xml:"atom:link"`
+	Items         []rssItem   `xml:"item"`
+}
+
+type rssAtomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+	Type string `xml:"type,attr"`
+}
+
+type rssItem struct {
+	Title       string        `xml:"title"`
+	Link        string        `xml:"link"`
+	Description string        `xml:"description"`
+	Content     *rssCDATA     `xml:"content:encoded,omitempty"`
+	PubDate     string        `xml:"pubDate,omitempty"`
+	GUID        rssGUID       `xml:"guid"`
+	Categories  []rssCategory `xml:"category"`
+	Enclosure   *rssEnclosure `xml:"enclosure"`
+}
+
+// rssCDATA 以 CDATA 输出的 HTML，编码器会拆分正文中的 ]]>
+type rssCDATA struct {
+	Value string `xml:",cdata"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+type rssCategory struct {
+	Domain string `xml:"domain,attr,omitempty"`
+	Value  string `xml:",chardata"`
+}
+
+type rssEnclosure struct {
+	URL    string `xml:"url,attr"`
+	Length int64  `xml:"length,attr"`
+	Type   string `xml:"type,attr"`
+}
+
+// RSS 输出 RSS 2.0，完整正文写入 content:encoded
+func (f *Feed) RSS() ([]byte, error) {
+	doc := rssDocument{
+		Version:   "2.0",
+		AtomNS:    "http://www.w3.org/2005/Atom",
+		ContentNS: "http://purl.org/rss/1.0/modules/content/",
+		Channel: rssChannel{
+			Title:         f.Title,
+			Link:          f.SiteURL,
+			Description:   f.Description,
+			Language:      f.Language,
+			LastBuildDate: f.updated().Format(time.RFC1123Z),
+			AtomLink:      rssAtomLink{Href: f.FeedURL, Rel: "self", Type: FormatRSS.mediaType()},
+			Items:         make([]rssItem, len(f.Items)),
+		},
+	}
+
+	for i, item := range f.Items {
+		entry := rssItem{
+			Title:       item.Title,
+			Link:        item.Link,
+			Description: item.Summary,
+			GUID:        rssGUID{IsPermaLink: item.ID == item.Link, Value: item.ID},
+		}
+		if item.ContentHTML != "" {
+			entry.Content = &rssCDATA{Value: item.ContentHTML}
+		}
+		if !item.Published.IsZero() {
+			entry.PubDate = item.Published.Format(time.RFC1123Z)
+		}
+		for _, category := range item.Categories {
+			entry.Categories = append(entry.Categories, rssCategory{Domain: category.Domain, Value: category.Label})
+		}
+		if item.Enclosure != nil {
+			entry.Enclosure = &rssEnclosure{
+				URL:    item.Enclosure.URL,
+				Length: item.Enclosure.Length,
+				Type:   item.Enclosure.Type,
+			}
+		}
+		doc.Channel.Items[i] = entry
+	}
+
+	return marshalXML(doc)
+}
+
+// marshalXML 输出带 XML 声明的缩进文档
+func marshalXML(v interface{}) ([]byte, error) {
+	out, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), append(out, '\n')...), nil
+}
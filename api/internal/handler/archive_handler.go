@@ -0,0 +1,86 @@
+// Package handler 备份处理器
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"kuaiyu/internal/archive"
+	"kuaiyu/pkg/constants"
+	"kuaiyu/pkg/response"
+)
+
+// ===========================================
+// 备份处理器
+// ===========================================
+
+// ArchiveHandler 备份处理器
+type ArchiveHandler struct{}
+
+// NewArchiveHandler 创建备份处理器
+func NewArchiveHandler() *ArchiveHandler {
+	return &ArchiveHandler{}
+}
+
+// ===========================================
+// 管理接口
+// ===========================================
+
+// Export 导出全站数据归档
+// 先写入临时文件，导出出错时仍能返回错误响应
+func (h *ArchiveHandler) Export(c *gin.Context) {
+	tmp, err := os.CreateTemp("", "kuaiyu-export-*.zip")
+	if err != nil {
+		response.InternalError(c, "导出失败")
+		return
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	if _, err := archive.Export(tmp); err != nil {
+		log.Printf("Failed to export archive: %v", err)
+		response.InternalError(c, "导出失败")
+		return
+	}
+
+	filename := "kuaiyu-backup-" + time.Now().Format("20060102-150405") + ".zip"
+	c.Header("Cache-Control", "no-store")
+	c.FileAttachment(tmp.Name(), filename)
+}
+
+// Restore 从归档恢复数据（仅限空数据库）
+func (h *ArchiveHandler) Restore(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, constants.ArchiveMaxSize)
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		response.BadRequest(c, "请上传备份归档")
+		return
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		response.BadRequest(c, "无法打开文件")
+		return
+	}
+	defer src.Close()
+
+	report, err := archive.Restore(src, file.Size)
+	if err != nil {
+		if errors.Is(err, archive.ErrNotEmpty) {
+			response.Error(c, http.StatusConflict, err.Error())
+			return
+		}
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	// 恢复后在后台重建检索索引和相关文章
+	go archive.Reindex()
+
+	response.Success(c, report)
+}
@@ -1,27 +1,39 @@
 package handler
 
 import (
-	"sort"
+	"errors"
 	"strconv"
+	"time"
 	
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"kuaiyu/internal/challenge"
+	"kuaiyu/internal/config"
 	"kuaiyu/internal/database"
 	"kuaiyu/internal/model"
+	"kuaiyu/internal/notify"
 	"kuaiyu/internal/repository"
+	"kuaiyu/internal/search"
+	"kuaiyu/internal/spam"
 	"kuaiyu/pkg/constants"
 	"kuaiyu/pkg/response"
 )
 
 type CommentHandler struct {
-	repo *repository.CommentRepository
+	repo         *repository.CommentRepository
+	reactionRepo *repository.ReactionRepository
 }
 
 func NewCommentHandler() *CommentHandler {
 	return &CommentHandler{
-		repo: repository.NewCommentRepository(),
+		repo:         repository.NewCommentRepository(),
+		reactionRepo: repository.NewReactionRepository(),
 	}
 }
 
+// List 评论列表
+// 顶层评论按游标分页（置顶优先，其次按时间倒序），每条附带深度优先顺序的前 DefaultReplyLimit 条回复，
+// 更多回复通过 Replies 接口加载。提供邮箱时包含该邮箱待审核的评论
 func (h *CommentHandler) List(c *gin.Context) {
 	commentType := c.Query("comment_type") // post | life | guestbook
 	targetIDStr := c.Query("target_id")
@@ -29,12 +41,15 @@ func (h *CommentHandler) List(c *gin.Context) {
 	lifeID, _ := GetIDParam(c, "life_record_id")
 	isGuestbook := c.Query("is_guestbook") == "true"
 	userEmail := c.Query("email")
+	_, limit := GetPageParams(c)
 	
-	var comments []model.Comment
-	var err error
-	var targetID *uint
+	cursor, err := model.DecodeCommentCursor(c.Query("cursor"))
+	if err != nil {
+		response.BadRequest(c, "无效的游标")
+		return
+	}
 	
-	db := database.Get()
+	var targetID *uint
 	
 	// 确定评论类型和目标ID
 	if commentType == "" {
@@ -50,14 +65,18 @@ func (h *CommentHandler) List(c *gin.Context) {
 			targetID = nil
 		} else {
 			// 默认返回最近评论
-			comments, err = h.repo.FindRecent(20)
-			if userEmail != "" {
-				var pendingComments []model.Comment
-				db.Where("comment_type = ? AND email = ? AND status = ?", "guestbook", userEmail, "pending").
-					Order("created_at DESC").Find(&pendingComments)
-				comments = append(pendingComments, comments...)
+			comments, err := h.repo.FindRecent(20)
+			if err != nil {
+				response.InternalError(c, "")
+				return
 			}
-			goto processComments
+			items, err := h.buildThreads(comments, userEmail)
+			if err != nil {
+				response.InternalError(c, "")
+				return
+			}
+			response.Success(c, model.CommentPageVO{Items: items})
+			return
 		}
 	} else {
 		// 使用新的 comment_type 参数
@@ -69,119 +88,173 @@ func (h *CommentHandler) List(c *gin.Context) {
 		}
 	}
 	
-	// 根据类型查询评论（不预加载回复，后面手动处理）
-	comments, err = h.repo.FindByCommentType(commentType, targetID, false)
-	
-	// 添加待审核评论（如果提供了用户邮箱）
-	if userEmail != "" {
-		var pendingComments []model.Comment
-		query := db.Where("comment_type = ? AND email = ? AND status = ?", commentType, userEmail, "pending")
-		if targetID != nil {
-			query = query.Where("target_id = ?", *targetID)
-		} else {
-			query = query.Where("target_id IS NULL")
-		}
-		query.Order("created_at DESC").Find(&pendingComments)
-		comments = append(pendingComments, comments...)
+	comments, hasMore, err := h.repo.FindThreads(commentType, targetID, userEmail, cursor, limit)
+	if err != nil {
+		response.InternalError(c, "")
+		return
 	}
 	
-processComments:
-	
+	items, err := h.buildThreads(comments, userEmail)
 	if err != nil {
 		response.InternalError(c, "")
 		return
 	}
+	page := model.CommentPageVO{
+		Items:   items,
+		HasMore: hasMore,
+	}
+	if hasMore {
+		last := comments[len(comments)-1]
+		page.NextCursor = model.CommentCursor{Pinned: last.IsPinned, CreatedAt: last.CreatedAt, ID: last.ID}.Encode()
+	}
 	
-	seen := make(map[uint]bool)
-	uniqueComments := make([]model.Comment, 0)
-	for _, c := range comments {
-		if !seen[c.ID] {
-			seen[c.ID] = true
-			uniqueComments = append(uniqueComments, c)
-		}
+	response.Success(c, page)
+}
+
+// Replies 按游标加载评论的更多回复，适用于任意层级的评论
+// 回复按深度优先顺序返回，客户端可根据 parent_id 和 depth 还原树形结构
+func (h *CommentHandler) Replies(c *gin.Context) {
+	id, err := GetIDParam(c, "id")
+	if err != nil {
+		response.BadRequest(c, "无效的 ID")
+		return
 	}
-	comments = uniqueComments
+	userEmail := c.Query("email")
 	
-	sort.Slice(comments, func(i, j int) bool {
-		if comments[i].IsPinned != comments[j].IsPinned {
-			return comments[i].IsPinned
-		}
-		return comments[i].CreatedAt.After(comments[j].CreatedAt)
-	})
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(constants.DefaultReplyLimit)))
+	if limit < 1 {
+		limit = constants.DefaultReplyLimit
+	}
+	if limit > constants.MaxPageSize {
+		limit = constants.MaxPageSize
+	}
 	
-	if len(comments) > 0 {
-		commentIDs := make([]uint, len(comments))
-		commentMap := make(map[uint]*model.Comment)
-		for i := range comments {
-			commentIDs[i] = comments[i].ID
-			commentMap[comments[i].ID] = &comments[i]
-		}
-		
-		var allReplies []model.Comment
-		db.Where("parent_id IN ? AND status = ?", commentIDs, constants.CommentStatusApproved).
-			Order("parent_id ASC, created_at ASC").
-			Find(&allReplies)
-		
-		for i := range allReplies {
-			reply := allReplies[i]
-			if reply.ParentID != nil {
-				if parent, exists := commentMap[*reply.ParentID]; exists {
-					parent.Replies = append(parent.Replies, reply)
-				}
-			}
-		}
+	cursor, err := model.DecodeCommentCursor(c.Query("cursor"))
+	if err != nil {
+		response.BadRequest(c, "无效的游标")
+		return
+	}
+	afterPath := ""
+	if cursor != nil {
+		afterPath = cursor.Path
 	}
 	
-	allCommentIDs := make(map[uint]bool)
-	for _, comment := range comments {
-		allCommentIDs[comment.ID] = true
-		if len(comment.Replies) > 0 {
-			for _, reply := range comment.Replies {
-				allCommentIDs[reply.ID] = true
-				if reply.ParentID != nil {
-					allCommentIDs[*reply.ParentID] = true
-				}
-			}
-		}
+	var comment model.Comment
+	if err := h.repo.FindByID(&comment, id); err != nil || !visibleTo(&comment, userEmail) {
+		response.NotFound(c, "评论不存在")
+		return
 	}
 	
-	commentIDList := make([]uint, 0, len(allCommentIDs))
-	for id := range allCommentIDs {
-		commentIDList = append(commentIDList, id)
+	replies, hasMore, err := h.repo.FindDescendants(&comment, userEmail, afterPath, limit)
+	if err != nil {
+		response.InternalError(c, "")
+		return
 	}
 	
-	nicknameMap := make(map[uint]string)
-	if len(commentIDList) > 0 {
-		var nicknameComments []struct {
-			ID       uint
-			Nickname string
-		}
-		db.Model(&model.Comment{}).
-			Select("id, nickname").
-			Where("id IN ?", commentIDList).
-			Find(&nicknameComments)
-		
-		for _, nc := range nicknameComments {
-			nicknameMap[nc.ID] = nc.Nickname
-		}
+	nicknameMap := replyNicknames(replies)
+	items := make([]model.CommentVO, len(replies))
+	for i := range replies {
+		items[i] = buildReplyVO(&replies[i], nicknameMap)
 	}
+	attachCommentReactions(items)
+	
+	page := model.CommentPageVO{Items: items, HasMore: hasMore}
+	if hasMore {
+		page.NextCursor = model.CommentCursor{Path: replies[len(replies)-1].Path}.Encode()
+	}
+	response.Success(c, page)
+}
+
+// buildThreads 为顶层评论附带前 DefaultReplyLimit 条回复和回复总数
+func (h *CommentHandler) buildThreads(comments []model.Comment, userEmail string) ([]model.CommentVO, error) {
+	rootIDs := make([]uint, len(comments))
+	for i := range comments {
+		rootIDs[i] = comments[i].ID
+	}
+	counts := h.repo.CountThreadReplies(rootIDs, userEmail)
+	
+	threadReplies, hasMore, err := h.repo.FindThreadReplies(rootIDs, userEmail, constants.DefaultReplyLimit)
+	if err != nil {
+		return nil, err
+	}
+	var allReplies []model.Comment
+	for _, replies := range threadReplies {
+		allReplies = append(allReplies, replies...)
+	}
+	nicknameMap := replyNicknames(allReplies)
 	
 	items := make([]model.CommentVO, len(comments))
 	for i, comment := range comments {
 		vo := comment.ToVO()
-		
-		if len(comment.Replies) > 0 {
-			vo.Replies = make([]model.CommentVO, len(comment.Replies))
-				for j := range comment.Replies {
-					vo.Replies[j] = buildReplyVO(&comment.Replies[j], nicknameMap)
-				}
-			vo.ReplyCount = len(comment.Replies)
+		replies := threadReplies[comment.ID]
+		if len(replies) > 0 {
+			vo.Replies = make([]model.CommentVO, len(replies))
+			for j := range replies {
+				vo.Replies[j] = buildReplyVO(&replies[j], nicknameMap)
+			}
+		}
+		vo.ReplyCount = max(counts[comment.ID], len(replies))
+		vo.HasMore = hasMore[comment.ID]
+		if vo.HasMore {
+			vo.NextCursor = model.CommentCursor{Path: replies[len(replies)-1].Path}.Encode()
 		}
-		
 		items[i] = vo
 	}
+	attachCommentReactions(items)
+	
+	return items, nil
+}
 
-	response.Success(c, items)
+// replyNicknames 查询回复所回复评论的昵称，支持任意层级
+func replyNicknames(replies []model.Comment) map[uint]string {
+	idSet := make(map[uint]bool)
+	for _, reply := range replies {
+		if reply.ReplyToID != nil {
+			idSet[*reply.ReplyToID] = true
+		}
+		if reply.ParentID != nil {
+			idSet[*reply.ParentID] = true
+		}
+	}
+	
+	nicknameMap := make(map[uint]string)
+	if len(idSet) == 0 {
+		return nicknameMap
+	}
+	
+	ids := make([]uint, 0, len(idSet))
+	for id := range idSet {
+		ids = append(ids, id)
+	}
+	var nicknameComments []struct {
+		ID       uint
+		Nickname string
+	}
+	database.Get().Model(&model.Comment{}).
+		Select("id, nickname").
+		Where("id IN ?", ids).
+		Find(&nicknameComments)
+	
+	for _, nc := range nicknameComments {
+		nicknameMap[nc.ID] = nc.Nickname
+	}
+	return nicknameMap
+}
+
+// sameTarget 判断两个评论目标是否相同
+func sameTarget(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+// visibleTo 评论对读者是否可见：已通过审核，或是该邮箱待审核的评论
+func visibleTo(comment *model.Comment, email string) bool {
+	if comment.Status == string(constants.CommentStatusApproved) {
+		return true
+	}
+	return email != "" && comment.Status == string(constants.CommentStatusPending) && comment.Email == email
 }
 
 func buildReplyVO(reply *model.Comment, nicknameMap map[uint]string) model.CommentVO {
@@ -240,10 +313,27 @@ func (h *CommentHandler) Create(c *gin.Context) {
 		}
 	}
 	
-	isFirst := h.repo.IsFirstComment(req.Email)
-	status := string(constants.CommentStatusApproved)
-	if isFirst {
-		status = string(constants.CommentStatusPending)
+	// 回复挂在被回复的评论下：优先取 reply_to_id，兼容只提交 parent_id 的客户端
+	var parent *model.Comment
+	parentID := req.ReplyToID
+	if parentID == nil {
+		parentID = req.ParentID
+	}
+	if parentID != nil {
+		var p model.Comment
+		if err := h.repo.FindByID(&p, *parentID); err != nil || !visibleTo(&p, req.Email) {
+			response.BadRequest(c, "父评论不存在")
+			return
+		}
+		if p.CommentType != commentType || !sameTarget(p.TargetID, targetID) {
+			response.BadRequest(c, "父评论不属于该页面")
+			return
+		}
+		if !p.CanReply() {
+			response.BadRequest(c, "回复层级过深")
+			return
+		}
+		parent = &p
 	}
 	
 	comment := model.Comment{
@@ -251,36 +341,241 @@ func (h *CommentHandler) Create(c *gin.Context) {
 		TargetID:     targetID,
 		PostID:       req.PostID,       // 保留用于向后兼容
 		LifeRecordID: req.LifeRecordID, // 保留用于向后兼容
-		ParentID:     req.ParentID,
-		ReplyToID:    req.ReplyToID,
 		Nickname:     req.Nickname,
 		Email:        req.Email,
 		Avatar:       req.Avatar,
 		Website:      req.Website,
 		Content:      req.Content,
-		Status:       status,
 		IPAddress:    GetClientIP(c),
 		UserAgent:    GetUserAgent(c),
 	}
 	
-	db := database.Get()
-	if err := db.Create(&comment).Error; err != nil {
+	// 开启工作量证明时需要提交有效且未使用过的解
+	// 放在参数校验之后，请求有误时不消耗已求出的解
+	if config.Get().Challenge.Enabled {
+		if req.Challenge == "" || req.Nonce == "" {
+			response.BadRequest(c, constants.MsgChallengeRequired)
+			return
+		}
+		if err := challenge.Redeem(req.Challenge, req.Nonce); err != nil {
+			if errors.Is(err, challenge.ErrInvalid) || errors.Is(err, challenge.ErrUnsolved) || errors.Is(err, challenge.ErrUsed) {
+				response.Forbidden(c, constants.MsgChallengeFailed)
+			} else {
+				response.InternalError(c, "")
+			}
+			return
+		}
+	}
+	
+	// 反垃圾检查决定评论状态，检查原因保存供管理员审核时查看
+	submission := &spam.Submission{
+		Comment:    &comment,
+		Honeypot:   req.Honeypot,
+		ReceivedAt: time.Now(),
+	}
+	if req.RenderedAt > 0 {
+		submission.RenderedAt = time.UnixMilli(req.RenderedAt)
+	}
+	verdict := spam.Evaluate(submission)
+	comment.Status = verdict.Status
+	comment.SpamScore = verdict.Score
+	comment.SetSpamReasons(verdict.Reasons)
+	if parent != nil {
+		comment.ReplyToID = &parent.ID
+	}
+	
+	// 编辑令牌只返回给作者一次，数据库保存哈希
+	editToken, editTokenHash := model.NewCommentEditToken()
+	comment.EditTokenHash = editTokenHash
+	
+	if err := h.repo.CreateInThread(&comment, parent); err != nil {
 		response.InternalError(c, "评论失败")
 		return
 	}
 	
+	search.SyncComment(&comment)
+	notify.CommentCreated(&comment)
+	
+	// 垃圾评论对提交者显示为待审核
+	status := comment.Status
 	message := constants.MsgCommentApproved
-	if isFirst {
+	if status != string(constants.CommentStatusApproved) {
+		status = string(constants.CommentStatusPending)
 		message = constants.MsgCommentPending
 	}
 	
 	response.SuccessMessage(c, message, model.CreateCommentResponse{
-		ID:      comment.ID,
-		Status:  status,
-		Message: message,
+		ID:            comment.ID,
+		Status:        status,
+		Message:       message,
+		EditToken:     editToken,
+		EditableUntil: comment.EditableUntil(config.Get().Comment.EditWindow),
 	})
 }
 
+// ===========================================
+// 作者编辑
+// ===========================================
+
+// authorComment 按路径中的 ID 和 X-Edit-Token 请求头查找作者自己的评论
+func (h *CommentHandler) authorComment(c *gin.Context) (*model.Comment, bool) {
+	id, err := GetIDParam(c, "id")
+	if err != nil {
+		response.BadRequest(c, "无效的 ID")
+		return nil, false
+	}
+	
+	var comment model.Comment
+	if err := h.repo.FindByID(&comment, id); err != nil || comment.Withdrawn {
+		response.NotFound(c, "评论不存在")
+		return nil, false
+	}
+	if !comment.CheckEditToken(c.GetHeader("X-Edit-Token")) {
+		response.Forbidden(c, constants.MsgEditTokenInvalid)
+		return nil, false
+	}
+	return &comment, true
+}
+
+// Edit 作者在可编辑时间内修改评论内容
+// 修改前的内容记入编辑历史，修改后重新进行反垃圾检查；已被标记为垃圾的评论保持原状态
+func (h *CommentHandler) Edit(c *gin.Context) {
+	var req model.EditCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	
+	comment, ok := h.authorComment(c)
+	if !ok {
+		return
+	}
+	if time.Now().After(comment.EditableUntil(config.Get().Comment.EditWindow)) {
+		response.Forbidden(c, constants.MsgEditExpired)
+		return
+	}
+	
+	if req.Content != comment.Content {
+		edit := model.CommentEdit{
+			CommentID: comment.ID,
+			Action:    model.CommentEditActionEdit,
+			Content:   comment.Content,
+			Status:    comment.Status,
+			SpamScore: comment.SpamScore,
+			IPAddress: GetClientIP(c),
+		}
+		
+		now := time.Now()
+		comment.Content = req.Content
+		comment.EditedAt = &now
+		verdict := spam.Evaluate(&spam.Submission{Comment: comment, ReceivedAt: now})
+		if comment.Status != string(constants.CommentStatusSpam) {
+			comment.Status = verdict.Status
+		}
+		comment.SpamScore = verdict.Score
+		comment.SetSpamReasons(verdict.Reasons)
+		
+		err := database.Get().Transaction(func(tx *gorm.DB) error {
+			if err := tx.Create(&edit).Error; err != nil {
+				return err
+			}
+			return tx.Model(comment).
+				Select("content", "edited_at", "status", "spam_score", "spam_reasons").
+				Updates(comment).Error
+		})
+		if err != nil {
+			response.InternalError(c, "更新失败")
+			return
+		}
+		
+		search.SyncComment(comment)
+	}
+	
+	// 垃圾评论对作者显示为待审核
+	vo := comment.ToVO()
+	message := constants.MsgUpdateSuccess
+	if vo.Status != string(constants.CommentStatusApproved) {
+		vo.Status = string(constants.CommentStatusPending)
+		message = constants.MsgCommentPending
+	}
+	response.SuccessMessage(c, message, vo)
+}
+
+// Withdraw 作者删除评论，不受编辑时间限制
+// 没有回复时直接删除；有回复时清空内容并保留占位，以免破坏主题结构
+func (h *CommentHandler) Withdraw(c *gin.Context) {
+	comment, ok := h.authorComment(c)
+	if !ok {
+		return
+	}
+	
+	edit := model.CommentEdit{
+		CommentID: comment.ID,
+		Action:    model.CommentEditActionWithdraw,
+		Content:   comment.Content,
+		Status:    comment.Status,
+		SpamScore: comment.SpamScore,
+		IPAddress: GetClientIP(c),
+	}
+	hasReplies := len(h.repo.FindSubtreeIDs(comment)) > 0
+	
+	err := database.Get().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&edit).Error; err != nil {
+			return err
+		}
+		if !hasReplies {
+			return tx.Delete(comment).Error
+		}
+		return tx.Model(comment).UpdateColumns(map[string]interface{}{
+			"content":    "",
+			"withdrawn":  true,
+			"edited_at":  time.Now(),
+			"updated_at": time.Now(),
+		}).Error
+	})
+	if err != nil {
+		response.InternalError(c, "删除失败")
+		return
+	}
+	
+	search.Remove(search.DocTypeComment, comment.ID)
+	if !hasReplies {
+		h.reactionRepo.DeleteByTargets(model.ReactionTargetComment, []uint{comment.ID})
+	}
+	
+	response.SuccessMessage(c, constants.MsgDeleteSuccess, nil)
+}
+
+// Edits 评论的作者编辑历史（管理后台）
+func (h *CommentHandler) Edits(c *gin.Context) {
+	id, err := GetIDParam(c, "id")
+	if err != nil {
+		response.BadRequest(c, "无效的 ID")
+		return
+	}
+	
+	edits, err := h.repo.FindEdits(id)
+	if err != nil {
+		response.InternalError(c, "")
+		return
+	}
+	
+	response.Success(c, edits)
+}
+
+// Challenge 获取评论工作量证明题目
+func (h *CommentHandler) Challenge(c *gin.Context) {
+	vo, err := challenge.Issue()
+	if err != nil {
+		response.InternalError(c, "")
+		return
+	}
+	vo.Required = config.Get().Challenge.Enabled
+	
+	c.Header("Cache-Control", "no-store")
+	response.Success(c, vo)
+}
+
 func (h *CommentHandler) AdminList(c *gin.Context) {
 	page, limit := GetPageParams(c)
 	status := c.Query("status")
@@ -361,12 +656,25 @@ func (h *CommentHandler) UpdateStatus(c *gin.Context) {
 		return
 	}
 	
+	previousStatus := comment.Status
 	comment.Status = req.Status
 	if err := db.Save(&comment).Error; err != nil {
 		response.InternalError(c, "更新失败")
 		return
 	}
 	
+	search.SyncComment(&comment)
+	
+	// 审核结果用于训练反垃圾分类器
+	if comment.Status != previousStatus {
+		spam.Learn(&comment)
+	}
+	
+	// 审核通过后通知被回复的评论者
+	if previousStatus == string(constants.CommentStatusPending) && comment.Status == string(constants.CommentStatusApproved) {
+		notify.CommentApproved(&comment)
+	}
+	
 	response.SuccessMessage(c, constants.MsgUpdateSuccess, comment.ToAdminVO())
 }
 
@@ -409,10 +717,21 @@ func (h *CommentHandler) Delete(c *gin.Context) {
 		return
 	}
 	
+	var comment model.Comment
+	if err := h.repo.FindByID(&comment, id); err != nil {
+		response.NotFound(c, "评论不存在")
+		return
+	}
+	
 	db := database.Get()
 	
-	// 删除子评论
-	db.Where("parent_id = ?", id).Delete(&model.Comment{})
+	// 记录全部后代评论 ID，用于清理检索索引
+	replyIDs := h.repo.FindSubtreeIDs(&comment)
+	
+	// 删除后代评论
+	if len(replyIDs) > 0 {
+		db.Where("id IN ?", replyIDs).Delete(&model.Comment{})
+	}
 	
 	// 删除评论
 	if err := db.Delete(&model.Comment{}, id).Error; err != nil {
@@ -420,6 +739,12 @@ func (h *CommentHandler) Delete(c *gin.Context) {
 		return
 	}
 	
+	search.Remove(search.DocTypeComment, id)
+	for _, replyID := range replyIDs {
+		search.Remove(search.DocTypeComment, replyID)
+	}
+	h.reactionRepo.DeleteByTargets(model.ReactionTargetComment, append(replyIDs, id))
+	
 	response.SuccessMessage(c, constants.MsgDeleteSuccess, nil)
 }
 
@@ -439,11 +764,14 @@ func (h *CommentHandler) AdminReply(c *gin.Context) {
 	
 	// 检查父评论
 	var parent model.Comment
-	db := database.Get()
-	if err := db.First(&parent, id).Error; err != nil {
+	if err := h.repo.FindByID(&parent, id); err != nil {
 		response.NotFound(c, "评论不存在")
 		return
 	}
+	if !parent.CanReply() {
+		response.BadRequest(c, "回复层级过深")
+		return
+	}
 	
 	// 创建管理员回复
 	reply := model.Comment{
@@ -451,7 +779,7 @@ func (h *CommentHandler) AdminReply(c *gin.Context) {
 		TargetID:     parent.TargetID,
 		PostID:       parent.PostID,       // 保留用于向后兼容
 		LifeRecordID: parent.LifeRecordID, // 保留用于向后兼容
-		ParentID:     &id,
+		ReplyToID:    &id,
 		Nickname:     "管理员",
 		Email:        "admin@kcat.site",
 		Content:      req.Content,
@@ -461,11 +789,14 @@ func (h *CommentHandler) AdminReply(c *gin.Context) {
 		UserAgent:    GetUserAgent(c),
 	}
 	
-	if err := db.Create(&reply).Error; err != nil {
+	if err := h.repo.CreateInThread(&reply, &parent); err != nil {
 		response.InternalError(c, "回复失败")
 		return
 	}
 	
+	search.SyncComment(&reply)
+	notify.CommentCreated(&reply)
+	
 	response.Created(c, reply.ToVO())
 }
 
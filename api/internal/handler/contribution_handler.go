@@ -7,7 +7,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"kuaiyu/internal/database"
 	"kuaiyu/internal/model"
-	"kuaiyu/pkg/constants"
+	"kuaiyu/internal/repository"
 	"kuaiyu/pkg/response"
 )
 
@@ -82,8 +82,8 @@ func (h *ContributionHandler) GetContributionCalendar(c *gin.Context) {
 	// 查询博客记录
 	if contributionType == "all" || contributionType == "post" {
 		var posts []model.Post
-		db.Where("status = ? AND published_at >= ? AND published_at <= ?",
-			constants.PostStatusPublished, startDate, endDate).
+		db.Scopes(repository.PublishedScope("posts"), repository.ListedScope("posts")).
+			Where("published_at >= ? AND published_at <= ?", startDate, endDate).
 			Select("id, title, slug, published_at").
 			Order("published_at ASC").
 			Find(&posts)
@@ -121,8 +121,8 @@ func (h *ContributionHandler) GetContributionCalendar(c *gin.Context) {
 	// 查询生活记录
 	if contributionType == "all" || contributionType == "life" {
 		var lifeRecords []model.LifeRecord
-		db.Where("status = ? AND published_at >= ? AND published_at <= ?",
-			constants.PostStatusPublished, startDate, endDate).
+		db.Scopes(repository.PublishedScope("life_records")).
+			Where("published_at >= ? AND published_at <= ?", startDate, endDate).
 			Select("id, title, content, published_at").
 			Order("published_at ASC").
 			Find(&lifeRecords)
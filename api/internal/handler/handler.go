@@ -3,7 +3,13 @@
 package handler
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"kuaiyu/pkg/constants"
@@ -61,3 +67,97 @@ func GetUserAgent(c *gin.Context) string {
 	return c.GetHeader("User-Agent")
 }
 
+// ===========================================
+// 条件请求
+// ===========================================
+
+// writeConditional 输出可缓存的内容（订阅源、站点地图等）
+// ETag 取内容哈希，Last-Modified 取内容的最后修改时间（零值时不输出）；
+// 命中 If-None-Match / If-Modified-Since 时返回 304
+func writeConditional(c *gin.Context, contentType string, data []byte, lastModified time.Time) {
+	sum := sha256.Sum256(data)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	lastModified = lastModified.UTC().Truncate(time.Second)
+
+	c.Header("ETag", etag)
+	if !lastModified.IsZero() {
+		c.Header("Last-Modified", lastModified.Format(http.TimeFormat))
+	}
+	c.Header("Cache-Control", "public, no-cache")
+
+	if notModified(c, etag, lastModified) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	c.Data(http.StatusOK, contentType, data)
+}
+
+// notModified 判断条件请求是否命中
+// 有 If-None-Match 时只比较 ETag（弱比较），否则比较 If-Modified-Since
+func notModified(c *gin.Context, etag string, lastModified time.Time) bool {
+	if match := c.GetHeader("If-None-Match"); match != "" {
+		for _, candidate := range strings.Split(match, ",") {
+			candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+			if candidate == "*" || candidate == etag {
+				return true
+			}
+		}
+		return false
+	}
+
+	if lastModified.IsZero() {
+		return false
+	}
+	since, err := http.ParseTime(c.GetHeader("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+	return !lastModified.After(since)
+}
+
+// ===========================================
+// 发布状态
+// ===========================================
+
+// resolvePublish 根据目标状态和请求的发布时间计算最终状态和发布时间
+// prevStatus/prevPublishedAt 为内容当前的值（创建时为空）
+//   - scheduled：publish_at 必须晚于当前时间，未传时沿用已排期的时间
+//   - published：publish_at 在未来时自动转为 scheduled，在过去时视为补录发布时间
+//   - draft：取消尚未生效的定时发布
+func resolvePublish(status string, publishAt *time.Time, prevStatus string, prevPublishedAt *time.Time) (string, *time.Time, error) {
+	now := time.Now()
+
+	switch status {
+	case string(constants.PostStatusScheduled):
+		if publishAt == nil {
+			if prevStatus != string(constants.PostStatusScheduled) || prevPublishedAt == nil {
+				return "", nil, errors.New(constants.MsgPublishAtRequired)
+			}
+			publishAt = prevPublishedAt
+		}
+		if !publishAt.After(now) {
+			return "", nil, errors.New(constants.MsgPublishAtInPast)
+		}
+		return status, publishAt, nil
+
+	case string(constants.PostStatusPublished):
+		if publishAt != nil {
+			if publishAt.After(now) {
+				return string(constants.PostStatusScheduled), publishAt, nil
+			}
+			return status, publishAt, nil
+		}
+		// 首次发布（或提前发布定时内容）设置为当前时间
+		if prevPublishedAt == nil || prevStatus == string(constants.PostStatusScheduled) {
+			return status, &now, nil
+		}
+		return status, prevPublishedAt, nil
+
+	default:
+		if prevStatus == string(constants.PostStatusScheduled) {
+			return status, nil, nil
+		}
+		return status, prevPublishedAt, nil
+	}
+}
@@ -0,0 +1,114 @@
+// Package handler 导入处理器
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"kuaiyu/internal/importer"
+	"kuaiyu/internal/middleware"
+	"kuaiyu/internal/related"
+	"kuaiyu/pkg/constants"
+	"kuaiyu/pkg/response"
+)
+
+// ===========================================
+// 导入处理器
+// ===========================================
+
+// ImportHandler 导入处理器
+type ImportHandler struct {
+	importer *importer.Importer
+}
+
+// NewImportHandler 创建导入处理器
+func NewImportHandler() *ImportHandler {
+	return &ImportHandler{
+		importer: importer.NewImporter(),
+	}
+}
+
+// ===========================================
+// 管理接口
+// ===========================================
+
+// ImportPosts 从 Markdown 文件导入文章
+// 表单字段 file 上传 zip 压缩包或单个 Markdown 文件，files 可上传多个 Markdown 文件（目录）
+// 默认只返回预演报告，dry_run=false 时才实际写入
+func (h *ImportHandler) ImportPosts(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, constants.ImportMaxSize)
+
+	form, err := c.MultipartForm()
+	if err != nil {
+		response.BadRequest(c, "请上传 zip 压缩包或 Markdown 文件")
+		return
+	}
+
+	var headers []*multipart.FileHeader
+	headers = append(headers, form.File["file"]...)
+	headers = append(headers, form.File["files"]...)
+	if len(headers) == 0 {
+		response.BadRequest(c, "请上传 zip 压缩包或 Markdown 文件")
+		return
+	}
+
+	var files []importer.File
+	for _, header := range headers {
+		read, err := readImportFile(header)
+		if err != nil {
+			response.BadRequest(c, err.Error())
+			return
+		}
+		files = append(files, read...)
+	}
+	if len(files) == 0 {
+		response.BadRequest(c, "未找到 Markdown 文件")
+		return
+	}
+
+	dryRun := true
+	if v := c.DefaultPostForm("dry_run", c.Query("dry_run")); v != "" {
+		if b, err := strconv.ParseBool(v); err == nil {
+			dryRun = b
+		}
+	}
+
+	if dryRun {
+		response.Success(c, h.importer.Plan(files))
+		return
+	}
+	report := h.importer.Apply(files, middleware.GetUserID(c))
+	if report.Created > 0 {
+		related.Refresh()
+	}
+	response.Success(c, report)
+}
+
+// readImportFile 读取上传的单个文件（zip 解包，Markdown 直接读取）
+func readImportFile(header *multipart.FileHeader) ([]importer.File, error) {
+	src, err := header.Open()
+	if err != nil {
+		return nil, errors.New("无法打开文件")
+	}
+	defer src.Close()
+
+	if strings.EqualFold(filepath.Ext(header.Filename), ".zip") {
+		return importer.ReadZip(src, header.Size)
+	}
+
+	if !importer.IsMarkdown(header.Filename) {
+		return nil, nil
+	}
+	var buf bytes.Buffer
+	if _, err := io.Copy(&buf, src); err != nil {
+		return nil, errors.New("无法读取文件")
+	}
+	return []importer.File{{Path: filepath.ToSlash(header.Filename), Data: buf.Bytes()}}, nil
+}
@@ -10,6 +10,10 @@ import (
 	"kuaiyu/internal/database"
 	"kuaiyu/internal/middleware"
 	"kuaiyu/internal/model"
+	"kuaiyu/internal/ping"
+	"kuaiyu/internal/render"
+	"kuaiyu/internal/repository"
+	"kuaiyu/internal/search"
 	"kuaiyu/pkg/constants"
 	"kuaiyu/pkg/response"
 )
@@ -19,11 +23,17 @@ import (
 // ===========================================
 
 // LifeHandler 生活记录处理器
-type LifeHandler struct{}
+type LifeHandler struct {
+	revisionRepo *repository.RevisionRepository
+	reactionRepo *repository.ReactionRepository
+}
 
 // NewLifeHandler 创建生活记录处理器
 func NewLifeHandler() *LifeHandler {
-	return &LifeHandler{}
+	return &LifeHandler{
+		revisionRepo: repository.NewRevisionRepository(),
+		reactionRepo: repository.NewReactionRepository(),
+	}
 }
 
 // ===========================================
@@ -39,7 +49,7 @@ func (h *LifeHandler) List(c *gin.Context) {
 	
 	db := database.Get()
 	query := db.Model(&model.LifeRecord{}).
-		Where("status = ?", constants.PostStatusPublished)
+		Scopes(repository.PublishedScope("life_records"))
 	
 	query.Count(&total)
 	
@@ -56,6 +66,7 @@ func (h *LifeHandler) List(c *gin.Context) {
 	for i, record := range records {
 		items[i] = record.ToListVO(constants.LifeContentPreviewLength)
 	}
+	attachLifeListReactions(items)
 	
 	response.PagedSuccess(c, items, page, limit, total)
 }
@@ -80,7 +91,11 @@ func (h *LifeHandler) Get(c *gin.Context) {
 		return
 	}
 	
-	response.Success(c, record.ToVO())
+	vo := record.ToVO()
+	render.Life(&vo)
+	vo.Reactions = reactionCounts(model.ReactionTargetLife, []uint{record.ID})[record.ID]
+	
+	response.Success(c, vo)
 }
 
 // IncrementViews 增加生活记录的阅读量，并记录到 page_views 表
@@ -191,7 +206,10 @@ func (h *LifeHandler) AdminGet(c *gin.Context) {
 		return
 	}
 	
-	response.Success(c, record.ToVO())
+	vo := record.ToVO()
+	render.Life(&vo)
+	
+	response.Success(c, vo)
 }
 
 // Create 创建生活记录
@@ -202,16 +220,18 @@ func (h *LifeHandler) Create(c *gin.Context) {
 		return
 	}
 	
-	record := model.LifeRecord{
-		Content:    req.Content,
-		CoverImage: req.CoverImage,
-		Status:     req.Status,
-		AuthorID:   middleware.GetUserID(c),
+	status, publishedAt, err := resolvePublish(req.Status, req.PublishAt, "", nil)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
 	}
 	
-	if req.Status == string(constants.PostStatusPublished) {
-		now := time.Now()
-		record.PublishedAt = &now
+	record := model.LifeRecord{
+		Content:     req.Content,
+		CoverImage:  req.CoverImage,
+		Status:      status,
+		AuthorID:    middleware.GetUserID(c),
+		PublishedAt: publishedAt,
 	}
 	
 	db := database.Get()
@@ -220,6 +240,17 @@ func (h *LifeHandler) Create(c *gin.Context) {
 		return
 	}
 	
+	// 保存修订快照
+	h.saveRevision(&record, constants.RevisionReasonCreate, middleware.GetUserID(c))
+	
+	// 更新检索索引
+	search.SyncLife(&record)
+	
+	// 通知搜索引擎
+	if ping.PublicLife(&record) {
+		ping.Notify(model.PingReasonPublish, ping.LifeURL(record.ID))
+	}
+	
 	response.Created(c, record.ToVO())
 }
 
@@ -243,6 +274,7 @@ func (h *LifeHandler) Update(c *gin.Context) {
 		response.NotFound(c, "记录不存在")
 		return
 	}
+	wasPublic := ping.PublicLife(&record)
 	
 	if req.Content != "" {
 		record.Content = req.Content
@@ -250,12 +282,18 @@ func (h *LifeHandler) Update(c *gin.Context) {
 	if req.CoverImage != "" {
 		record.CoverImage = req.CoverImage
 	}
-	if req.Status != "" {
-		if req.Status == string(constants.PostStatusPublished) && record.PublishedAt == nil {
-			now := time.Now()
-			record.PublishedAt = &now
+	if req.Status != "" || req.PublishAt != nil {
+		status := req.Status
+		if status == "" {
+			status = record.Status
+		}
+		status, publishedAt, err := resolvePublish(status, req.PublishAt, record.Status, record.PublishedAt)
+		if err != nil {
+			response.BadRequest(c, err.Error())
+			return
 		}
-		record.Status = req.Status
+		record.Status = status
+		record.PublishedAt = publishedAt
 	}
 	
 	if err := db.Save(&record).Error; err != nil {
@@ -263,6 +301,17 @@ func (h *LifeHandler) Update(c *gin.Context) {
 		return
 	}
 	
+	// 保存修订快照
+	h.saveRevision(&record, constants.RevisionReasonUpdate, middleware.GetUserID(c))
+	
+	// 更新检索索引
+	search.SyncLife(&record)
+	
+	// 通知搜索引擎
+	if reason, ok := ping.ChangeReason(wasPublic, ping.PublicLife(&record)); ok {
+		ping.Notify(reason, ping.LifeURL(record.ID))
+	}
+	
 	response.SuccessMessage(c, constants.MsgUpdateSuccess, record.ToVO())
 }
 
@@ -275,11 +324,36 @@ func (h *LifeHandler) Delete(c *gin.Context) {
 	}
 	
 	db := database.Get()
+	
+	var record model.LifeRecord
+	wasPublic := db.First(&record, id).Error == nil && ping.PublicLife(&record)
+	
 	if err := db.Delete(&model.LifeRecord{}, id).Error; err != nil {
 		response.InternalError(c, "删除失败")
 		return
 	}
 	
+	search.Remove(search.DocTypeLife, id)
+	h.reactionRepo.DeleteByTargets(model.ReactionTargetLife, []uint{id})
+	
+	if wasPublic {
+		ping.Notify(model.PingReasonDelete, ping.LifeURL(id))
+	}
+	
 	response.SuccessMessage(c, constants.MsgDeleteSuccess, nil)
 }
 
+// saveRevision 保存生活记录修订快照，失败仅记录日志
+func (h *LifeHandler) saveRevision(record *model.LifeRecord, reason constants.RevisionReason, editorID uint) {
+	revision := model.NewLifeRevision(record, string(reason), editorID)
+	saved, err := h.revisionRepo.Save(&revision)
+	if err != nil {
+		log.Printf("Failed to save life revision: %v", err)
+		return
+	}
+	
+	// 预先渲染新修订的正文
+	if saved {
+		render.Markdown(record.Content)
+	}
+}
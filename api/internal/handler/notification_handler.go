@@ -0,0 +1,54 @@
+// Package handler 邮件通知处理器
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"kuaiyu/internal/notify"
+)
+
+// ===========================================
+// 邮件通知处理器
+// ===========================================
+
+// NotificationHandler 邮件通知处理器
+type NotificationHandler struct{}
+
+// NewNotificationHandler 创建邮件通知处理器
+func NewNotificationHandler() *NotificationHandler {
+	return &NotificationHandler{}
+}
+
+// Unsubscribe 一键退订
+// GET 由用户点击邮件中的链接，返回结果页面；POST 由邮件客户端按 RFC 8058 直接提交
+func (h *NotificationHandler) Unsubscribe(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		token = c.PostForm("token")
+	}
+
+	email, err := notify.Unsubscribe(token)
+	if err != nil && !errors.Is(err, notify.ErrInvalidToken) && !errors.Is(err, notify.ErrExpiredToken) {
+		log.Printf("Failed to unsubscribe: %v", err)
+	}
+
+	status := http.StatusOK
+	if err != nil {
+		status = http.StatusBadRequest
+	}
+	if c.Request.Method == http.MethodPost {
+		c.Status(status)
+		return
+	}
+
+	page, renderErr := notify.UnsubscribePage(email, err == nil)
+	if renderErr != nil {
+		log.Printf("Failed to render unsubscribe page: %v", renderErr)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+	c.Data(status, "text/html; charset=utf-8", page)
+}
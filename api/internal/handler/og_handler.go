@@ -0,0 +1,74 @@
+// Package handler 文章分享图处理器
+package handler
+
+import (
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"kuaiyu/internal/config"
+	"kuaiyu/internal/model"
+	"kuaiyu/internal/ogimage"
+	"kuaiyu/internal/repository"
+	"kuaiyu/pkg/response"
+	"kuaiyu/pkg/utils"
+)
+
+// ===========================================
+// 文章分享图处理器
+// ===========================================
+
+// OGHandler 文章分享图处理器
+type OGHandler struct {
+	postRepo *repository.PostRepository
+}
+
+// NewOGHandler 创建文章分享图处理器
+func NewOGHandler() *OGHandler {
+	return &OGHandler{
+		postRepo: repository.NewPostRepository(),
+	}
+}
+
+// Image 输出文章分享图（/og/:slug.png）
+// 已缓存到 COS 时重定向到 COS 地址，否则直接输出生成的 PNG
+func (h *OGHandler) Image(c *gin.Context) {
+	slug, ok := strings.CutSuffix(c.Param("file"), ".png")
+	if !ok || slug == "" {
+		response.NotFound(c, "图片不存在")
+		return
+	}
+
+	post, err := h.postRepo.FindBySlug(slug)
+	if err != nil || !isPublishedNow(post.Status, post.PublishedAt) {
+		response.NotFound(c, "文章不存在")
+		return
+	}
+
+	fileURL, data, err := ogimage.Get(post)
+	if err != nil {
+		log.Printf("Failed to render og image for post %d: %v", post.ID, err)
+		response.InternalError(c, "")
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=3600")
+	if data == nil {
+		c.Redirect(http.StatusFound, fileURL)
+		return
+	}
+	c.Data(http.StatusOK, "image/png", data)
+}
+
+// ===========================================
+// 辅助函数
+// ===========================================
+
+// postShareImage 文章的分享图地址：有封面时使用封面，否则使用自动生成的分享图
+func postShareImage(post *model.Post) string {
+	if post.CoverImage != "" {
+		return utils.AbsoluteURL(config.Get().Site.URL, post.CoverImage)
+	}
+	return ogimage.URL(post.Slug)
+}
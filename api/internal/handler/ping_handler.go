@@ -0,0 +1,111 @@
+// Package handler 搜索引擎推送处理器
+package handler
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"kuaiyu/internal/config"
+	"kuaiyu/internal/model"
+	"kuaiyu/internal/ping"
+	"kuaiyu/internal/repository"
+	"kuaiyu/pkg/constants"
+	"kuaiyu/pkg/response"
+	"kuaiyu/pkg/utils"
+)
+
+// ===========================================
+// 搜索引擎推送处理器
+// ===========================================
+
+// PingHandler 搜索引擎推送处理器
+type PingHandler struct {
+	repo *repository.PingRepository
+}
+
+// NewPingHandler 创建搜索引擎推送处理器
+func NewPingHandler() *PingHandler {
+	return &PingHandler{
+		repo: repository.NewPingRepository(),
+	}
+}
+
+// ===========================================
+// 管理接口
+// ===========================================
+
+// List 推送记录列表，支持 kind、status 过滤
+func (h *PingHandler) List(c *gin.Context) {
+	page, limit := GetPageParams(c)
+
+	logs, total, err := h.repo.FindAll(c.Query("kind"), c.Query("status"), page, limit)
+	if err != nil {
+		response.InternalError(c, "")
+		return
+	}
+
+	items := make([]model.PingLogVO, len(logs))
+	for i := range logs {
+		items[i] = logs[i].ToVO()
+	}
+	response.PagedSuccess(c, items, page, limit, total)
+}
+
+// Submit 手动提交地址
+func (h *PingHandler) Submit(c *gin.Context) {
+	if !config.Get().Ping.Enabled {
+		response.BadRequest(c, "搜索引擎推送未启用")
+		return
+	}
+
+	var req model.SubmitPingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	site := config.Get().Site
+	urls := make([]string, 0, len(req.URLs))
+	for _, u := range req.URLs {
+		u = utils.AbsoluteURL(site.URL, strings.TrimSpace(u))
+		if u != site.URL && !strings.HasPrefix(u, site.URL+"/") {
+			response.BadRequest(c, "只能提交本站地址："+u)
+			return
+		}
+		urls = append(urls, u)
+	}
+
+	ping.Notify(model.PingReasonManual, urls...)
+	response.SuccessMessage(c, constants.MsgOperationSuccess, nil)
+}
+
+// Retry 重新发送推送任务
+func (h *PingHandler) Retry(c *gin.Context) {
+	id, err := GetIDParam(c, "id")
+	if err != nil {
+		response.BadRequest(c, "无效的推送记录 ID")
+		return
+	}
+
+	entry, err := h.repo.FindByID(id)
+	if err != nil {
+		response.NotFound(c, "推送记录不存在")
+		return
+	}
+	if entry.Status == model.PingStatusSuccess {
+		response.BadRequest(c, "推送已成功，无需重试")
+		return
+	}
+
+	if err := ping.Retry(entry); err != nil {
+		response.InternalError(c, "")
+		return
+	}
+
+	entry, err = h.repo.FindByID(id)
+	if err != nil {
+		response.InternalError(c, "")
+		return
+	}
+	response.SuccessMessage(c, constants.MsgOperationSuccess, entry.ToVO())
+}
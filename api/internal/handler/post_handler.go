@@ -2,6 +2,7 @@
 package handler
 
 import (
+	"errors"
 	"log"
 	"time"
 
@@ -9,7 +10,12 @@ import (
 	"kuaiyu/internal/database"
 	"kuaiyu/internal/middleware"
 	"kuaiyu/internal/model"
+	"kuaiyu/internal/ogimage"
+	"kuaiyu/internal/ping"
+	"kuaiyu/internal/related"
+	"kuaiyu/internal/render"
 	"kuaiyu/internal/repository"
+	"kuaiyu/internal/search"
 	"kuaiyu/pkg/constants"
 	"kuaiyu/pkg/response"
 	"kuaiyu/pkg/utils"
@@ -21,13 +27,25 @@ import (
 
 // PostHandler 文章处理器
 type PostHandler struct {
-	repo *repository.PostRepository
+	repo            *repository.PostRepository
+	revisionRepo    *repository.RevisionRepository
+	seriesRepo      *repository.SeriesRepository
+	relatedRepo     *repository.RelatedRepository
+	redirectRepo    *repository.RedirectRepository
+	translationRepo *repository.TranslationRepository
+	reactionRepo    *repository.ReactionRepository
 }
 
 // NewPostHandler 创建文章处理器
 func NewPostHandler() *PostHandler {
 	return &PostHandler{
-		repo: repository.NewPostRepository(),
+		repo:            repository.NewPostRepository(),
+		revisionRepo:    repository.NewRevisionRepository(),
+		seriesRepo:      repository.NewSeriesRepository(),
+		relatedRepo:     repository.NewRelatedRepository(),
+		redirectRepo:    repository.NewRedirectRepository(),
+		translationRepo: repository.NewTranslationRepository(),
+		reactionRepo:    repository.NewReactionRepository(),
 	}
 }
 
@@ -46,7 +64,7 @@ func (h *PostHandler) List(c *gin.Context) {
 	var err error
 	
 	if keyword != "" {
-		posts, total, err = h.repo.Search(keyword, page, limit)
+		posts, total, err = h.search(keyword, page, limit)
 	} else if tag != "" {
 		posts, total, err = h.repo.FindByTag(tag, page, limit)
 	} else {
@@ -63,6 +81,8 @@ func (h *PostHandler) List(c *gin.Context) {
 	for i, post := range posts {
 		items[i] = post.ToListVO()
 	}
+	localizePostList(h.translationRepo, items, requestedLocales(c))
+	attachPostListReactions(items)
 	
 	response.PagedSuccess(c, items, page, limit, total)
 }
@@ -71,9 +91,24 @@ func (h *PostHandler) List(c *gin.Context) {
 func (h *PostHandler) GetBySlug(c *gin.Context) {
 	slug := c.Param("slug")
 	
+	preferred := requestedLocales(c)
+	
 	post, err := h.repo.FindBySlug(slug)
 	if err != nil {
-		response.NotFound(c, "文章不存在")
+		// 翻译的 slug：未指定语言时返回该翻译
+		translation, terr := h.translationRepo.FindBySlug(slug)
+		if terr == nil {
+			post, err = h.repo.FindByID(translation.PostID)
+			if c.Query("lang") == "" {
+				preferred = []string{translation.Locale}
+			}
+		}
+	}
+	if err != nil {
+		// 旧 slug 重定向到当前地址
+		if !respondSlugRedirect(c, h.redirectRepo, model.RedirectTypePost, slug) {
+			response.NotFound(c, "文章不存在")
+		}
 		return
 	}
 	
@@ -83,7 +118,77 @@ func (h *PostHandler) GetBySlug(c *gin.Context) {
 		return
 	}
 	
-	response.Success(c, post.ToVO())
+	vo := post.ToVO()
+	vo.OGImage = postShareImage(post)
+	
+	// 选择语言版本
+	translations, err := h.translationRepo.FindByPost(post.ID)
+	if err != nil {
+		log.Printf("Failed to load translations for post %d: %v", post.ID, err)
+	}
+	localizePost(&vo, translations, preferred)
+	vo.Reactions = reactionCounts(model.ReactionTargetPost, []uint{post.ID})[post.ID]
+	
+	if post.IsProtected() && !h.unlocked(c, post) {
+		// 未解锁的加密文章只返回标题等元信息
+		vo.Content = ""
+		vo.Locked = true
+	} else {
+		render.Post(&vo)
+	}
+	
+	// 所属系列及前后篇
+	if series, err := h.seriesRepo.FindPostSeries(post.ID); err != nil {
+		log.Printf("Failed to load series for post %d: %v", post.ID, err)
+	} else {
+		vo.Series = series
+	}
+	
+	response.Success(c, vo)
+}
+
+// Unlock 校验访问密码，解锁加密文章
+// 返回的令牌通过 X-Unlock-Token 头或 unlock_token 参数携带，在有效期内访问文章详情
+func (h *PostHandler) Unlock(c *gin.Context) {
+	id, err := GetIDParam(c, "id")
+	if err != nil {
+		response.BadRequest(c, "无效的文章 ID")
+		return
+	}
+	
+	var req model.UnlockPostRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	
+	post, err := h.repo.FindByID(id)
+	if err != nil || post.Status != string(constants.PostStatusPublished) {
+		response.NotFound(c, "文章不存在")
+		return
+	}
+	
+	if !post.IsProtected() {
+		response.BadRequest(c, "文章未加密")
+		return
+	}
+	
+	if !utils.CheckPassword(req.Password, post.PasswordHash) {
+		response.Forbidden(c, constants.MsgUnlockFailed)
+		return
+	}
+	
+	expiresAt := time.Now().Add(constants.PostUnlockExpiry)
+	token, err := middleware.GenerateUnlockToken(post.ID, post.PasswordFingerprint(), expiresAt)
+	if err != nil {
+		response.InternalError(c, "")
+		return
+	}
+	
+	response.Success(c, model.UnlockPostVO{
+		Token:     token,
+		ExpiresAt: expiresAt,
+	})
 }
 
 // IncrementViews 增加阅读量
@@ -137,18 +242,35 @@ func (h *PostHandler) IncrementViews(c *gin.Context) {
 }
 
 // Featured 获取推荐文章
+// 优先返回后台精选的文章，不足时按阅读量补齐
 func (h *PostHandler) Featured(c *gin.Context) {
-	limit := 5
-	posts, err := h.repo.FindFeatured(limit)
+	limit := constants.FeaturedPostsLimit
+	posts, err := h.repo.FindCurated(limit)
 	if err != nil {
 		response.InternalError(c, "")
 		return
 	}
 	
+	if len(posts) < limit {
+		excludeIDs := make([]uint, len(posts))
+		for i, post := range posts {
+			excludeIDs[i] = post.ID
+		}
+		
+		popular, err := h.repo.FindFeatured(limit-len(posts), excludeIDs)
+		if err != nil {
+			response.InternalError(c, "")
+			return
+		}
+		posts = append(posts, popular...)
+	}
+	
 	items := make([]model.PostListVO, len(posts))
 	for i, post := range posts {
 		items[i] = post.ToListVO()
 	}
+	localizePostList(h.translationRepo, items, requestedLocales(c))
+	attachPostListReactions(items)
 	
 	response.Success(c, items)
 }
@@ -190,7 +312,108 @@ func (h *PostHandler) AdminGet(c *gin.Context) {
 		return
 	}
 	
-	response.Success(c, post.ToVO())
+	vo := post.ToVO()
+	vo.OGImage = postShareImage(post)
+	render.Post(&vo)
+	
+	response.Success(c, vo)
+}
+
+// Pin 置顶文章
+func (h *PostHandler) Pin(c *gin.Context) {
+	id, err := GetIDParam(c, "id")
+	if err != nil {
+		response.BadRequest(c, "无效的文章 ID")
+		return
+	}
+	
+	var req model.PinPostRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	
+	if req.Until != nil && !req.Until.After(time.Now()) {
+		response.BadRequest(c, "置顶到期时间必须晚于当前时间")
+		return
+	}
+	
+	if _, err := h.repo.FindByID(id); err != nil {
+		response.NotFound(c, "文章不存在")
+		return
+	}
+	
+	if err := h.repo.Pin(id, req.Order, req.Until); err != nil {
+		response.InternalError(c, "置顶失败")
+		return
+	}
+	
+	post, _ := h.repo.FindByID(id)
+	response.SuccessMessage(c, constants.MsgUpdateSuccess, post.ToVO())
+}
+
+// Unpin 取消置顶
+func (h *PostHandler) Unpin(c *gin.Context) {
+	id, err := GetIDParam(c, "id")
+	if err != nil {
+		response.BadRequest(c, "无效的文章 ID")
+		return
+	}
+	
+	if err := h.repo.Unpin(id); err != nil {
+		response.InternalError(c, "取消置顶失败")
+		return
+	}
+	
+	response.SuccessMessage(c, constants.MsgUpdateSuccess, nil)
+}
+
+// AdminPinned 管理后台置顶文章列表（包含已过期的置顶）
+func (h *PostHandler) AdminPinned(c *gin.Context) {
+	posts, err := h.repo.FindPinned()
+	if err != nil {
+		response.InternalError(c, "")
+		return
+	}
+	
+	items := make([]model.PostVO, len(posts))
+	for i, post := range posts {
+		items[i] = post.ToVO()
+	}
+	
+	response.Success(c, items)
+}
+
+// AdminFeatured 管理后台精选文章列表
+func (h *PostHandler) AdminFeatured(c *gin.Context) {
+	posts, err := h.repo.FindAllCurated()
+	if err != nil {
+		response.InternalError(c, "")
+		return
+	}
+	
+	items := make([]model.PostVO, len(posts))
+	for i, post := range posts {
+		items[i] = post.ToVO()
+	}
+	
+	response.Success(c, items)
+}
+
+// SetFeatured 设置精选文章及顺序
+func (h *PostHandler) SetFeatured(c *gin.Context) {
+	var req model.SetFeaturedRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	
+	if err := h.repo.SetFeatured(req.PostIDs); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	
+	h.AdminFeatured(c)
 }
 
 // Create 创建文章
@@ -218,21 +441,29 @@ func (h *PostHandler) Create(c *gin.Context) {
 		excerpt = utils.GenerateExcerpt(req.Content, constants.ExcerptMaxLength)
 	}
 	
-	// 创建文章
-	post := model.Post{
-		Title:      req.Title,
-		Slug:       slug,
-		Content:    req.Content,
-		Excerpt:    excerpt,
-		CoverImage: req.CoverImage,
-		Status:     req.Status,
-		AuthorID:   middleware.GetUserID(c),
+	// 计算发布状态和发布时间
+	status, publishedAt, err := resolvePublish(req.Status, req.PublishAt, "", nil)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
 	}
 	
-	// 设置发布时间
-	if req.Status == string(constants.PostStatusPublished) {
-		now := time.Now()
-		post.PublishedAt = &now
+	// 创建文章
+	post := model.Post{
+		Title:       req.Title,
+		Slug:        slug,
+		Content:     req.Content,
+		Excerpt:     excerpt,
+		CoverImage:  req.CoverImage,
+		Status:      status,
+		AuthorID:    middleware.GetUserID(c),
+		PublishedAt: publishedAt,
+	}
+	
+	// 可见性和访问密码
+	if err := applyVisibility(&post, req.Visibility, req.Password); err != nil {
+		response.BadRequest(c, err.Error())
+		return
 	}
 	
 	if err := h.repo.Create(&post); err != nil {
@@ -245,12 +476,27 @@ func (h *PostHandler) Create(c *gin.Context) {
 		h.repo.UpdateTags(&post, req.TagIDs)
 	}
 	
+	// 保存修订快照
+	h.saveRevision(&post, constants.RevisionReasonCreate, middleware.GetUserID(c))
+	
+	// 更新检索索引
+	search.SyncPost(&post)
+	related.Refresh(post.ID)
+	
 	// 重新加载关联
 	reloadedPost, err := h.repo.FindByID(post.ID)
 	if err == nil {
 		post = *reloadedPost
 	}
 	
+	// 生成分享图
+	ogimage.Refresh(&post)
+	
+	// 通知搜索引擎
+	if ping.PublicPost(&post) {
+		ping.NotifyPost(model.PingReasonPublish, &post, "")
+	}
+	
 	response.Created(c, post.ToVO())
 }
 
@@ -274,6 +520,9 @@ func (h *PostHandler) Update(c *gin.Context) {
 		return
 	}
 	
+	oldSlug := post.Slug
+	wasPublic := ping.PublicPost(post)
+	
 	// 更新字段
 	if req.Title != "" {
 		post.Title = req.Title
@@ -297,13 +546,23 @@ func (h *PostHandler) Update(c *gin.Context) {
 	if req.CoverImage != "" {
 		post.CoverImage = req.CoverImage
 	}
-	if req.Status != "" {
-		// 首次发布设置发布时间
-		if req.Status == string(constants.PostStatusPublished) && post.PublishedAt == nil {
-			now := time.Now()
-			post.PublishedAt = &now
+	if req.Status != "" || req.PublishAt != nil {
+		// 未指定状态时按当前状态调整发布时间
+		status := req.Status
+		if status == "" {
+			status = post.Status
+		}
+		status, publishedAt, err := resolvePublish(status, req.PublishAt, post.Status, post.PublishedAt)
+		if err != nil {
+			response.BadRequest(c, err.Error())
+			return
 		}
-		post.Status = req.Status
+		post.Status = status
+		post.PublishedAt = publishedAt
+	}
+	if err := applyVisibility(post, req.Visibility, req.Password); err != nil {
+		response.BadRequest(c, err.Error())
+		return
 	}
 	
 	if err := h.repo.Update(post); err != nil {
@@ -311,6 +570,14 @@ func (h *PostHandler) Update(c *gin.Context) {
 		return
 	}
 	
+	// 保存修订快照
+	h.saveRevision(post, constants.RevisionReasonUpdate, middleware.GetUserID(c))
+	
+	// 记录旧 slug，旧链接重定向到新地址
+	if err := h.redirectRepo.RecordSlugChange(model.RedirectTypePost, post.ID, oldSlug, post.Slug); err != nil {
+		log.Printf("Failed to record slug redirect for post %d: %v", post.ID, err)
+	}
+	
 	// 更新标签
 	if req.TagIDs != nil {
 		h.repo.UpdateTags(post, req.TagIDs)
@@ -319,6 +586,18 @@ func (h *PostHandler) Update(c *gin.Context) {
 	// 重新加载
 	post, _ = h.repo.FindByID(id)
 	
+	// 更新检索索引
+	search.SyncPost(post)
+	related.Refresh(id)
+	
+	// 标题或标签变化后重新生成分享图
+	ogimage.Refresh(post)
+	
+	// 通知搜索引擎（slug 变更时旧地址一并提交）
+	if reason, ok := ping.ChangeReason(wasPublic, ping.PublicPost(post)); ok {
+		ping.NotifyPost(reason, post, oldSlug)
+	}
+	
 	response.SuccessMessage(c, constants.MsgUpdateSuccess, post.ToVO())
 }
 
@@ -341,9 +620,105 @@ func (h *PostHandler) Delete(c *gin.Context) {
 		return
 	}
 	
+	search.Remove(search.DocTypePost, id)
+	h.seriesRepo.RemovePost(id)
+	h.relatedRepo.DeleteOverridesByPost(id)
+	h.redirectRepo.DeleteByTarget(model.RedirectTypePost, id)
+	if translations, err := h.translationRepo.FindByPost(id); err == nil {
+		for _, translation := range translations {
+			h.redirectRepo.DeleteByTarget(model.RedirectTypeTranslation, translation.ID)
+		}
+	}
+	h.translationRepo.DeleteByPost(id)
+	h.reactionRepo.DeleteByTargets(model.ReactionTargetPost, []uint{id})
+	ogimage.Delete(id)
+	related.Refresh(id)
+	
+	if ping.PublicPost(post) {
+		ping.NotifyPost(model.PingReasonDelete, post, "")
+	}
+	
 	response.SuccessMessage(c, constants.MsgDeleteSuccess, nil)
 }
 
+// unlocked 请求是否携带了该文章有效的解锁令牌
+func (h *PostHandler) unlocked(c *gin.Context, post *model.Post) bool {
+	token := c.GetHeader("X-Unlock-Token")
+	if token == "" {
+		token = c.Query("unlock_token")
+	}
+	if token == "" {
+		return false
+	}
+	
+	claims, err := middleware.ParseUnlockToken(token)
+	if err != nil {
+		return false
+	}
+	return claims.PostID == post.ID && claims.Fingerprint == post.PasswordFingerprint()
+}
+
+// applyVisibility 设置文章可见性，visibility 为空时保持不变
+// 加密文章必须设置密码；取消加密时清除密码，自动生成的摘要会泄露正文，一并清空
+func applyVisibility(post *model.Post, visibility, password string) error {
+	if visibility != "" {
+		post.Visibility = visibility
+	}
+	
+	if !post.IsProtected() {
+		post.PasswordHash = ""
+		return nil
+	}
+	
+	if password != "" {
+		hash, err := utils.HashPassword(password)
+		if err != nil {
+			return err
+		}
+		post.PasswordHash = hash
+	}
+	if post.PasswordHash == "" {
+		return errors.New(constants.MsgPasswordRequired)
+	}
+	
+	if post.Excerpt == utils.GenerateExcerpt(post.Content, constants.ExcerptMaxLength) {
+		post.Excerpt = ""
+	}
+	return nil
+}
+
+// search 全文检索文章，索引不可用时退化为 LIKE 查询
+func (h *PostHandler) search(keyword string, page, limit int) ([]model.Post, int64, error) {
+	result, err := search.Query(keyword, search.DocTypePost, page, limit)
+	if err != nil {
+		log.Printf("Search index query failed, falling back to LIKE: %v", err)
+		return h.repo.Search(keyword, page, limit)
+	}
+	
+	ids := make([]uint, len(result.Hits))
+	for i, hit := range result.Hits {
+		ids[i] = hit.DocID
+	}
+	
+	posts, err := h.repo.FindPublishedByIDs(ids)
+	return posts, result.Total, err
+}
+
+// saveRevision 保存文章修订快照，失败仅记录日志
+func (h *PostHandler) saveRevision(post *model.Post, reason constants.RevisionReason, editorID uint) {
+	revision := model.NewPostRevision(post, string(reason), editorID)
+	saved, err := h.revisionRepo.Save(&revision)
+	if err != nil {
+		log.Printf("Failed to save post revision: %v", err)
+		return
+	}
+	
+	// 预先渲染新修订的正文
+	if saved {
+		render.Markdown(post.Content)
+	}
+}
+
 // ===========================================
 // 归档接口
 // ===========================================
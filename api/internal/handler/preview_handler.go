@@ -0,0 +1,238 @@
+// Package handler 草稿预览处理器
+package handler
+
+import (
+	"errors"
+	"io"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"kuaiyu/internal/database"
+	"kuaiyu/internal/middleware"
+	"kuaiyu/internal/model"
+	"kuaiyu/internal/render"
+	"kuaiyu/internal/repository"
+	"kuaiyu/pkg/constants"
+	"kuaiyu/pkg/response"
+	"kuaiyu/pkg/utils"
+)
+
+// ===========================================
+// 草稿预览处理器
+// ===========================================
+
+// PreviewHandler 草稿预览处理器
+type PreviewHandler struct {
+	postRepo *repository.PostRepository
+}
+
+// NewPreviewHandler 创建草稿预览处理器
+func NewPreviewHandler() *PreviewHandler {
+	return &PreviewHandler{
+		postRepo: repository.NewPostRepository(),
+	}
+}
+
+// ===========================================
+// 公开接口
+// ===========================================
+
+// Get 通过预览令牌获取内容（不限发布状态）
+func (h *PreviewHandler) Get(c *gin.Context) {
+	// 预览内容不应被缓存或收录
+	c.Header("Cache-Control", "no-store")
+	c.Header("X-Robots-Tag", "noindex, nofollow")
+
+	claims, err := middleware.ParsePreviewToken(c.Param("token"))
+	if err != nil {
+		response.NotFound(c, constants.MsgPreviewInvalid)
+		return
+	}
+
+	// 原子地校验链接状态并计数，已撤销或过期的链接不会命中
+	now := time.Now()
+	db := database.Get()
+	result := db.Model(&model.PreviewLink{}).
+		Where("link_id = ? AND target_type = ? AND target_id = ?", claims.ID, claims.TargetType, claims.TargetID).
+		Where("revoked_at IS NULL AND expires_at > ?", now).
+		Updates(map[string]interface{}{
+			"views":          gorm.Expr("views + 1"),
+			"last_viewed_at": now,
+		})
+	if result.Error != nil {
+		response.InternalError(c, "")
+		return
+	}
+	if result.RowsAffected == 0 {
+		response.NotFound(c, constants.MsgPreviewInvalid)
+		return
+	}
+
+	preview := model.PreviewVO{
+		Type:      claims.TargetType,
+		ExpiresAt: claims.ExpiresAt.Time,
+	}
+
+	switch claims.TargetType {
+	case "post":
+		post, err := h.postRepo.FindByID(claims.TargetID)
+		if err != nil {
+			response.NotFound(c, "文章不存在")
+			return
+		}
+		vo := post.ToVO()
+		render.Post(&vo)
+		preview.Post = &vo
+	case "life":
+		var record model.LifeRecord
+		if err := db.First(&record, claims.TargetID).Error; err != nil {
+			response.NotFound(c, "记录不存在")
+			return
+		}
+		vo := record.ToVO()
+		render.Life(&vo)
+		preview.Life = &vo
+	default:
+		response.NotFound(c, constants.MsgPreviewInvalid)
+		return
+	}
+
+	response.Success(c, preview)
+}
+
+// ===========================================
+// 管理接口
+// ===========================================
+
+// CreatePost 为文章创建预览链接
+func (h *PreviewHandler) CreatePost(c *gin.Context) {
+	h.create(c, "post")
+}
+
+// ListPost 文章的预览链接列表
+func (h *PreviewHandler) ListPost(c *gin.Context) {
+	h.list(c, "post")
+}
+
+// CreateLife 为生活记录创建预览链接
+func (h *PreviewHandler) CreateLife(c *gin.Context) {
+	h.create(c, "life")
+}
+
+// ListLife 生活记录的预览链接列表
+func (h *PreviewHandler) ListLife(c *gin.Context) {
+	h.list(c, "life")
+}
+
+// Revoke 撤销预览链接
+func (h *PreviewHandler) Revoke(c *gin.Context) {
+	id, err := GetIDParam(c, "id")
+	if err != nil {
+		response.BadRequest(c, "无效的 ID")
+		return
+	}
+
+	var link model.PreviewLink
+	db := database.Get()
+	if err := db.First(&link, id).Error; err != nil {
+		response.NotFound(c, "预览链接不存在")
+		return
+	}
+
+	if link.RevokedAt == nil {
+		now := time.Now()
+		link.RevokedAt = &now
+		if err := db.Model(&link).Update("revoked_at", now).Error; err != nil {
+			response.InternalError(c, "撤销失败")
+			return
+		}
+	}
+
+	response.SuccessMessage(c, constants.MsgOperationSuccess, link.ToVO())
+}
+
+// ===========================================
+// 通用实现
+// ===========================================
+
+// create 创建预览链接
+func (h *PreviewHandler) create(c *gin.Context, targetType string) {
+	id, err := GetIDParam(c, "id")
+	if err != nil {
+		response.BadRequest(c, "无效的 ID")
+		return
+	}
+
+	// 请求体可选，为空时使用默认有效期
+	var req model.CreatePreviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	db := database.Get()
+	var target interface{} = &model.Post{}
+	if targetType == "life" {
+		target = &model.LifeRecord{}
+	}
+	if err := db.Select("id").First(target, id).Error; err != nil {
+		response.NotFound(c, "内容不存在")
+		return
+	}
+
+	expiry := constants.PreviewDefaultExpiry
+	if req.ExpiresIn > 0 {
+		expiry = time.Duration(req.ExpiresIn) * time.Hour
+	}
+
+	link := model.PreviewLink{
+		LinkID:     utils.GenerateRandomString(32),
+		TargetType: targetType,
+		TargetID:   id,
+		Note:       req.Note,
+		ExpiresAt:  time.Now().Add(expiry),
+		CreatedBy:  middleware.GetUserID(c),
+	}
+
+	token, err := middleware.GeneratePreviewToken(link.LinkID, targetType, id, link.ExpiresAt)
+	if err != nil {
+		response.InternalError(c, "生成令牌失败")
+		return
+	}
+
+	if err := db.Create(&link).Error; err != nil {
+		response.InternalError(c, "创建失败")
+		return
+	}
+
+	vo := link.ToVO()
+	vo.Token = token
+
+	response.Created(c, vo)
+}
+
+// list 预览链接列表
+func (h *PreviewHandler) list(c *gin.Context, targetType string) {
+	id, err := GetIDParam(c, "id")
+	if err != nil {
+		response.BadRequest(c, "无效的 ID")
+		return
+	}
+
+	var links []model.PreviewLink
+	db := database.Get()
+	if err := db.Where("target_type = ? AND target_id = ?", targetType, id).
+		Order("created_at DESC").
+		Find(&links).Error; err != nil {
+		response.InternalError(c, "")
+		return
+	}
+
+	items := make([]model.PreviewLinkVO, len(links))
+	for i, link := range links {
+		items[i] = link.ToVO()
+	}
+
+	response.Success(c, items)
+}
@@ -0,0 +1,324 @@
+// Package handler 读者表态处理器
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"kuaiyu/internal/config"
+	"kuaiyu/internal/database"
+	"kuaiyu/internal/model"
+	"kuaiyu/internal/repository"
+	"kuaiyu/pkg/constants"
+	"kuaiyu/pkg/response"
+	"kuaiyu/pkg/utils"
+)
+
+// ===========================================
+// 读者表态处理器
+// ===========================================
+
+// ReactionHandler 读者表态处理器
+type ReactionHandler struct {
+	repo *repository.ReactionRepository
+}
+
+// NewReactionHandler 创建读者表态处理器
+func NewReactionHandler() *ReactionHandler {
+	return &ReactionHandler{
+		repo: repository.NewReactionRepository(),
+	}
+}
+
+// ===========================================
+// 公开接口
+// ===========================================
+
+// Summary 获取对象的表态汇总
+// GET /api/reactions?target_type=post&target_id=1
+func (h *ReactionHandler) Summary(c *gin.Context) {
+	targetType := c.Query("target_type")
+	targetID, _ := strconv.ParseUint(c.Query("target_id"), 10, 32)
+	if !isReactionTarget(targetType) || targetID == 0 {
+		response.BadRequest(c, "无效的表态对象")
+		return
+	}
+
+	summary, err := h.summary(targetType, uint(targetID), reactionFingerprint(c, c.Query("client_id")))
+	if err != nil {
+		response.InternalError(c, "")
+		return
+	}
+
+	response.Success(c, summary)
+}
+
+// Toggle 切换表态：未表态则添加，已表态则取消
+func (h *ReactionHandler) Toggle(c *gin.Context) {
+	var req model.ToggleReactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	if !isReactionEmoji(req.Emoji) {
+		response.BadRequest(c, "不支持的表情")
+		return
+	}
+
+	if !reactionTargetExists(req.TargetType, req.TargetID) {
+		response.NotFound(c, "表态对象不存在")
+		return
+	}
+
+	fingerprint := reactionFingerprint(c, req.ClientID)
+	active, err := h.repo.Toggle(&model.Reaction{
+		TargetType:  req.TargetType,
+		TargetID:    req.TargetID,
+		Emoji:       req.Emoji,
+		Fingerprint: fingerprint,
+	})
+	if err != nil {
+		response.InternalError(c, "")
+		return
+	}
+
+	summary, err := h.summary(req.TargetType, req.TargetID, fingerprint)
+	if err != nil {
+		response.InternalError(c, "")
+		return
+	}
+	summary.Active = active
+
+	response.Success(c, summary)
+}
+
+// ===========================================
+// 管理接口
+// ===========================================
+
+// Trends 表态趋势：按天和表情统计，并列出表态最多的对象
+// GET /api/admin/reactions/trends?days=30&target_type=post
+func (h *ReactionHandler) Trends(c *gin.Context) {
+	days, _ := strconv.Atoi(c.DefaultQuery("days", "30"))
+	if days < 1 || days > 365 {
+		days = 30
+	}
+	targetType := c.Query("target_type")
+	if targetType != "" && !isReactionTarget(targetType) {
+		response.BadRequest(c, "无效的表态对象类型")
+		return
+	}
+
+	now := time.Now()
+	start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()).AddDate(0, 0, -days+1)
+
+	daily, err := h.repo.CountDaily(targetType, start)
+	if err != nil {
+		response.InternalError(c, "")
+		return
+	}
+	top, err := h.repo.FindTopTargets(targetType, start, 10)
+	if err != nil {
+		response.InternalError(c, "")
+		return
+	}
+
+	byDate := make(map[string]*model.ReactionDayVO, days)
+	trend := model.ReactionTrendVO{
+		Days:       make([]model.ReactionDayVO, days),
+		Totals:     make(map[string]int),
+		TopTargets: make([]model.ReactionTargetVO, 0, len(top)),
+	}
+	for i := range trend.Days {
+		date := start.AddDate(0, 0, i).Format("2006-01-02")
+		trend.Days[i] = model.ReactionDayVO{Date: date, Counts: make(map[string]int)}
+		byDate[date] = &trend.Days[i]
+	}
+	for _, row := range daily {
+		if day, ok := byDate[row.Date]; ok {
+			day.Counts[row.Emoji] += row.Count
+			day.Total += row.Count
+		}
+		trend.Totals[row.Emoji] += row.Count
+	}
+	for _, row := range top {
+		trend.TopTargets = append(trend.TopTargets, model.ReactionTargetVO{
+			TargetType: row.TargetType,
+			TargetID:   row.TargetID,
+			Title:      reactionTargetTitle(row.TargetType, row.TargetID),
+			Count:      row.Count,
+		})
+	}
+
+	response.Success(c, trend)
+}
+
+// ===========================================
+// 辅助函数
+// ===========================================
+
+// summary 构建对象的表态汇总
+func (h *ReactionHandler) summary(targetType string, targetID uint, fingerprint string) (*model.ReactionSummaryVO, error) {
+	counts, err := h.repo.CountByTargets(targetType, []uint{targetID})
+	if err != nil {
+		return nil, err
+	}
+	mine, err := h.repo.FindMine(targetType, targetID, fingerprint)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &model.ReactionSummaryVO{
+		TargetType: targetType,
+		TargetID:   targetID,
+		Emojis:     config.Get().Reaction.Emojis,
+		Counts:     counts[targetID],
+		Mine:       mine,
+	}
+	if summary.Counts == nil {
+		summary.Counts = map[string]int{}
+	}
+	if summary.Mine == nil {
+		summary.Mine = []string{}
+	}
+	return summary, nil
+}
+
+// reactionCounts 批量查询列表中各对象的表态数，失败时返回空结果
+func reactionCounts(targetType string, targetIDs []uint) map[uint]map[string]int {
+	counts, err := repository.NewReactionRepository().CountByTargets(targetType, targetIDs)
+	if err != nil {
+		log.Printf("Failed to count %s reactions: %v", targetType, err)
+		return map[uint]map[string]int{}
+	}
+	return counts
+}
+
+// attachPostListReactions 为文章列表填充表态数
+func attachPostListReactions(items []model.PostListVO) {
+	ids := make([]uint, len(items))
+	for i := range items {
+		ids[i] = items[i].ID
+	}
+	counts := reactionCounts(model.ReactionTargetPost, ids)
+	for i := range items {
+		items[i].Reactions = counts[items[i].ID]
+	}
+}
+
+// attachLifeListReactions 为生活记录列表填充表态数
+func attachLifeListReactions(items []model.LifeRecordListVO) {
+	ids := make([]uint, len(items))
+	for i := range items {
+		ids[i] = items[i].ID
+	}
+	counts := reactionCounts(model.ReactionTargetLife, ids)
+	for i := range items {
+		items[i].Reactions = counts[items[i].ID]
+	}
+}
+
+// attachCommentReactions 为评论及其回复填充表态数
+func attachCommentReactions(items []model.CommentVO) {
+	var ids []uint
+	for i := range items {
+		ids = append(ids, items[i].ID)
+		for j := range items[i].Replies {
+			ids = append(ids, items[i].Replies[j].ID)
+		}
+	}
+	counts := reactionCounts(model.ReactionTargetComment, ids)
+	for i := range items {
+		items[i].Reactions = counts[items[i].ID]
+		for j := range items[i].Replies {
+			items[i].Replies[j].Reactions = counts[items[i].Replies[j].ID]
+		}
+	}
+}
+
+// reactionFingerprint 读者的匿名指纹：优先使用客户端 ID，否则使用 IP+UA，只保存哈希
+func reactionFingerprint(c *gin.Context, clientID string) string {
+	if clientID == "" {
+		clientID = c.GetHeader("X-Client-ID")
+	}
+
+	var source string
+	if clientID != "" {
+		source = "cid:" + clientID
+	} else {
+		source = "ipua:" + GetClientIP(c) + "|" + GetUserAgent(c)
+	}
+
+	sum := sha256.Sum256([]byte(source))
+	return hex.EncodeToString(sum[:])
+}
+
+// isReactionTarget 是否为可表态的对象类型
+func isReactionTarget(targetType string) bool {
+	switch targetType {
+	case model.ReactionTargetPost, model.ReactionTargetLife, model.ReactionTargetComment:
+		return true
+	}
+	return false
+}
+
+// isReactionEmoji 是否为配置中允许的表情
+func isReactionEmoji(emoji string) bool {
+	for _, allowed := range config.Get().Reaction.Emojis {
+		if emoji == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// reactionTargetExists 表态对象是否存在且公开可见
+func reactionTargetExists(targetType string, targetID uint) bool {
+	db := database.Get()
+	var count int64
+
+	switch targetType {
+	case model.ReactionTargetPost:
+		db.Model(&model.Post{}).Scopes(repository.PublishedScope("posts")).
+			Where("id = ?", targetID).Count(&count)
+	case model.ReactionTargetLife:
+		db.Model(&model.LifeRecord{}).Scopes(repository.PublishedScope("life_records")).
+			Where("id = ?", targetID).Count(&count)
+	case model.ReactionTargetComment:
+		db.Model(&model.Comment{}).
+			Where("id = ? AND status = ?", targetID, constants.CommentStatusApproved).Count(&count)
+	}
+	return count > 0
+}
+
+// reactionTargetTitle 表态对象的标题，评论和无标题的生活记录取内容摘要
+func reactionTargetTitle(targetType string, targetID uint) string {
+	db := database.Get()
+
+	switch targetType {
+	case model.ReactionTargetPost:
+		var post model.Post
+		if db.Unscoped().Select("id", "title").First(&post, targetID).Error == nil {
+			return post.Title
+		}
+	case model.ReactionTargetLife:
+		var record model.LifeRecord
+		if db.Unscoped().Select("id", "title", "content").First(&record, targetID).Error == nil {
+			if record.Title != "" {
+				return record.Title
+			}
+			return utils.GenerateExcerpt(record.Content, 50)
+		}
+	case model.ReactionTargetComment:
+		var comment model.Comment
+		if db.Unscoped().Select("id", "content").First(&comment, targetID).Error == nil {
+			return utils.GenerateExcerpt(comment.Content, 50)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,276 @@
+// Package handler 重定向处理器
+package handler
+
+import (
+	"errors"
+	"log"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"kuaiyu/internal/database"
+	"kuaiyu/internal/model"
+	"kuaiyu/internal/repository"
+	"kuaiyu/pkg/constants"
+	"kuaiyu/pkg/response"
+	"kuaiyu/pkg/utils"
+)
+
+// ===========================================
+// 重定向处理器
+// ===========================================
+
+// RedirectHandler 重定向处理器
+type RedirectHandler struct {
+	repo *repository.RedirectRepository
+}
+
+// NewRedirectHandler 创建重定向处理器
+func NewRedirectHandler() *RedirectHandler {
+	return &RedirectHandler{
+		repo: repository.NewRedirectRepository(),
+	}
+}
+
+// ===========================================
+// 公开接口
+// ===========================================
+
+// Resolve 解析自定义重定向
+// GET /api/redirects/resolve?path=/p/123，命中时返回 301/302 和目标地址
+func (h *RedirectHandler) Resolve(c *gin.Context) {
+	source := normalizeRedirectPath(c.Query("path"))
+	if source == "" {
+		response.BadRequest(c, "缺少 path 参数")
+		return
+	}
+
+	redirect, err := h.repo.FindBySource(model.RedirectTypeCustom, source)
+	if err != nil {
+		response.NotFound(c, "")
+		return
+	}
+
+	recordRedirectHit(h.repo, redirect)
+	respondRedirect(c, &model.RedirectVO{
+		Type:      model.RedirectTypeCustom,
+		Location:  redirect.Target,
+		Permanent: redirect.Permanent,
+	})
+}
+
+// ===========================================
+// 管理接口
+// ===========================================
+
+// List 重定向列表，可按 type 筛选
+func (h *RedirectHandler) List(c *gin.Context) {
+	page, limit := GetPageParams(c)
+
+	redirects, total, err := h.repo.FindAll(c.Query("type"), page, limit)
+	if err != nil {
+		response.InternalError(c, "")
+		return
+	}
+
+	response.PagedSuccess(c, redirects, page, limit, total)
+}
+
+// Create 创建自定义重定向
+func (h *RedirectHandler) Create(c *gin.Context) {
+	var req model.CreateRedirectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	redirect := model.SlugRedirect{
+		Type:      model.RedirectTypeCustom,
+		Source:    normalizeRedirectPath(req.Source),
+		Target:    strings.TrimSpace(req.Target),
+		Permanent: req.Permanent == nil || *req.Permanent,
+	}
+	if err := validateCustomRedirect(&redirect); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	if err := h.repo.Create(&redirect); err != nil {
+		response.BadRequest(c, constants.MsgRedirectExists)
+		return
+	}
+
+	// 显式写入 false，避免被数据库默认值覆盖
+	if !redirect.Permanent {
+		h.repo.Save(&redirect)
+	}
+
+	response.Created(c, redirect)
+}
+
+// Update 更新自定义重定向
+func (h *RedirectHandler) Update(c *gin.Context) {
+	id, err := GetIDParam(c, "id")
+	if err != nil {
+		response.BadRequest(c, "无效的 ID")
+		return
+	}
+
+	var req model.UpdateRedirectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	redirect, err := h.repo.FindByID(id)
+	if err != nil {
+		response.NotFound(c, "重定向不存在")
+		return
+	}
+
+	// 文章和标签的旧 slug 由系统维护，只允许删除
+	if redirect.Type != model.RedirectTypeCustom {
+		response.BadRequest(c, "只能修改自定义重定向")
+		return
+	}
+
+	if req.Source != "" {
+		redirect.Source = normalizeRedirectPath(req.Source)
+	}
+	if req.Target != "" {
+		redirect.Target = strings.TrimSpace(req.Target)
+	}
+	if req.Permanent != nil {
+		redirect.Permanent = *req.Permanent
+	}
+	if err := validateCustomRedirect(redirect); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	if err := h.repo.Save(redirect); err != nil {
+		response.BadRequest(c, constants.MsgRedirectExists)
+		return
+	}
+
+	response.SuccessMessage(c, constants.MsgUpdateSuccess, redirect)
+}
+
+// Delete 删除重定向
+func (h *RedirectHandler) Delete(c *gin.Context) {
+	id, err := GetIDParam(c, "id")
+	if err != nil {
+		response.BadRequest(c, "无效的 ID")
+		return
+	}
+
+	if err := h.repo.Delete(id); err != nil {
+		response.InternalError(c, "删除失败")
+		return
+	}
+
+	response.SuccessMessage(c, constants.MsgDeleteSuccess, nil)
+}
+
+// ===========================================
+// 旧 slug 解析
+// ===========================================
+
+// respondSlugRedirect 旧 slug 命中重定向时返回目标的当前地址
+// 文章地址同时查找翻译的旧 slug；返回 false 表示没有可用的重定向，由调用方返回 404
+func respondSlugRedirect(c *gin.Context, repo *repository.RedirectRepository, redirectType, slug string) bool {
+	redirect, err := repo.FindBySource(redirectType, slug)
+	if err != nil && redirectType == model.RedirectTypePost {
+		redirect, err = repo.FindBySource(model.RedirectTypeTranslation, slug)
+	}
+	if err != nil {
+		return false
+	}
+
+	db := database.Get()
+	vo := &model.RedirectVO{Type: redirectType, Permanent: redirect.Permanent}
+
+	switch redirect.Type {
+	case model.RedirectTypePost:
+		var post model.Post
+		err = db.Scopes(repository.PublishedScope("posts")).
+			Select("id", "slug").
+			First(&post, redirect.TargetID).Error
+		vo.Slug = post.Slug
+		vo.Location = "/blog/" + post.Slug
+	case model.RedirectTypeTranslation:
+		// 翻译跳转到翻译的当前 slug，原文未发布时不跳转
+		var translation model.PostTranslation
+		err = db.Select("id", "post_id", "slug").First(&translation, redirect.TargetID).Error
+		if err == nil {
+			err = db.Scopes(repository.PublishedScope("posts")).
+				Select("id").
+				First(&model.Post{}, translation.PostID).Error
+		}
+		vo.Slug = translation.Slug
+		vo.Location = "/blog/" + translation.Slug
+	case model.RedirectTypeTag:
+		var tag model.Tag
+		err = db.Select("id", "slug").First(&tag, redirect.TargetID).Error
+		vo.Slug = tag.Slug
+		vo.Location = "/category/" + tag.Slug
+	default:
+		return false
+	}
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Printf("Failed to resolve %s redirect %q: %v", redirectType, slug, err)
+		}
+		return false
+	}
+
+	recordRedirectHit(repo, redirect)
+	respondRedirect(c, vo)
+	return true
+}
+
+// respondRedirect 返回重定向指示
+// 状态码为 301/302，但不设置 Location 头，避免 HTTP 客户端自动跟随到 API 地址；
+// 前端页面请求失败时从响应中取出 data.location，加上语言前缀后按 permanent 发起 301 或 302 页面跳转
+func respondRedirect(c *gin.Context, vo *model.RedirectVO) {
+	response.ErrorWithData(c, vo.StatusCode(), constants.MsgMovedPermanently, vo)
+}
+
+// recordRedirectHit 记录命中，失败仅记录日志
+func recordRedirectHit(repo *repository.RedirectRepository, redirect *model.SlugRedirect) {
+	if err := repo.RecordHit(redirect.ID); err != nil {
+		log.Printf("Failed to record redirect hit %d: %v", redirect.ID, err)
+	}
+}
+
+// normalizeRedirectPath 规范化自定义重定向的来源路径：补齐开头的 /，去掉结尾的 /
+func normalizeRedirectPath(p string) string {
+	p = strings.TrimSpace(p)
+	if p == "" {
+		return ""
+	}
+	if !strings.HasPrefix(p, "/") {
+		p = "/" + p
+	}
+	if len(p) > 1 {
+		p = strings.TrimRight(p, "/")
+	}
+	return p
+}
+
+// validateCustomRedirect 校验自定义重定向
+func validateCustomRedirect(r *model.SlugRedirect) error {
+	if r.Source == "" || r.Source == "/" {
+		return errors.New("来源路径不能为空")
+	}
+	if !strings.HasPrefix(r.Target, "/") && !utils.IsValidURL(r.Target) {
+		return errors.New("目标地址必须是站内路径或 http(s) 链接")
+	}
+	if strings.HasPrefix(r.Target, "//") {
+		return errors.New("目标地址必须是站内路径或 http(s) 链接")
+	}
+	if normalizeRedirectPath(r.Target) == r.Source {
+		return errors.New("来源和目标不能相同")
+	}
+	return nil
+}
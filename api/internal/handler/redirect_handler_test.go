@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"kuaiyu/internal/database/dbtest"
+	"kuaiyu/internal/model"
+	"kuaiyu/internal/repository"
+	"kuaiyu/pkg/constants"
+)
+
+// resolveSlug 解析 /blog/ 下的旧 slug，未命中时返回 nil
+func resolveSlug(t *testing.T, repo *repository.RedirectRepository, slug string) *model.RedirectVO {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/posts/slug/"+slug, nil)
+
+	if !respondSlugRedirect(c, repo, model.RedirectTypePost, slug) {
+		return nil
+	}
+	if w.Code != http.StatusMovedPermanently {
+		t.Fatalf("%s: status %d, want 301", slug, w.Code)
+	}
+	var body struct {
+		Data model.RedirectVO `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	return &body.Data
+}
+
+func TestTranslationSlugRedirect(t *testing.T) {
+	db := dbtest.Open(t, &model.Post{}, &model.PostTranslation{}, &model.SlugRedirect{})
+	repo := repository.NewRedirectRepository()
+
+	post := model.Post{Title: "你好", Slug: "ni-hao", Status: string(constants.PostStatusPublished)}
+	if err := db.Create(&post).Error; err != nil {
+		t.Fatal(err)
+	}
+	translation := model.PostTranslation{PostID: post.ID, Locale: "en", Title: "Hello", Slug: "hello-world"}
+	if err := db.Create(&translation).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	// 翻译改名两次，旧地址都跳转到翻译的当前地址
+	for _, slug := range []string{"hello", "hello-there"} {
+		if err := repo.RecordSlugChange(model.RedirectTypeTranslation, translation.ID, slug, translation.Slug); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := repo.RecordSlugChange(model.RedirectTypePost, post.ID, "old-ni-hao", post.Slug); err != nil {
+		t.Fatal(err)
+	}
+
+	for slug, want := range map[string]string{
+		"hello":       "/blog/hello-world",
+		"hello-there": "/blog/hello-world",
+		"old-ni-hao":  "/blog/ni-hao",
+	} {
+		vo := resolveSlug(t, repo, slug)
+		if vo == nil || vo.Location != want {
+			t.Errorf("%s redirects to %+v, want %s", slug, vo, want)
+		}
+	}
+
+	// 删除翻译后旧地址改为跳转到原文，与文章重定向重复的旧地址保留文章的记录
+	if err := repo.RecordSlugChange(model.RedirectTypePost, post.ID, "hello-there", post.Slug); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.MoveTranslationRedirects(translation.ID, post.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Delete(&translation).Error; err != nil {
+		t.Fatal(err)
+	}
+	for _, slug := range []string{"hello", "hello-there"} {
+		if vo := resolveSlug(t, repo, slug); vo == nil || vo.Location != "/blog/ni-hao" {
+			t.Errorf("%s after translation delete redirects to %+v, want /blog/ni-hao", slug, vo)
+		}
+	}
+
+	// 原文未发布时不跳转
+	if err := db.Model(&post).Update("status", constants.PostStatusDraft).Error; err != nil {
+		t.Fatal(err)
+	}
+	if vo := resolveSlug(t, repo, "hello"); vo != nil {
+		t.Errorf("hello redirects to %+v for a draft post", vo)
+	}
+}
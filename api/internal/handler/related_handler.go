@@ -0,0 +1,254 @@
+// Package handler 相关文章处理器
+package handler
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"kuaiyu/internal/model"
+	"kuaiyu/internal/related"
+	"kuaiyu/internal/repository"
+	"kuaiyu/pkg/constants"
+	"kuaiyu/pkg/response"
+)
+
+// ===========================================
+// 相关文章处理器
+// ===========================================
+
+// RelatedHandler 相关文章处理器
+type RelatedHandler struct {
+	repo     *repository.RelatedRepository
+	postRepo *repository.PostRepository
+}
+
+// NewRelatedHandler 创建相关文章处理器
+func NewRelatedHandler() *RelatedHandler {
+	return &RelatedHandler{
+		repo:     repository.NewRelatedRepository(),
+		postRepo: repository.NewPostRepository(),
+	}
+}
+
+// ===========================================
+// 公开接口
+// ===========================================
+
+// List 获取文章的相关文章
+// Query: limit (可选) - 返回数量，默认 5，最大 20
+func (h *RelatedHandler) List(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(constants.RelatedDefaultLimit)))
+	if limit < 1 {
+		limit = constants.RelatedDefaultLimit
+	}
+	if limit > constants.RelatedMaxLimit {
+		limit = constants.RelatedMaxLimit
+	}
+
+	post, err := h.postRepo.FindBySlug(c.Param("slug"))
+	if err != nil || post.Status != string(constants.PostStatusPublished) {
+		response.NotFound(c, "文章不存在")
+		return
+	}
+
+	overrides, err := h.repo.FindOverrides(post.ID)
+	if err != nil {
+		response.InternalError(c, "")
+		return
+	}
+	scores, err := h.repo.FindScores(post.ID)
+	if err != nil {
+		response.InternalError(c, "")
+		return
+	}
+
+	// 置顶项在前，其余按得分排序，排除项不出现
+	pinned := make(map[uint]bool)
+	excluded := make(map[uint]bool)
+	var ids []uint
+	for _, o := range overrides {
+		switch o.Action {
+		case "pin":
+			pinned[o.RelatedID] = true
+			ids = append(ids, o.RelatedID)
+		case "exclude":
+			excluded[o.RelatedID] = true
+		}
+	}
+
+	scoreOf := make(map[uint]float64, len(scores))
+	for _, s := range scores {
+		scoreOf[s.RelatedID] = s.Score
+		if !pinned[s.RelatedID] && !excluded[s.RelatedID] {
+			ids = append(ids, s.RelatedID)
+		}
+	}
+
+	// 只返回公开文章
+	posts, err := h.postRepo.FindPublishedByIDs(ids)
+	if err != nil {
+		response.InternalError(c, "")
+		return
+	}
+
+	items := make([]model.RelatedPostVO, 0, limit)
+	for _, p := range posts {
+		if len(items) >= limit {
+			break
+		}
+		if p.ID == post.ID {
+			continue
+		}
+		items = append(items, model.RelatedPostVO{
+			PostListVO: p.ToListVO(),
+			Score:      scoreOf[p.ID],
+			Pinned:     pinned[p.ID],
+		})
+	}
+
+	response.Success(c, items)
+}
+
+// ===========================================
+// 管理接口
+// ===========================================
+
+// AdminList 管理后台查看文章的相关文章（含得分明细和干预项）
+func (h *RelatedHandler) AdminList(c *gin.Context) {
+	id, err := GetIDParam(c, "id")
+	if err != nil {
+		response.BadRequest(c, "无效的文章 ID")
+		return
+	}
+
+	overrides, err := h.repo.FindOverrides(id)
+	if err != nil {
+		response.InternalError(c, "")
+		return
+	}
+	scores, err := h.repo.FindScores(id)
+	if err != nil {
+		response.InternalError(c, "")
+		return
+	}
+
+	// 合并干预项和计算结果
+	items := make([]model.AdminRelatedPostVO, 0, len(overrides)+len(scores))
+	index := make(map[uint]int)
+	for _, o := range overrides {
+		index[o.RelatedID] = len(items)
+		items = append(items, model.AdminRelatedPostVO{
+			ID:       o.RelatedID,
+			Action:   o.Action,
+			Position: o.Position,
+		})
+	}
+	for _, s := range scores {
+		i, ok := index[s.RelatedID]
+		if !ok {
+			i = len(items)
+			index[s.RelatedID] = i
+			items = append(items, model.AdminRelatedPostVO{ID: s.RelatedID})
+		}
+		items[i].Score = s.Score
+		items[i].TagScore = s.TagScore
+		items[i].TextScore = s.TextScore
+		items[i].RecencyScore = s.RecencyScore
+	}
+
+	// 补充文章标题等信息
+	ids := make([]uint, len(items))
+	for i, item := range items {
+		ids[i] = item.ID
+	}
+	var posts []model.Post
+	if len(ids) > 0 {
+		h.postRepo.DB().Select("id, title, slug, status").Where("id IN ?", ids).Find(&posts)
+	}
+	for _, p := range posts {
+		item := &items[index[p.ID]]
+		item.Title = p.Title
+		item.Slug = p.Slug
+		item.Status = p.Status
+	}
+
+	response.Success(c, items)
+}
+
+// SetOverride 置顶或排除某篇相关文章
+func (h *RelatedHandler) SetOverride(c *gin.Context) {
+	id, err := GetIDParam(c, "id")
+	if err != nil {
+		response.BadRequest(c, "无效的文章 ID")
+		return
+	}
+
+	relatedID, err := GetIDParam(c, "rid")
+	if err != nil || relatedID == id {
+		response.BadRequest(c, "无效的相关文章 ID")
+		return
+	}
+
+	var req model.SetRelatedOverrideRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	if _, err := h.postRepo.FindByID(id); err != nil {
+		response.NotFound(c, "文章不存在")
+		return
+	}
+	if _, err := h.postRepo.FindByID(relatedID); err != nil {
+		response.NotFound(c, "相关文章不存在")
+		return
+	}
+
+	override := model.RelatedOverride{
+		PostID:    id,
+		RelatedID: relatedID,
+		Action:    req.Action,
+		Position:  req.Position,
+	}
+	if err := h.repo.SetOverride(&override); err != nil {
+		response.InternalError(c, "设置失败")
+		return
+	}
+
+	response.SuccessMessage(c, constants.MsgOperationSuccess, override)
+}
+
+// DeleteOverride 取消置顶或排除
+func (h *RelatedHandler) DeleteOverride(c *gin.Context) {
+	id, err := GetIDParam(c, "id")
+	if err != nil {
+		response.BadRequest(c, "无效的文章 ID")
+		return
+	}
+
+	relatedID, err := GetIDParam(c, "rid")
+	if err != nil {
+		response.BadRequest(c, "无效的相关文章 ID")
+		return
+	}
+
+	if err := h.repo.DeleteOverride(id, relatedID); err != nil {
+		response.InternalError(c, "删除失败")
+		return
+	}
+
+	response.SuccessMessage(c, constants.MsgDeleteSuccess, nil)
+}
+
+// Rebuild 立即重新计算全部相关文章
+func (h *RelatedHandler) Rebuild(c *gin.Context) {
+	total, err := related.Rebuild()
+	if err != nil {
+		response.InternalError(c, "重新计算失败")
+		return
+	}
+
+	response.SuccessMessage(c, constants.MsgOperationSuccess, gin.H{
+		"posts": total,
+	})
+}
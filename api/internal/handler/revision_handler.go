@@ -0,0 +1,287 @@
+// Package handler 修订历史处理器
+package handler
+
+import (
+	"log"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"kuaiyu/internal/database"
+	"kuaiyu/internal/middleware"
+	"kuaiyu/internal/model"
+	"kuaiyu/internal/related"
+	"kuaiyu/internal/render"
+	"kuaiyu/internal/repository"
+	"kuaiyu/internal/search"
+	"kuaiyu/pkg/constants"
+	"kuaiyu/pkg/diff"
+	"kuaiyu/pkg/response"
+)
+
+// ===========================================
+// 修订历史处理器
+// ===========================================
+
+// RevisionHandler 修订历史处理器
+type RevisionHandler struct {
+	repo     *repository.RevisionRepository
+	postRepo *repository.PostRepository
+}
+
+// NewRevisionHandler 创建修订历史处理器
+func NewRevisionHandler() *RevisionHandler {
+	return &RevisionHandler{
+		repo:     repository.NewRevisionRepository(),
+		postRepo: repository.NewPostRepository(),
+	}
+}
+
+// RevisionDiffVO 修订对比结果
+type RevisionDiffVO struct {
+	From    model.PostRevisionVO `json:"from"`
+	To      model.PostRevisionVO `json:"to"`
+	Title   diff.Result          `json:"title"`
+	Excerpt diff.Result          `json:"excerpt"`
+	Content diff.Result          `json:"content"`
+	Unified string               `json:"unified"` // 正文的统一格式差异文本
+}
+
+// ===========================================
+// 文章修订
+// ===========================================
+
+// ListPost 文章修订列表
+func (h *RevisionHandler) ListPost(c *gin.Context) {
+	h.list(c, "post")
+}
+
+// GetPost 文章修订详情
+func (h *RevisionHandler) GetPost(c *gin.Context) {
+	h.get(c, "post")
+}
+
+// DiffPost 对比文章的两个修订
+func (h *RevisionHandler) DiffPost(c *gin.Context) {
+	h.diff(c, "post")
+}
+
+// RestorePost 将文章恢复到指定修订
+func (h *RevisionHandler) RestorePost(c *gin.Context) {
+	id, err := GetIDParam(c, "id")
+	if err != nil {
+		response.BadRequest(c, "无效的文章 ID")
+		return
+	}
+
+	revisionID, err := GetIDParam(c, "rid")
+	if err != nil {
+		response.BadRequest(c, "无效的修订 ID")
+		return
+	}
+
+	post, err := h.postRepo.FindByID(id)
+	if err != nil {
+		response.NotFound(c, "文章不存在")
+		return
+	}
+
+	revision, err := h.repo.FindByID("post", id, revisionID)
+	if err != nil {
+		response.NotFound(c, "修订不存在")
+		return
+	}
+
+	// 恢复正文字段，slug 和状态保持当前值
+	post.Title = revision.Title
+	post.Excerpt = revision.Excerpt
+	post.Content = revision.Content
+	post.CoverImage = revision.CoverImage
+
+	if err := h.postRepo.Update(post); err != nil {
+		response.InternalError(c, "恢复失败")
+		return
+	}
+
+	snapshot := model.NewPostRevision(post, string(constants.RevisionReasonRestore), middleware.GetUserID(c))
+	if _, err := h.repo.Save(&snapshot); err != nil {
+		log.Printf("Failed to save post revision: %v", err)
+	}
+
+	post, _ = h.postRepo.FindByID(id)
+	search.SyncPost(post)
+	related.Refresh(id)
+
+	response.SuccessMessage(c, "恢复成功", post.ToVO())
+}
+
+// ===========================================
+// 生活记录修订
+// ===========================================
+
+// ListLife 生活记录修订列表
+func (h *RevisionHandler) ListLife(c *gin.Context) {
+	h.list(c, "life")
+}
+
+// GetLife 生活记录修订详情
+func (h *RevisionHandler) GetLife(c *gin.Context) {
+	h.get(c, "life")
+}
+
+// DiffLife 对比生活记录的两个修订
+func (h *RevisionHandler) DiffLife(c *gin.Context) {
+	h.diff(c, "life")
+}
+
+// RestoreLife 将生活记录恢复到指定修订
+func (h *RevisionHandler) RestoreLife(c *gin.Context) {
+	id, err := GetIDParam(c, "id")
+	if err != nil {
+		response.BadRequest(c, "无效的 ID")
+		return
+	}
+
+	revisionID, err := GetIDParam(c, "rid")
+	if err != nil {
+		response.BadRequest(c, "无效的修订 ID")
+		return
+	}
+
+	var record model.LifeRecord
+	db := database.Get()
+	if err := db.First(&record, id).Error; err != nil {
+		response.NotFound(c, "记录不存在")
+		return
+	}
+
+	revision, err := h.repo.FindByID("life", id, revisionID)
+	if err != nil {
+		response.NotFound(c, "修订不存在")
+		return
+	}
+
+	record.Title = revision.Title
+	record.Content = revision.Content
+	record.CoverImage = revision.CoverImage
+
+	if err := db.Save(&record).Error; err != nil {
+		response.InternalError(c, "恢复失败")
+		return
+	}
+
+	snapshot := model.NewLifeRevision(&record, string(constants.RevisionReasonRestore), middleware.GetUserID(c))
+	if _, err := h.repo.Save(&snapshot); err != nil {
+		log.Printf("Failed to save life revision: %v", err)
+	}
+	search.SyncLife(&record)
+
+	response.SuccessMessage(c, "恢复成功", record.ToVO())
+}
+
+// ===========================================
+// 通用实现
+// ===========================================
+
+// list 修订列表
+func (h *RevisionHandler) list(c *gin.Context, targetType string) {
+	id, err := GetIDParam(c, "id")
+	if err != nil {
+		response.BadRequest(c, "无效的 ID")
+		return
+	}
+
+	page, limit := GetPageParams(c)
+
+	revisions, total, err := h.repo.FindByTarget(targetType, id, page, limit)
+	if err != nil {
+		response.InternalError(c, "")
+		return
+	}
+
+	items := make([]model.PostRevisionVO, len(revisions))
+	for i, revision := range revisions {
+		items[i] = revision.ToListVO()
+	}
+
+	response.PagedSuccess(c, items, page, limit, total)
+}
+
+// get 修订详情
+func (h *RevisionHandler) get(c *gin.Context, targetType string) {
+	id, err := GetIDParam(c, "id")
+	if err != nil {
+		response.BadRequest(c, "无效的 ID")
+		return
+	}
+
+	revisionID, err := GetIDParam(c, "rid")
+	if err != nil {
+		response.BadRequest(c, "无效的修订 ID")
+		return
+	}
+
+	revision, err := h.repo.FindByID(targetType, id, revisionID)
+	if err != nil {
+		response.NotFound(c, "修订不存在")
+		return
+	}
+
+	vo := revision.ToVO()
+	render.Revision(&vo)
+
+	response.Success(c, vo)
+}
+
+// diff 对比两个修订
+// Query: from - 旧修订 ID（必填）
+// Query: to - 新修订 ID（可选，默认使用最新修订）
+func (h *RevisionHandler) diff(c *gin.Context, targetType string) {
+	id, err := GetIDParam(c, "id")
+	if err != nil {
+		response.BadRequest(c, "无效的 ID")
+		return
+	}
+
+	fromID, err := strconv.ParseUint(c.Query("from"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "无效的修订 ID")
+		return
+	}
+
+	from, err := h.repo.FindByID(targetType, id, uint(fromID))
+	if err != nil {
+		response.NotFound(c, "修订不存在")
+		return
+	}
+
+	var to *model.PostRevision
+	if toStr := c.Query("to"); toStr != "" {
+		toID, err := strconv.ParseUint(toStr, 10, 32)
+		if err != nil {
+			response.BadRequest(c, "无效的修订 ID")
+			return
+		}
+		to, err = h.repo.FindByID(targetType, id, uint(toID))
+		if err != nil {
+			response.NotFound(c, "修订不存在")
+			return
+		}
+	} else {
+		to, err = h.repo.FindLatest(targetType, id)
+		if err != nil {
+			response.NotFound(c, "修订不存在")
+			return
+		}
+	}
+
+	contentDiff := diff.Lines(from.Content, to.Content)
+
+	response.Success(c, RevisionDiffVO{
+		From:    from.ToListVO(),
+		To:      to.ToListVO(),
+		Title:   diff.Lines(from.Title, to.Title),
+		Excerpt: diff.Lines(from.Excerpt, to.Excerpt),
+		Content: contentDiff,
+		Unified: contentDiff.Unified(constants.RevisionDiffContext),
+	})
+}
@@ -1,164 +1,358 @@
-// Package handler RSS 处理器
+// Package handler 订阅源处理器
 package handler
 
 import (
-	"fmt"
-	"time"
+	"log"
+	"sort"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"kuaiyu/internal/config"
 	"kuaiyu/internal/database"
+	"kuaiyu/internal/feed"
 	"kuaiyu/internal/model"
+	"kuaiyu/internal/render"
+	"kuaiyu/internal/repository"
 	"kuaiyu/pkg/constants"
+	"kuaiyu/pkg/response"
+	"kuaiyu/pkg/utils"
 )
 
 // ===========================================
-// RSS 处理器
+// 订阅源处理器
 // ===========================================
 
-// RSSHandler RSS 处理器
-type RSSHandler struct{}
+// RSSHandler 订阅源处理器
+// 同一份数据输出 RSS 2.0、Atom 1.0 和 JSON Feed 1.1，通用参数：
+//
+//	format=rss|atom|json  输出格式（/atom 和 /feed.json 默认对应格式）
+//	full=1                输出完整正文 HTML
+//	limit=N               条目数，默认 20，最多 100
+//
+// 所有订阅源都支持条件请求（ETag / Last-Modified），内容未变化时返回 304
+type RSSHandler struct {
+	seriesRepo *repository.SeriesRepository
+	tagRepo    *repository.TagRepository
+}
 
-// NewRSSHandler 创建 RSS 处理器
+// NewRSSHandler 创建订阅源处理器
 func NewRSSHandler() *RSSHandler {
-	return &RSSHandler{}
+	return &RSSHandler{
+		seriesRepo: repository.NewSeriesRepository(),
+		tagRepo:    repository.NewTagRepository(),
+	}
 }
 
 // ===========================================
-// RSS Feed
+// 博客订阅源
 // ===========================================
 
-// Feed 生成 RSS Feed
+// Feed 博客订阅源（默认 RSS 2.0）
 func (h *RSSHandler) Feed(c *gin.Context) {
-	db := database.Get()
-	
-	// 获取最近文章
-	var posts []model.Post
-	db.Where("status = ?", constants.PostStatusPublished).
-		Order("published_at DESC").
-		Limit(20).
-		Find(&posts)
-	
-	// 使用写死的站点名称
-	siteName := "Yu.kuai"
-	
-	xml := h.generateRSS(siteName, "https://kcat.site", posts)
-	
-	c.Header("Content-Type", "application/rss+xml; charset=utf-8")
-	c.String(200, xml)
-}
-
-// PostsFeed 博客 RSS Feed
+	h.postsFeed(c, feed.FormatRSS)
+}
+
+// PostsFeed 博客订阅源
 func (h *RSSHandler) PostsFeed(c *gin.Context) {
 	h.Feed(c)
 }
 
-// LifeFeed 生活记录 RSS Feed
+// AtomFeed 博客订阅源（默认 Atom 1.0）
+func (h *RSSHandler) AtomFeed(c *gin.Context) {
+	h.postsFeed(c, feed.FormatAtom)
+}
+
+// JSONFeed 博客订阅源（默认 JSON Feed 1.1）
+func (h *RSSHandler) JSONFeed(c *gin.Context) {
+	h.postsFeed(c, feed.FormatJSON)
+}
+
+// postsFeed 最近发布的文章
+func (h *RSSHandler) postsFeed(c *gin.Context, defaultFormat feed.Format) {
+	posts, err := recentFeedPosts(feedLimit(c))
+	if err != nil {
+		response.InternalError(c, "")
+		return
+	}
+
+	site := config.Get().Site
+	f := newFeed(c, site.Name, site.Description)
+	f.Items = h.postItems(posts, feedFull(c))
+
+	writeFeed(c, f, defaultFormat)
+}
+
+// TagFeed 标签订阅源
+func (h *RSSHandler) TagFeed(c *gin.Context) {
+	tag, err := h.tagRepo.FindBySlug(c.Param("slug"))
+	if err != nil {
+		response.NotFound(c, "标签不存在")
+		return
+	}
+
+	var posts []model.Post
+	if err := database.Get().
+		Joins("JOIN post_tags ON post_tags.post_id = posts.id").
+		Where("post_tags.tag_id = ?", tag.ID).
+		Scopes(repository.PublishedScope("posts"), repository.ListedScope("posts")).
+		Preload("Tags").
+		Order("posts.published_at DESC").
+		Limit(feedLimit(c)).
+		Find(&posts).Error; err != nil {
+		response.InternalError(c, "")
+		return
+	}
+
+	site := config.Get().Site
+	description := tag.Description
+	if description == "" {
+		description = site.Description
+	}
+	f := newFeed(c, site.Name+" - "+tag.Name, description)
+	f.SiteURL = site.URL + "/category/" + tag.Slug
+	f.Items = h.postItems(posts, feedFull(c))
+
+	writeFeed(c, f, feed.FormatRSS)
+}
+
+// SeriesFeed 系列订阅源，条目按系列顺序排列
+func (h *RSSHandler) SeriesFeed(c *gin.Context) {
+	series, err := h.seriesRepo.FindBySlug(c.Param("slug"))
+	if err != nil {
+		response.NotFound(c, "系列不存在")
+		return
+	}
+
+	posts, err := h.seriesRepo.FindPosts(series.ID, true)
+	if err != nil {
+		response.InternalError(c, "")
+		return
+	}
+
+	site := config.Get().Site
+	description := series.Description
+	if description == "" {
+		description = site.Description
+	}
+	f := newFeed(c, site.Name+" - "+series.Name, description)
+	f.Items = h.postItems(posts, feedFull(c))
+
+	writeFeed(c, f, feed.FormatRSS)
+}
+
+// ===========================================
+// 生活记录订阅源
+// ===========================================
+
+// LifeFeed 生活记录订阅源
 func (h *RSSHandler) LifeFeed(c *gin.Context) {
-	db := database.Get()
-	
+	records, err := recentFeedLifeRecords(feedLimit(c))
+	if err != nil {
+		response.InternalError(c, "")
+		return
+	}
+
+	site := config.Get().Site
+	f := newFeed(c, site.Name+"生活", "生活记录")
+	f.SiteURL = site.URL + "/life"
+	f.Items = lifeItems(records, feedFull(c))
+
+	writeFeed(c, f, feed.FormatRSS)
+}
+
+// ===========================================
+// 全站订阅源
+// ===========================================
+
+// AllFeed 全站订阅源，文章和生活记录按发布时间交错排列
+func (h *RSSHandler) AllFeed(c *gin.Context) {
+	limit := feedLimit(c)
+	full := feedFull(c)
+
+	posts, err := recentFeedPosts(limit)
+	if err != nil {
+		response.InternalError(c, "")
+		return
+	}
+	records, err := recentFeedLifeRecords(limit)
+	if err != nil {
+		response.InternalError(c, "")
+		return
+	}
+
+	items := append(h.postItems(posts, full), lifeItems(records, full)...)
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Published.After(items[j].Published)
+	})
+	if len(items) > limit {
+		items = items[:limit]
+	}
+
+	site := config.Get().Site
+	f := newFeed(c, site.Name, site.Description)
+	f.Items = items
+
+	writeFeed(c, f, feed.FormatRSS)
+}
+
+// ===========================================
+// 辅助函数
+// ===========================================
+
+// recentFeedPosts 最近发布的公开文章
+func recentFeedPosts(limit int) ([]model.Post, error) {
+	var posts []model.Post
+	err := database.Get().
+		Scopes(repository.PublishedScope("posts"), repository.ListedScope("posts")).
+		Preload("Tags").
+		Order("published_at DESC").
+		Limit(limit).
+		Find(&posts).Error
+	return posts, err
+}
+
+// recentFeedLifeRecords 最近发布的生活记录
+func recentFeedLifeRecords(limit int) ([]model.LifeRecord, error) {
 	var records []model.LifeRecord
-	db.Where("status = ?", constants.PostStatusPublished).
+	err := database.Get().
+		Scopes(repository.PublishedScope("life_records")).
 		Order("published_at DESC").
-		Limit(20).
-		Find(&records)
-	
-	// 转换为 RSS 格式
-	xml := h.generateLifeRSS("Yu.kuai生活", "https://kcat.site/life", records)
-	
-	c.Header("Content-Type", "application/rss+xml; charset=utf-8")
-	c.String(200, xml)
-}
-
-// generateRSS 生成 RSS XML
-func (h *RSSHandler) generateRSS(title, link string, posts []model.Post) string {
-	now := time.Now().Format(time.RFC1123Z)
-	
-	items := ""
+		Limit(limit).
+		Find(&records).Error
+	return records, err
+}
+
+// postItems 将文章转换为订阅源条目，标签和所属系列作为分类
+// 加密文章只输出摘要，不输出正文
+func (h *RSSHandler) postItems(posts []model.Post, full bool) []feed.Item {
+	site := config.Get().Site
+
+	postIDs := make([]uint, len(posts))
+	for i, post := range posts {
+		postIDs[i] = post.ID
+	}
+	seriesByPost, err := h.seriesRepo.FindSeriesByPosts(postIDs)
+	if err != nil {
+		log.Printf("Failed to load series for feed: %v", err)
+	}
+
+	items := make([]feed.Item, 0, len(posts))
 	for _, post := range posts {
-		pubDate := ""
+		link := site.URL + "/blog/" + post.Slug
+
+		item := feed.Item{
+			ID:        link,
+			Title:     post.Title,
+			Link:      link,
+			Summary:   post.Excerpt,
+			Published: post.CreatedAt,
+			Updated:   post.UpdatedAt,
+			Enclosure: feed.NewImageEnclosure(utils.AbsoluteURL(site.URL, post.CoverImage)),
+		}
 		if post.PublishedAt != nil {
-			pubDate = post.PublishedAt.Format(time.RFC1123Z)
+			item.Published = *post.PublishedAt
+		}
+		if full && !post.IsProtected() {
+			item.ContentHTML = render.Markdown(post.Content).HTML
+		}
+
+		for _, tag := range post.Tags {
+			item.Categories = append(item.Categories, feed.Category{
+				Term:   tag.Slug,
+				Label:  tag.Name,
+				Domain: site.URL + "/category/",
+			})
+		}
+		if series, ok := seriesByPost[post.ID]; ok {
+			item.Categories = append(item.Categories, feed.Category{
+				Term:   series.Slug,
+				Label:  series.Name,
+				Domain: site.URL + "/series/",
+			})
 		}
-		
-		items += fmt.Sprintf(`
-    <item>
-      <title><![CDATA[%s]]></title>
-      <link>%s/blog/%s</link>
-      <description><![CDATA[%s]]></description>
-      <pubDate>%s</pubDate>
-      <guid>%s/blog/%s</guid>
-    </item>`,
-			post.Title,
-			link, post.Slug,
-			post.Excerpt,
-			pubDate,
-			link, post.Slug,
-		)
-	}
-	
-	return fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
-<rss version="2.0" xmlns:atom="http://www.w3.org/2005/Atom">
-  <channel>
-    <title><![CDATA[%s]]></title>
-    <link>%s</link>
-    <description><![CDATA[%s 的博客]]></description>
-    <language>zh-CN</language>
-    <lastBuildDate>%s</lastBuildDate>
-    <atom:link href="%s/api/rss" rel="self" type="application/rss+xml"/>%s
-  </channel>
-</rss>`, title, link, title, now, link, items)
-}
-
-// generateLifeRSS 生成生活记录 RSS
-func (h *RSSHandler) generateLifeRSS(title, link string, records []model.LifeRecord) string {
-	now := time.Now().Format(time.RFC1123Z)
-	
-	items := ""
+
+		items = append(items, item)
+	}
+	return items
+}
+
+// lifeItems 将生活记录转换为订阅源条目，没有标题时取正文开头
+func lifeItems(records []model.LifeRecord, full bool) []feed.Item {
+	site := config.Get().Site
+
+	items := make([]feed.Item, 0, len(records))
 	for _, record := range records {
-		pubDate := ""
-		if record.PublishedAt != nil {
-			pubDate = record.PublishedAt.Format(time.RFC1123Z)
+		link := site.URL + "/life/" + strconv.FormatUint(uint64(record.ID), 10)
+
+		title := record.Title
+		if title == "" {
+			title = utils.GenerateExcerpt(record.Content, 50)
 		}
-		
-		// 截取内容预览
-		content := record.Content
-		// 使用内容前50字作为标题
-		titleRunes := []rune(content)
-		title := content
-		if len(titleRunes) > 50 {
-			title = string(titleRunes[:50]) + "..."
+
+		item := feed.Item{
+			ID:        link,
+			Title:     title,
+			Link:      link,
+			Summary:   utils.GenerateExcerpt(record.Content, constants.ExcerptMaxLength),
+			Published: record.CreatedAt,
+			Updated:   record.UpdatedAt,
+			Enclosure: feed.NewImageEnclosure(utils.AbsoluteURL(site.URL, record.CoverImage)),
+		}
+		if record.PublishedAt != nil {
+			item.Published = *record.PublishedAt
 		}
-		
-		if len([]rune(content)) > 200 {
-			content = string([]rune(content)[:200]) + "..."
+		if full {
+			item.ContentHTML = render.Markdown(record.Content).HTML
 		}
-		
-		items += fmt.Sprintf(`
-    <item>
-      <title><![CDATA[%s]]></title>
-      <link>%s/%d</link>
-      <description><![CDATA[%s]]></description>
-      <pubDate>%s</pubDate>
-      <guid>%s/%d</guid>
-    </item>`,
-			title,
-			link, record.ID,
-			content,
-			pubDate,
-			link, record.ID,
-		)
-	}
-	
-	return fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
-<rss version="2.0" xmlns:atom="http://www.w3.org/2005/Atom">
-  <channel>
-    <title><![CDATA[%s]]></title>
-    <link>%s</link>
-    <description><![CDATA[生活记录]]></description>
-    <language>zh-CN</language>
-    <lastBuildDate>%s</lastBuildDate>
-    <atom:link href="%s/api/rss/life" rel="self" type="application/rss+xml"/>%s
-  </channel>
-</rss>`, title, link, now, "https://kcat.site", items)
+		items = append(items, item)
+	}
+	return items
+}
+
+// newFeed 创建订阅源，自身地址取当前请求地址
+func newFeed(c *gin.Context, title, description string) *feed.Feed {
+	site := config.Get().Site
+	return &feed.Feed{
+		Title:       title,
+		Description: description,
+		Language:    site.Language,
+		SiteURL:     site.URL,
+		FeedURL:     site.URL + c.Request.URL.RequestURI(),
+		Author:      site.Name,
+	}
+}
+
+// writeFeed 按 ?format= 或默认格式输出订阅源，支持条件请求
+func writeFeed(c *gin.Context, f *feed.Feed, defaultFormat feed.Format) {
+	format, ok := feed.ParseFormat(c.Query("format"))
+	if !ok {
+		format = defaultFormat
+	}
+
+	data, err := f.Render(format)
+	if err != nil {
+		log.Printf("Failed to render %s feed: %v", format, err)
+		response.InternalError(c, "")
+		return
+	}
+
+	writeConditional(c, format.ContentType(), data, f.LastModified())
+}
+
+// feedLimit 条目数参数
+func feedLimit(c *gin.Context) int {
+	limit, err := strconv.Atoi(c.Query("limit"))
+	if err != nil || limit < 1 {
+		return constants.FeedDefaultLimit
+	}
+	if limit > constants.FeedMaxLimit {
+		return constants.FeedMaxLimit
+	}
+	return limit
 }
 
+// feedFull 是否输出完整正文
+func feedFull(c *gin.Context) bool {
+	full, _ := strconv.ParseBool(c.Query("full"))
+	return full
+}
@@ -0,0 +1,79 @@
+// Package handler 全文检索处理器
+package handler
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"kuaiyu/internal/search"
+	"kuaiyu/pkg/constants"
+	"kuaiyu/pkg/response"
+)
+
+// ===========================================
+// 全文检索处理器
+// ===========================================
+
+// SearchHandler 全文检索处理器
+type SearchHandler struct{}
+
+// NewSearchHandler 创建全文检索处理器
+func NewSearchHandler() *SearchHandler {
+	return &SearchHandler{}
+}
+
+// ===========================================
+// 公开接口
+// ===========================================
+
+// Search 站内检索
+// Query: q - 关键词（必填）
+// Query: type (可选) - "post", "life", "comment", "all" (默认 "all")
+func (h *SearchHandler) Search(c *gin.Context) {
+	q := strings.TrimSpace(c.Query("q"))
+	if q == "" {
+		response.BadRequest(c, "请输入搜索关键词")
+		return
+	}
+	if len([]rune(q)) > 100 {
+		response.BadRequest(c, "搜索关键词过长")
+		return
+	}
+
+	docType := c.DefaultQuery("type", "all")
+	switch docType {
+	case "all":
+		docType = ""
+	case search.DocTypePost, search.DocTypeLife, search.DocTypeComment:
+	default:
+		response.BadRequest(c, "无效的搜索类型")
+		return
+	}
+
+	page, limit := GetPageParams(c)
+
+	items, total, err := search.Search(q, docType, page, limit)
+	if err != nil {
+		response.InternalError(c, "")
+		return
+	}
+
+	response.PagedSuccess(c, items, page, limit, total)
+}
+
+// ===========================================
+// 管理接口
+// ===========================================
+
+// Rebuild 重建全部索引
+func (h *SearchHandler) Rebuild(c *gin.Context) {
+	total, err := search.Rebuild()
+	if err != nil {
+		response.InternalError(c, "重建索引失败")
+		return
+	}
+
+	response.SuccessMessage(c, constants.MsgOperationSuccess, gin.H{
+		"documents": total,
+	})
+}
@@ -2,13 +2,23 @@
 package handler
 
 import (
-	"fmt"
+	"errors"
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"kuaiyu/internal/config"
 	"kuaiyu/internal/database"
 	"kuaiyu/internal/model"
+	"kuaiyu/internal/repository"
+	"kuaiyu/internal/sitemap"
 	"kuaiyu/pkg/constants"
+	"kuaiyu/pkg/response"
+	"kuaiyu/pkg/utils"
 )
 
 // ===========================================
@@ -16,107 +26,521 @@ import (
 // ===========================================
 
 // SEOHandler SEO 处理器
-type SEOHandler struct{}
+type SEOHandler struct {
+	postRepo        *repository.PostRepository
+	translationRepo *repository.TranslationRepository
+	tagRepo         *repository.TagRepository
+	seriesRepo      *repository.SeriesRepository
+	redirectRepo    *repository.RedirectRepository
+}
 
 // NewSEOHandler 创建 SEO 处理器
 func NewSEOHandler() *SEOHandler {
-	return &SEOHandler{}
+	return &SEOHandler{
+		postRepo:        repository.NewPostRepository(),
+		translationRepo: repository.NewTranslationRepository(),
+		tagRepo:         repository.NewTagRepository(),
+		seriesRepo:      repository.NewSeriesRepository(),
+		redirectRepo:    repository.NewRedirectRepository(),
+	}
 }
 
 // ===========================================
 // Sitemap
 // ===========================================
 
-// Sitemap 生成 Sitemap
+// Sitemap sitemap 索引
+// 子 sitemap 按类型拆分并分页，见 SitemapSection
 func (h *SEOHandler) Sitemap(c *gin.Context) {
-	baseURL := "https://kcat.site"
-	db := database.Get()
-	
-	// 静态页面
-	urls := []string{
-		fmt.Sprintf(`  <url><loc>%s</loc><changefreq>daily</changefreq><priority>1.0</priority></url>`, baseURL),
-		fmt.Sprintf(`  <url><loc>%s/blog</loc><changefreq>daily</changefreq><priority>0.9</priority></url>`, baseURL),
-		fmt.Sprintf(`  <url><loc>%s/life</loc><changefreq>weekly</changefreq><priority>0.8</priority></url>`, baseURL),
-		fmt.Sprintf(`  <url><loc>%s/archive</loc><changefreq>weekly</changefreq><priority>0.7</priority></url>`, baseURL),
-		fmt.Sprintf(`  <url><loc>%s/category</loc><changefreq>weekly</changefreq><priority>0.7</priority></url>`, baseURL),
-		fmt.Sprintf(`  <url><loc>%s/guestbook</loc><changefreq>weekly</changefreq><priority>0.6</priority></url>`, baseURL),
-	}
-	
-	// 博客文章
-	var posts []model.Post
-	db.Where("status = ?", constants.PostStatusPublished).Find(&posts)
-	
-	for _, post := range posts {
-		lastmod := post.UpdatedAt.Format("2006-01-02")
-		urls = append(urls, fmt.Sprintf(
-			`  <url><loc>%s/blog/%s</loc><lastmod>%s</lastmod><changefreq>monthly</changefreq><priority>0.8</priority></url>`,
-			baseURL, post.Slug, lastmod,
-		))
-	}
-	
-	// 生活记录
-	var records []model.LifeRecord
-	db.Where("status = ?", constants.PostStatusPublished).Find(&records)
-	
-	for _, record := range records {
-		lastmod := record.UpdatedAt.Format("2006-01-02")
-		urls = append(urls, fmt.Sprintf(
-			`  <url><loc>%s/life/%d</loc><lastmod>%s</lastmod><changefreq>monthly</changefreq><priority>0.6</priority></url>`,
-			baseURL, record.ID, lastmod,
-		))
-	}
-	
-	// 标签页面
-	var tags []model.Tag
-	db.Find(&tags)
-	
-	for _, tag := range tags {
-		urls = append(urls, fmt.Sprintf(
-			`  <url><loc>%s/category/%s</loc><changefreq>weekly</changefreq><priority>0.5</priority></url>`,
-			baseURL, tag.Slug,
-		))
-	}
-	
-	// 生成 XML
-	xml := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
-<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
-%s
-</urlset>`, joinStrings(urls, "\n"))
-	
-	c.Header("Content-Type", "application/xml; charset=utf-8")
-	c.String(200, xml)
-}
-
-// Robots 生成 robots.txt
+	doc, err := sitemap.Index()
+	writeSitemap(c, doc, err)
+}
+
+// SitemapSection 子 sitemap，如 /api/sitemap/posts-1.xml
+func (h *SEOHandler) SitemapSection(c *gin.Context) {
+	name, page, ok := sitemap.ParseFileName(c.Param("file"))
+	if !ok {
+		response.NotFound(c, "")
+		return
+	}
+
+	doc, err := sitemap.Section(name, page)
+	writeSitemap(c, doc, err)
+}
+
+// Robots 生成 robots.txt，禁止爬取的路径来自配置
 func (h *SEOHandler) Robots(c *gin.Context) {
-	robots := `User-agent: *
-Allow: /
+	site := config.Get().Site
 
-# 禁止爬取后台
-Disallow: /admin/
-Disallow: /api/admin/
+	var b strings.Builder
+	b.WriteString("User-agent: *\nAllow: /\n")
+	for _, path := range site.RobotsDisallow {
+		b.WriteString("Disallow: " + path + "\n")
+	}
+	b.WriteString("\n# Sitemap\nSitemap: " + site.URL + "/api/sitemap.xml\n")
 
-# Sitemap
-Sitemap: https://kcat.site/api/sitemap.xml
-`
-	
 	c.Header("Content-Type", "text/plain; charset=utf-8")
-	c.String(200, robots)
+	c.String(200, b.String())
+}
+
+// IndexNowKey IndexNow 密钥文件，搜索引擎据此校验推送方对站点的所有权
+func (h *SEOHandler) IndexNowKey(c *gin.Context) {
+	key := config.Get().Ping.IndexNowKey
+	if key == "" {
+		response.NotFound(c, "")
+		return
+	}
+
+	c.Header("Content-Type", "text/plain; charset=utf-8")
+	c.String(200, key)
 }
 
 // ===========================================
-// 辅助函数
+// 页面元数据
 // ===========================================
 
-func joinStrings(strs []string, sep string) string {
-	result := ""
-	for i, s := range strs {
-		if i > 0 {
-			result += sep
+// Meta 页面 SEO 元数据
+// GET /api/seo/meta?path=/blog/hello，解析前端公开路由（首页、列表页、文章、生活记录、标签、系列），
+// 返回标题、描述、规范地址、OpenGraph / Twitter Card 字段和 JSON-LD 文档；
+// 旧地址按重定向处理，与文章、标签详情接口一致
+func (h *SEOHandler) Meta(c *gin.Context) {
+	raw := c.Query("path")
+	if i := strings.IndexAny(raw, "?#"); i >= 0 {
+		raw = raw[:i]
+	}
+	if unescaped, err := url.PathUnescape(raw); err == nil {
+		raw = unescaped
+	}
+	path := normalizeRedirectPath(raw)
+	if path == "" {
+		response.BadRequest(c, "缺少 path 参数")
+		return
+	}
+
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+
+	var meta *model.SEOMetaVO
+	var err error
+	var redirectType string
+	switch {
+	case path == "/":
+		meta = homeMeta()
+	case len(segments) == 1:
+		meta = listMeta(segments[0])
+	case len(segments) == 2:
+		switch segments[0] {
+		case "blog":
+			meta, err = h.postMeta(segments[1])
+			redirectType = model.RedirectTypePost
+		case "category":
+			meta, err = h.tagMeta(segments[1])
+			redirectType = model.RedirectTypeTag
+		case "life":
+			meta, err = lifeMeta(segments[1])
+		case "series":
+			meta, err = h.seriesMeta(segments[1])
+		}
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Printf("Failed to resolve SEO meta for %q: %v", path, err)
+		response.InternalError(c, "")
+		return
+	}
+
+	if meta == nil {
+		// 旧 slug 和自定义重定向
+		if redirectType != "" && respondSlugRedirect(c, h.redirectRepo, redirectType, segments[1]) {
+			return
+		}
+		if redirect, err := h.redirectRepo.FindBySource(model.RedirectTypeCustom, path); err == nil {
+			recordRedirectHit(h.redirectRepo, redirect)
+			respondRedirect(c, &model.RedirectVO{
+				Type:      model.RedirectTypeCustom,
+				Location:  redirect.Target,
+				Permanent: redirect.Permanent,
+			})
+			return
+		}
+		response.NotFound(c, "页面不存在")
+		return
+	}
+
+	response.Success(c, meta)
+}
+
+// homeMeta 首页
+func homeMeta() *model.SEOMetaVO {
+	site := config.Get().Site
+	meta := newSEOMeta(model.SEOPageHome, "", site.Description, "/", "")
+
+	meta.JSONLD = schemaGraph(model.JSONLD{
+		"@type":       "WebSite",
+		"@id":         meta.Canonical + "#website",
+		"name":        site.Name,
+		"url":         meta.Canonical,
+		"description": meta.Description,
+		"inLanguage":  meta.Locale,
+		"publisher":   schemaPublisher(),
+	})
+	return meta
+}
+
+// listMeta 列表页，未知路由返回 nil
+func listMeta(segment string) *model.SEOMetaVO {
+	pages := map[string]struct {
+		pageType string
+		name     string
+	}{
+		"blog":      {model.SEOPageList, "博客"},
+		"life":      {model.SEOPageList, "生活"},
+		"category":  {model.SEOPageList, "分类"},
+		"archive":   {model.SEOPageArchive, "归档"},
+		"guestbook": {model.SEOPageList, "留言板"},
+	}
+	page, ok := pages[segment]
+	if !ok {
+		return nil
+	}
+
+	path := "/" + segment
+	meta := newSEOMeta(page.pageType, page.name, "", path, "")
+	meta.JSONLD = schemaGraph(
+		schemaCollectionPage(meta),
+		schemaBreadcrumb(breadcrumb{"首页", "/"}, breadcrumb{page.name, path}),
+	)
+	return meta
+}
+
+// postMeta 文章，支持翻译的 slug
+// 未发布的文章返回 gorm.ErrRecordNotFound；加密文章只使用摘要
+func (h *SEOHandler) postMeta(slug string) (*model.SEOMetaVO, error) {
+	var translation *model.PostTranslation
+
+	post, err := h.postRepo.FindBySlug(slug)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		translation, err = h.translationRepo.FindBySlug(slug)
+		if err != nil {
+			return nil, err
 		}
-		result += s
+		post, err = h.postRepo.FindByID(translation.PostID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !isPublishedNow(post.Status, post.PublishedAt) {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	title, excerpt, content, locale := post.Title, post.Excerpt, post.Content, constants.DefaultLocale
+	if translation != nil {
+		title, excerpt, content, locale = translation.Title, translation.Excerpt, translation.Content, translation.Locale
+	}
+	description := excerpt
+	if description == "" && !post.IsProtected() {
+		description = content
+	}
+
+	path := "/blog/" + slug
+	meta := newSEOMeta(model.SEOPagePost, title, description, path, postShareImage(post))
+	meta.Locale = locale
+	meta.OpenGraph.Locale = ogLocale(locale)
+	if post.GetVisibility() == string(constants.PostVisibilityUnlisted) {
+		meta.Robots = "noindex"
+	}
+
+	// 语言版本
+	versions, err := h.translationRepo.FindSummaries([]uint{post.ID})
+	if err != nil {
+		return nil, err
+	}
+	if translations := versions[post.ID]; len(translations) > 0 {
+		site := config.Get().Site
+		meta.Alternates = append(meta.Alternates, model.SEOAlternateVO{Hreflang: constants.DefaultLocale, Href: site.URL + "/blog/" + post.Slug})
+		for _, t := range translations {
+			meta.Alternates = append(meta.Alternates, model.SEOAlternateVO{Hreflang: t.Locale, Href: site.URL + "/blog/" + t.Slug})
+		}
+		meta.Alternates = append(meta.Alternates, model.SEOAlternateVO{Hreflang: "x-default", Href: site.URL + "/blog/" + post.Slug})
+	}
+
+	for _, tag := range post.Tags {
+		meta.Keywords = append(meta.Keywords, tag.Name)
+	}
+	setArticle(meta, post.PublishedAt, post.UpdatedAt, post.Author.Username)
+
+	meta.JSONLD = schemaGraph(
+		schemaArticle(meta, post.PublishedAt, post.UpdatedAt),
+		schemaBreadcrumb(breadcrumb{"首页", "/"}, breadcrumb{"博客", "/blog"}, breadcrumb{title, path}),
+	)
+	return meta, nil
+}
+
+// lifeMeta 生活记录
+func lifeMeta(idParam string) (*model.SEOMetaVO, error) {
+	id, err := strconv.ParseUint(idParam, 10, 32)
+	if err != nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	var record model.LifeRecord
+	if err := database.Get().Preload("Author").First(&record, id).Error; err != nil {
+		return nil, err
+	}
+	if !isPublishedNow(record.Status, record.PublishedAt) {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	title := record.Title
+	if title == "" {
+		title = utils.GenerateExcerpt(record.Content, 50)
+	}
+
+	path := "/life/" + idParam
+	meta := newSEOMeta(model.SEOPageLife, title, record.Content, path, record.CoverImage)
+	setArticle(meta, record.PublishedAt, record.UpdatedAt, record.Author.Username)
+
+	meta.JSONLD = schemaGraph(
+		schemaArticle(meta, record.PublishedAt, record.UpdatedAt),
+		schemaBreadcrumb(breadcrumb{"首页", "/"}, breadcrumb{"生活", "/life"}, breadcrumb{title, path}),
+	)
+	return meta, nil
+}
+
+// tagMeta 标签
+func (h *SEOHandler) tagMeta(slug string) (*model.SEOMetaVO, error) {
+	tag, err := h.tagRepo.FindBySlug(slug)
+	if err != nil {
+		return nil, err
+	}
+
+	description := tag.Description
+	if description == "" {
+		description = "「" + tag.Name + "」相关的文章"
 	}
-	return result
+
+	path := "/category/" + tag.Slug
+	meta := newSEOMeta(model.SEOPageTag, tag.Name, description, path, "")
+	meta.Keywords = []string{tag.Name}
+
+	meta.JSONLD = schemaGraph(
+		schemaCollectionPage(meta),
+		schemaBreadcrumb(breadcrumb{"首页", "/"}, breadcrumb{"分类", "/category"}, breadcrumb{tag.Name, path}),
+	)
+	return meta, nil
+}
+
+// seriesMeta 系列（只解析包含公开文章的系列）
+func (h *SEOHandler) seriesMeta(slug string) (*model.SEOMetaVO, error) {
+	series, err := h.seriesRepo.FindBySlug(slug)
+	if err != nil {
+		return nil, err
+	}
+	posts, err := h.seriesRepo.FindPosts(series.ID, true)
+	if err != nil {
+		return nil, err
+	}
+	if len(posts) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	path := "/series/" + series.Slug
+	meta := newSEOMeta(model.SEOPageSeries, series.Name, series.Description, path, series.CoverImage)
+
+	meta.JSONLD = schemaGraph(
+		schemaCollectionPage(meta),
+		schemaBreadcrumb(breadcrumb{"首页", "/"}, breadcrumb{series.Name, path}),
+	)
+	return meta, nil
+}
+
+// ===========================================
+// 元数据构建
+// ===========================================
+
+// newSEOMeta 页面元数据的公共部分
+// title 为空时只使用站点名；description 可以是 Markdown，为空时使用站点描述；image 为空时使用默认分享图
+func newSEOMeta(pageType, title, description, path, image string) *model.SEOMetaVO {
+	site := config.Get().Site
+
+	fullTitle := site.Name
+	if title == "" {
+		title = site.Name
+	} else {
+		fullTitle = title + " - " + site.Name
+	}
+
+	if description == "" {
+		description = site.Description
+	}
+	description = utils.GenerateExcerpt(description, constants.SEODescriptionMaxLength)
+
+	canonical := site.URL + path
+	if path == "/" {
+		canonical = site.URL
+	}
+
+	if image == "" {
+		image = site.Image
+	}
+	image = utils.AbsoluteURL(site.URL, image)
+
+	card := "summary"
+	if image != "" {
+		card = "summary_large_image"
+	}
+
+	return &model.SEOMetaVO{
+		Type:        pageType,
+		Title:       fullTitle,
+		Description: description,
+		Canonical:   canonical,
+		Locale:      site.Language,
+		OpenGraph: model.OpenGraphVO{
+			Type:        "website",
+			Title:       title,
+			Description: description,
+			URL:         canonical,
+			Image:       image,
+			SiteName:    site.Name,
+			Locale:      ogLocale(site.Language),
+		},
+		Twitter: model.TwitterCardVO{
+			Card:        card,
+			Title:       title,
+			Description: description,
+			Image:       image,
+			Site:        site.Twitter,
+		},
+	}
+}
+
+// setArticle 文章类页面的 OpenGraph 字段
+func setArticle(meta *model.SEOMetaVO, publishedAt *time.Time, updatedAt time.Time, author string) {
+	meta.OpenGraph.Type = "article"
+	meta.OpenGraph.PublishedTime = publishedAt
+	meta.OpenGraph.ModifiedTime = &updatedAt
+	meta.OpenGraph.Author = author
+	meta.OpenGraph.Tags = meta.Keywords
+}
+
+// breadcrumb 面包屑导航项
+type breadcrumb struct {
+	name string
+	path string
+}
+
+// schemaGraph 组合多个 schema.org 节点
+func schemaGraph(nodes ...model.JSONLD) model.JSONLD {
+	return model.JSONLD{
+		"@context": "https://schema.org",
+		"@graph":   nodes,
+	}
+}
+
+// schemaArticle BlogPosting 节点
+func schemaArticle(meta *model.SEOMetaVO, publishedAt *time.Time, updatedAt time.Time) model.JSONLD {
+	node := model.JSONLD{
+		"@type":            "BlogPosting",
+		"@id":              meta.Canonical + "#article",
+		"headline":         meta.OpenGraph.Title,
+		"description":      meta.Description,
+		"url":              meta.Canonical,
+		"mainEntityOfPage": meta.Canonical,
+		"inLanguage":       meta.Locale,
+		"dateModified":     updatedAt.Format(time.RFC3339),
+		"publisher":        schemaPublisher(),
+	}
+	if publishedAt != nil {
+		node["datePublished"] = publishedAt.Format(time.RFC3339)
+	}
+	if meta.OpenGraph.Author != "" {
+		node["author"] = model.JSONLD{"@type": "Person", "name": meta.OpenGraph.Author}
+	} else {
+		node["author"] = schemaPublisher()
+	}
+	if meta.OpenGraph.Image != "" {
+		node["image"] = meta.OpenGraph.Image
+	}
+	if len(meta.Keywords) > 0 {
+		node["keywords"] = strings.Join(meta.Keywords, ", ")
+	}
+	return node
+}
+
+// schemaCollectionPage CollectionPage 节点
+func schemaCollectionPage(meta *model.SEOMetaVO) model.JSONLD {
+	return model.JSONLD{
+		"@type":       "CollectionPage",
+		"@id":         meta.Canonical,
+		"name":        meta.OpenGraph.Title,
+		"description": meta.Description,
+		"url":         meta.Canonical,
+		"inLanguage":  meta.Locale,
+	}
+}
+
+// schemaBreadcrumb BreadcrumbList 节点
+func schemaBreadcrumb(items ...breadcrumb) model.JSONLD {
+	site := config.Get().Site
+
+	elements := make([]model.JSONLD, len(items))
+	for i, item := range items {
+		link := site.URL + item.path
+		if item.path == "/" {
+			link = site.URL
+		}
+		elements[i] = model.JSONLD{
+			"@type":    "ListItem",
+			"position": i + 1,
+			"name":     item.name,
+			"item":     link,
+		}
+	}
+	return model.JSONLD{
+		"@type":           "BreadcrumbList",
+		"itemListElement": elements,
+	}
+}
+
+// schemaPublisher 站点作者节点
+func schemaPublisher() model.JSONLD {
+	site := config.Get().Site
+	return model.JSONLD{
+		"@type": "Person",
+		"name":  site.Name,
+		"url":   site.URL,
+	}
+}
+
+// ogLocale OpenGraph 的语言格式，如 zh-CN -> zh_CN
+func ogLocale(locale string) string {
+	return strings.ReplaceAll(locale, "-", "_")
+}
+
+// isPublishedNow 内容是否已发布且发布时间已到
+func isPublishedNow(status string, publishedAt *time.Time) bool {
+	if status != string(constants.PostStatusPublished) {
+		return false
+	}
+	return publishedAt == nil || !publishedAt.After(time.Now())
+}
+
+// ===========================================
+// 辅助函数
+// ===========================================
+
+// writeSitemap 输出 sitemap，支持条件请求
+func writeSitemap(c *gin.Context, doc *sitemap.Document, err error) {
+	if errors.Is(err, sitemap.ErrNotFound) {
+		response.NotFound(c, "")
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to build sitemap: %v", err)
+		response.InternalError(c, "")
+		return
+	}
+
+	writeConditional(c, "application/xml; charset=utf-8", doc.Data, doc.LastModified)
 }
 
 // GetLastBuildTime 获取最后构建时间
@@ -0,0 +1,268 @@
+// Package handler 文章系列处理器
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"kuaiyu/internal/model"
+	"kuaiyu/internal/repository"
+	"kuaiyu/pkg/constants"
+	"kuaiyu/pkg/response"
+	"kuaiyu/pkg/utils"
+)
+
+// ===========================================
+// 系列处理器
+// ===========================================
+
+// SeriesHandler 系列处理器
+type SeriesHandler struct {
+	repo *repository.SeriesRepository
+}
+
+// NewSeriesHandler 创建系列处理器
+func NewSeriesHandler() *SeriesHandler {
+	return &SeriesHandler{
+		repo: repository.NewSeriesRepository(),
+	}
+}
+
+// ===========================================
+// 公开接口
+// ===========================================
+
+// List 获取系列列表（只包含有公开文章的系列）
+func (h *SeriesHandler) List(c *gin.Context) {
+	series, err := h.repo.FindAll()
+	if err != nil {
+		response.InternalError(c, "")
+		return
+	}
+
+	counts, err := h.repo.CountPosts(true)
+	if err != nil {
+		response.InternalError(c, "")
+		return
+	}
+
+	items := make([]model.SeriesVO, 0, len(series))
+	for _, s := range series {
+		if counts[s.ID] == 0 {
+			continue
+		}
+		vo := s.ToVO()
+		vo.PostCount = counts[s.ID]
+		items = append(items, vo)
+	}
+
+	response.Success(c, items)
+}
+
+// GetBySlug 根据 slug 获取系列及其公开文章
+func (h *SeriesHandler) GetBySlug(c *gin.Context) {
+	series, err := h.repo.FindBySlug(c.Param("slug"))
+	if err != nil {
+		response.NotFound(c, "系列不存在")
+		return
+	}
+
+	posts, err := h.repo.FindPosts(series.ID, true)
+	if err != nil {
+		response.InternalError(c, "")
+		return
+	}
+	if len(posts) == 0 {
+		response.NotFound(c, "系列不存在")
+		return
+	}
+
+	vo := series.ToVOWithPosts(posts)
+	vo.Feeds = model.FeedLinks(series.Name, "/api/rss/series/"+series.Slug)
+	response.Success(c, vo)
+}
+
+// ===========================================
+// 管理接口
+// ===========================================
+
+// AdminList 管理后台系列列表
+func (h *SeriesHandler) AdminList(c *gin.Context) {
+	series, err := h.repo.FindAll()
+	if err != nil {
+		response.InternalError(c, "")
+		return
+	}
+
+	counts, err := h.repo.CountPosts(false)
+	if err != nil {
+		response.InternalError(c, "")
+		return
+	}
+
+	items := make([]model.SeriesVO, len(series))
+	for i, s := range series {
+		items[i] = s.ToVO()
+		items[i].PostCount = counts[s.ID]
+	}
+
+	response.Success(c, items)
+}
+
+// AdminGet 管理后台获取系列详情（包含全部状态的文章）
+func (h *SeriesHandler) AdminGet(c *gin.Context) {
+	id, err := GetIDParam(c, "id")
+	if err != nil {
+		response.BadRequest(c, "无效的 ID")
+		return
+	}
+
+	series, err := h.repo.FindByID(id)
+	if err != nil {
+		response.NotFound(c, "系列不存在")
+		return
+	}
+
+	posts, err := h.repo.FindPosts(series.ID, false)
+	if err != nil {
+		response.InternalError(c, "")
+		return
+	}
+
+	response.Success(c, series.ToVOWithPosts(posts))
+}
+
+// Create 创建系列
+func (h *SeriesHandler) Create(c *gin.Context) {
+	var req model.CreateSeriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	slug := req.Slug
+	if slug == "" {
+		slug = utils.GenerateSlug(req.Name)
+	}
+	if h.repo.SlugExists(slug, 0) {
+		response.BadRequest(c, constants.MsgSlugExists)
+		return
+	}
+
+	series := model.Series{
+		Name:        req.Name,
+		Slug:        slug,
+		Description: req.Description,
+		CoverImage:  req.CoverImage,
+	}
+
+	if err := h.repo.Create(&series); err != nil {
+		response.BadRequest(c, "系列名称已存在")
+		return
+	}
+
+	if len(req.PostIDs) > 0 {
+		if err := h.repo.SetPosts(series.ID, req.PostIDs); err != nil {
+			h.repo.Delete(series.ID)
+			response.BadRequest(c, err.Error())
+			return
+		}
+	}
+
+	posts, _ := h.repo.FindPosts(series.ID, false)
+
+	response.Created(c, series.ToVOWithPosts(posts))
+}
+
+// Update 更新系列
+func (h *SeriesHandler) Update(c *gin.Context) {
+	id, err := GetIDParam(c, "id")
+	if err != nil {
+		response.BadRequest(c, "无效的 ID")
+		return
+	}
+
+	var req model.UpdateSeriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	series, err := h.repo.FindByID(id)
+	if err != nil {
+		response.NotFound(c, "系列不存在")
+		return
+	}
+
+	if req.Name != "" {
+		series.Name = req.Name
+	}
+	if req.Slug != "" && req.Slug != series.Slug {
+		if h.repo.SlugExists(req.Slug, id) {
+			response.BadRequest(c, constants.MsgSlugExists)
+			return
+		}
+		series.Slug = req.Slug
+	}
+	if req.Description != "" {
+		series.Description = req.Description
+	}
+	if req.CoverImage != "" {
+		series.CoverImage = req.CoverImage
+	}
+
+	if err := h.repo.Update(series); err != nil {
+		response.InternalError(c, "更新失败")
+		return
+	}
+
+	response.SuccessMessage(c, constants.MsgUpdateSuccess, series.ToVO())
+}
+
+// SetPosts 设置系列文章及顺序（用于添加、移除和排序）
+func (h *SeriesHandler) SetPosts(c *gin.Context) {
+	id, err := GetIDParam(c, "id")
+	if err != nil {
+		response.BadRequest(c, "无效的 ID")
+		return
+	}
+
+	var req model.SetSeriesPostsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	series, err := h.repo.FindByID(id)
+	if err != nil {
+		response.NotFound(c, "系列不存在")
+		return
+	}
+
+	if err := h.repo.SetPosts(series.ID, req.PostIDs); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	posts, err := h.repo.FindPosts(series.ID, false)
+	if err != nil {
+		response.InternalError(c, "")
+		return
+	}
+
+	response.SuccessMessage(c, constants.MsgUpdateSuccess, series.ToVOWithPosts(posts))
+}
+
+// Delete 删除系列（文章本身保留）
+func (h *SeriesHandler) Delete(c *gin.Context) {
+	id, err := GetIDParam(c, "id")
+	if err != nil {
+		response.BadRequest(c, "无效的 ID")
+		return
+	}
+
+	if err := h.repo.Delete(id); err != nil {
+		response.InternalError(c, "删除失败")
+		return
+	}
+
+	response.SuccessMessage(c, constants.MsgDeleteSuccess, nil)
+}
@@ -2,9 +2,14 @@
 package handler
 
 import (
+	"log"
+
 	"github.com/gin-gonic/gin"
 	"kuaiyu/internal/database"
 	"kuaiyu/internal/model"
+	"kuaiyu/internal/related"
+	"kuaiyu/internal/repository"
+	"kuaiyu/internal/sitemap"
 	"kuaiyu/pkg/constants"
 	"kuaiyu/pkg/response"
 	"kuaiyu/pkg/utils"
@@ -15,11 +20,15 @@ import (
 // ===========================================
 
 // TagHandler 标签处理器
-type TagHandler struct{}
+type TagHandler struct {
+	redirectRepo *repository.RedirectRepository
+}
 
 // NewTagHandler 创建标签处理器
 func NewTagHandler() *TagHandler {
-	return &TagHandler{}
+	return &TagHandler{
+		redirectRepo: repository.NewRedirectRepository(),
+	}
 }
 
 // ===========================================
@@ -42,7 +51,8 @@ func (h *TagHandler) List(c *gin.Context) {
 		var count int64
 		db.Table("post_tags").
 			Joins("JOIN posts ON posts.id = post_tags.post_id").
-			Where("post_tags.tag_id = ? AND posts.status = ?", tag.ID, constants.PostStatusPublished).
+			Where("post_tags.tag_id = ?", tag.ID).
+			Scopes(repository.PublishedScope("posts"), repository.ListedScope("posts")).
 			Count(&count)
 		
 		items[i] = tag.ToVOWithCount(int(count))
@@ -60,7 +70,10 @@ func (h *TagHandler) GetBySlug(c *gin.Context) {
 	db := database.Get()
 	
 	if err := db.Where("slug = ?", slug).First(&tag).Error; err != nil {
-		response.NotFound(c, "标签不存在")
+		// 旧 slug 重定向到当前地址
+		if !respondSlugRedirect(c, h.redirectRepo, model.RedirectTypeTag, slug) {
+			response.NotFound(c, "标签不存在")
+		}
 		return
 	}
 	
@@ -70,7 +83,8 @@ func (h *TagHandler) GetBySlug(c *gin.Context) {
 	
 	query := db.Model(&model.Post{}).
 		Joins("JOIN post_tags ON post_tags.post_id = posts.id").
-		Where("post_tags.tag_id = ? AND posts.status = ?", tag.ID, constants.PostStatusPublished).
+		Where("post_tags.tag_id = ?", tag.ID).
+		Scopes(repository.PublishedScope("posts"), repository.ListedScope("posts")).
 		Preload("Tags")
 	
 	query.Count(&total)
@@ -92,6 +106,7 @@ func (h *TagHandler) GetBySlug(c *gin.Context) {
 		TagVO: tag.ToVOWithCount(int(total)),
 		Posts: postVOs,
 	}
+	result.Feeds = model.FeedLinks(tag.Name, "/api/tags/"+tag.Slug+"/rss")
 	
 	response.Success(c, gin.H{
 		"tag":   result.TagVO,
@@ -174,6 +189,8 @@ func (h *TagHandler) Update(c *gin.Context) {
 		return
 	}
 	
+	oldSlug := tag.Slug
+	
 	if req.Name != "" {
 		tag.Name = req.Name
 	}
@@ -192,6 +209,13 @@ func (h *TagHandler) Update(c *gin.Context) {
 		return
 	}
 	
+	// 记录旧 slug，旧链接重定向到新地址
+	if err := h.redirectRepo.RecordSlugChange(model.RedirectTypeTag, tag.ID, oldSlug, tag.Slug); err != nil {
+		log.Printf("Failed to record slug redirect for tag %d: %v", tag.ID, err)
+	}
+	
+	// 标签表没有更新时间，改名和删除需要主动刷新站点地图
+	sitemap.Invalidate()
 	response.SuccessMessage(c, constants.MsgUpdateSuccess, tag.ToVO())
 }
 
@@ -214,6 +238,12 @@ func (h *TagHandler) Delete(c *gin.Context) {
 		return
 	}
 	
+	h.redirectRepo.DeleteByTarget(model.RedirectTypeTag, id)
+	
+	// 标签变化会影响相关文章得分
+	related.Refresh()
+	
+	sitemap.Invalidate()
 	response.SuccessMessage(c, constants.MsgDeleteSuccess, nil)
 }
 
@@ -0,0 +1,259 @@
+// Package handler 文章翻译处理器
+package handler
+
+import (
+	"errors"
+	"log"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"kuaiyu/internal/model"
+	"kuaiyu/internal/repository"
+	"kuaiyu/pkg/constants"
+	"kuaiyu/pkg/locale"
+	"kuaiyu/pkg/response"
+	"kuaiyu/pkg/utils"
+)
+
+// ===========================================
+// 文章翻译处理器
+// ===========================================
+
+// TranslationHandler 文章翻译处理器
+type TranslationHandler struct {
+	repo         *repository.TranslationRepository
+	postRepo     *repository.PostRepository
+	redirectRepo *repository.RedirectRepository
+}
+
+// NewTranslationHandler 创建文章翻译处理器
+func NewTranslationHandler() *TranslationHandler {
+	return &TranslationHandler{
+		repo:         repository.NewTranslationRepository(),
+		postRepo:     repository.NewPostRepository(),
+		redirectRepo: repository.NewRedirectRepository(),
+	}
+}
+
+// ===========================================
+// 管理接口
+// ===========================================
+
+// List 文章的全部翻译
+func (h *TranslationHandler) List(c *gin.Context) {
+	id, err := GetIDParam(c, "id")
+	if err != nil {
+		response.BadRequest(c, "无效的文章 ID")
+		return
+	}
+
+	translations, err := h.repo.FindByPost(id)
+	if err != nil {
+		response.InternalError(c, "")
+		return
+	}
+
+	response.Success(c, translations)
+}
+
+// Save 创建或更新指定语言的翻译
+// PUT /api/admin/posts/:id/translations/:locale
+func (h *TranslationHandler) Save(c *gin.Context) {
+	id, err := GetIDParam(c, "id")
+	if err != nil {
+		response.BadRequest(c, "无效的文章 ID")
+		return
+	}
+
+	lang, ok := translationLocale(c)
+	if !ok {
+		return
+	}
+
+	var req model.SavePostTranslationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	post, err := h.postRepo.FindByID(id)
+	if err != nil {
+		response.NotFound(c, "文章不存在")
+		return
+	}
+
+	translation, err := h.repo.FindByPostAndLocale(id, lang)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			response.InternalError(c, "")
+			return
+		}
+		translation = &model.PostTranslation{PostID: id, Locale: lang}
+	}
+	oldSlug := translation.Slug
+
+	// 生成 slug：显式指定的冲突时报错，自动生成的追加随机后缀
+	slug := req.Slug
+	if slug != "" {
+		if h.repo.SlugExists(slug, translation.ID) {
+			response.BadRequest(c, constants.MsgSlugExists)
+			return
+		}
+	} else if oldSlug != "" {
+		slug = oldSlug
+	} else {
+		slug = utils.GenerateSlug(req.Title)
+		if slug == "" {
+			slug = post.Slug + "-" + strings.ToLower(lang)
+		}
+		if h.repo.SlugExists(slug, translation.ID) {
+			slug = slug + "-" + utils.GenerateRandomString(4)
+		}
+	}
+
+	// 加密文章不自动生成摘要，避免泄露正文
+	excerpt := req.Excerpt
+	if excerpt == "" && !post.IsProtected() {
+		excerpt = utils.GenerateExcerpt(req.Content, constants.ExcerptMaxLength)
+	}
+
+	translation.Title = req.Title
+	translation.Slug = slug
+	translation.Content = req.Content
+	translation.Excerpt = excerpt
+
+	if err := h.repo.Save(translation); err != nil {
+		response.InternalError(c, "保存失败")
+		return
+	}
+
+	// 翻译的旧 slug 重定向到翻译的新地址
+	if err := h.redirectRepo.RecordSlugChange(model.RedirectTypeTranslation, translation.ID, oldSlug, slug); err != nil {
+		log.Printf("Failed to record slug redirect for translation %d: %v", translation.ID, err)
+	}
+
+	response.SuccessMessage(c, constants.MsgUpdateSuccess, translation)
+}
+
+// Delete 删除指定语言的翻译，旧地址重定向到原文
+func (h *TranslationHandler) Delete(c *gin.Context) {
+	id, err := GetIDParam(c, "id")
+	if err != nil {
+		response.BadRequest(c, "无效的文章 ID")
+		return
+	}
+
+	lang, ok := translationLocale(c)
+	if !ok {
+		return
+	}
+
+	translation, err := h.repo.FindByPostAndLocale(id, lang)
+	if err != nil {
+		response.NotFound(c, "翻译不存在")
+		return
+	}
+
+	if err := h.repo.Delete(id, lang); err != nil {
+		response.InternalError(c, "删除失败")
+		return
+	}
+
+	if post, err := h.postRepo.FindByID(id); err == nil {
+		if err := h.redirectRepo.MoveTranslationRedirects(translation.ID, id); err != nil {
+			log.Printf("Failed to move slug redirects of translation %d: %v", translation.ID, err)
+		}
+		if err := h.redirectRepo.RecordSlugChange(model.RedirectTypePost, id, translation.Slug, post.Slug); err != nil {
+			log.Printf("Failed to record slug redirect for translation %d: %v", translation.ID, err)
+		}
+	}
+
+	response.SuccessMessage(c, constants.MsgDeleteSuccess, nil)
+}
+
+// translationLocale 读取并校验路径中的语言标识，失败时已写入响应
+func translationLocale(c *gin.Context) (string, bool) {
+	lang := locale.Normalize(c.Param("locale"))
+	if lang == "" {
+		response.BadRequest(c, "无效的语言标识")
+		return "", false
+	}
+	if strings.EqualFold(lang, constants.DefaultLocale) {
+		response.BadRequest(c, "原文语言无需添加翻译")
+		return "", false
+	}
+	return lang, true
+}
+
+// ===========================================
+// 多语言内容选择
+// ===========================================
+
+// requestedLocales 读取请求的语言偏好：?lang= 优先，其次 Accept-Language
+func requestedLocales(c *gin.Context) []string {
+	c.Header("Vary", "Accept-Language")
+	if lang := locale.Normalize(c.Query("lang")); lang != "" {
+		return []string{lang}
+	}
+	return locale.ParseAcceptLanguage(c.GetHeader("Accept-Language"))
+}
+
+// localizePost 在原文和翻译中选择最匹配的语言覆盖文章视图，并列出全部可用语言
+func localizePost(vo *model.PostVO, translations []model.PostTranslation, preferred []string) {
+	vo.Locale = constants.DefaultLocale
+	if len(translations) == 0 {
+		return
+	}
+
+	available := []string{constants.DefaultLocale}
+	vo.Languages = []model.PostLanguageVO{{Locale: constants.DefaultLocale, Slug: vo.Slug, Title: vo.Title}}
+	for _, t := range translations {
+		available = append(available, t.Locale)
+		vo.Languages = append(vo.Languages, t.ToLanguageVO())
+	}
+
+	best := locale.Match(preferred, available, constants.DefaultLocale)
+	for i := range translations {
+		if translations[i].Locale == best {
+			translations[i].ApplyTo(vo)
+			return
+		}
+	}
+}
+
+// localizePostList 批量为文章列表选择语言，查询失败时保留原文
+func localizePostList(repo *repository.TranslationRepository, items []model.PostListVO, preferred []string) {
+	ids := make([]uint, len(items))
+	for i := range items {
+		items[i].Locale = constants.DefaultLocale
+		ids[i] = items[i].ID
+	}
+
+	summaries, err := repo.FindSummaries(ids)
+	if err != nil {
+		log.Printf("Failed to load post translations: %v", err)
+		return
+	}
+
+	for i := range items {
+		translations := summaries[items[i].ID]
+		if len(translations) == 0 {
+			continue
+		}
+
+		available := []string{constants.DefaultLocale}
+		for _, t := range translations {
+			available = append(available, t.Locale)
+		}
+		items[i].Languages = available
+
+		best := locale.Match(preferred, available, constants.DefaultLocale)
+		for j := range translations {
+			if translations[j].Locale == best {
+				translations[j].ApplyToList(&items[i])
+				break
+			}
+		}
+	}
+}
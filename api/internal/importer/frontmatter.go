@@ -0,0 +1,264 @@
+package importer
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-yaml"
+	"github.com/pelletier/go-toml/v2"
+)
+
+// ===========================================
+// 文档结构
+// ===========================================
+
+// Document 解析后的 Markdown 文档
+type Document struct {
+	Title      string
+	Slug       string
+	Date       *time.Time
+	Updated    *time.Time
+	Tags       []string
+	Draft      bool
+	Excerpt    string
+	CoverImage string
+	Content    string
+}
+
+// reJekyllName Jekyll 文件名格式：2006-01-02-slug.md
+var reJekyllName = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2})-(.+)$`)
+
+// dateLayouts 支持的日期格式（无时区的按本地时区解析）
+var dateLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05 -0700",
+	"2006-01-02 15:04:05 Z07:00",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"2006/01/02 15:04:05",
+	"2006/01/02 15:04",
+	"2006/01/02",
+}
+
+// ===========================================
+// 解析
+// ===========================================
+
+// Parse 解析带 YAML（---）或 TOML（+++）front matter 的 Markdown 文件
+// front matter 中缺失的 slug 和日期会尝试从文件路径推断
+func Parse(name string, data []byte) (*Document, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+
+	meta, body, err := splitFrontMatter(text)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &Document{
+		Title:      metaString(meta, "title"),
+		Slug:       metaString(meta, "slug"),
+		Tags:       metaList(meta, "tags", "tag"),
+		Draft:      metaBool(meta, "draft") || (meta["published"] != nil && !metaBool(meta, "published")),
+		Excerpt:    metaString(meta, "excerpt", "description", "summary"),
+		CoverImage: metaString(meta, "cover_image", "cover", "image", "thumbnail", "banner", "featured_image"),
+		Content:    strings.TrimSpace(body),
+	}
+
+	if doc.Date, err = metaTime(meta, "date"); err != nil {
+		return nil, err
+	}
+	if doc.Updated, err = metaTime(meta, "updated", "lastmod", "last_modified_at"); err != nil {
+		return nil, err
+	}
+
+	// Hexo 和 Jekyll 的草稿目录
+	if strings.HasPrefix(name, "_drafts/") || strings.Contains(name, "/_drafts/") {
+		doc.Draft = true
+	}
+
+	// 从文件名推断 slug 和日期
+	base := strings.TrimSuffix(path.Base(name), path.Ext(name))
+	if base == "index" || base == "_index" {
+		// Hugo page bundle：content/posts/my-post/index.md
+		base = path.Base(path.Dir(name))
+	}
+	if m := reJekyllName.FindStringSubmatch(base); m != nil {
+		base = m[2]
+		if doc.Date == nil {
+			if t, err := time.ParseInLocation("2006-01-02", m[1], time.Local); err == nil {
+				doc.Date = &t
+			}
+		}
+	}
+	if doc.Slug == "" && base != "." && base != "/" {
+		doc.Slug = base
+	}
+
+	return doc, nil
+}
+
+// splitFrontMatter 拆分 front matter 和正文
+func splitFrontMatter(text string) (map[string]interface{}, string, error) {
+	var delim string
+	switch {
+	case strings.HasPrefix(text, "---\n"):
+		delim = "---"
+	case strings.HasPrefix(text, "+++\n"):
+		delim = "+++"
+	default:
+		return map[string]interface{}{}, text, nil
+	}
+
+	rest := text[len(delim)+1:]
+	var header, body string
+	found := false
+	for offset := 0; offset <= len(rest); {
+		end := strings.IndexByte(rest[offset:], '\n')
+		line := rest[offset:]
+		if end >= 0 {
+			line = rest[offset : offset+end]
+		}
+		trimmed := strings.TrimRight(line, " \t")
+		if trimmed == delim || (delim == "---" && trimmed == "...") {
+			header = rest[:offset]
+			if end >= 0 {
+				body = rest[offset+end+1:]
+			}
+			found = true
+			break
+		}
+		if end < 0 {
+			break
+		}
+		offset += end + 1
+	}
+	if !found {
+		return nil, "", fmt.Errorf("front matter 未闭合")
+	}
+
+	raw := make(map[string]interface{})
+	var err error
+	if delim == "---" {
+		err = yaml.Unmarshal([]byte(header), &raw)
+	} else {
+		err = toml.Unmarshal([]byte(header), &raw)
+	}
+	if err != nil {
+		// YAML 错误信息附带多行源码片段，报告中只保留首行
+		msg := strings.SplitN(err.Error(), "\n", 2)[0]
+		return nil, "", fmt.Errorf("front matter 解析失败: %s", msg)
+	}
+
+	// 键名统一转小写
+	meta := make(map[string]interface{}, len(raw))
+	for k, v := range raw {
+		meta[strings.ToLower(k)] = v
+	}
+	return meta, body, nil
+}
+
+// ===========================================
+// 字段取值
+// ===========================================
+
+// metaString 取第一个非空的字符串字段
+func metaString(meta map[string]interface{}, keys ...string) string {
+	for _, key := range keys {
+		v, ok := meta[key]
+		if !ok || v == nil {
+			continue
+		}
+		var s string
+		switch val := v.(type) {
+		case string:
+			s = val
+		case []interface{}, map[string]interface{}:
+			continue
+		default:
+			s = fmt.Sprint(val)
+		}
+		if s = strings.TrimSpace(s); s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+// metaList 取列表字段，兼容 YAML/TOML 数组和逗号分隔的字符串
+func metaList(meta map[string]interface{}, keys ...string) []string {
+	var items []string
+	seen := make(map[string]bool)
+	add := func(s string) {
+		s = strings.TrimSpace(s)
+		if s == "" || seen[strings.ToLower(s)] {
+			return
+		}
+		seen[strings.ToLower(s)] = true
+		items = append(items, s)
+	}
+
+	for _, key := range keys {
+		switch val := meta[key].(type) {
+		case []interface{}:
+			for _, item := range val {
+				if item != nil {
+					add(fmt.Sprint(item))
+				}
+			}
+		case string:
+			for _, item := range strings.Split(val, ",") {
+				add(item)
+			}
+		case nil:
+		default:
+			add(fmt.Sprint(val))
+		}
+	}
+	return items
+}
+
+// metaBool 取布尔字段
+func metaBool(meta map[string]interface{}, key string) bool {
+	switch val := meta[key].(type) {
+	case bool:
+		return val
+	case string:
+		b, _ := strconv.ParseBool(strings.TrimSpace(val))
+		return b || strings.EqualFold(strings.TrimSpace(val), "yes")
+	}
+	return false
+}
+
+// metaTime 取时间字段
+func metaTime(meta map[string]interface{}, keys ...string) (*time.Time, error) {
+	for _, key := range keys {
+		v, ok := meta[key]
+		if !ok || v == nil {
+			continue
+		}
+		if t, ok := v.(time.Time); ok {
+			return &t, nil
+		}
+
+		// TOML 的本地日期时间类型和字符串统一按文本解析
+		s := strings.TrimSpace(fmt.Sprint(v))
+		if s == "" {
+			continue
+		}
+		for _, layout := range dateLayouts {
+			if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+				return &t, nil
+			}
+		}
+		return nil, fmt.Errorf("无法识别的日期格式 %s: %q", key, s)
+	}
+	return nil, nil
+}
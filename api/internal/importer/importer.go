@@ -0,0 +1,234 @@
+// Package importer 文章批量导入
+// 支持 Hexo、Hugo、Jekyll 等静态博客的 Markdown 文件（YAML/TOML front matter），
+// 先生成预演报告（新建/跳过/冲突），确认后再实际写入
+package importer
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"kuaiyu/internal/model"
+	"kuaiyu/internal/repository"
+	"kuaiyu/internal/search"
+	"kuaiyu/pkg/constants"
+	"kuaiyu/pkg/utils"
+)
+
+// ===========================================
+// 导入器
+// ===========================================
+
+// Importer 文章导入器
+type Importer struct {
+	postRepo     *repository.PostRepository
+	tagRepo      *repository.TagRepository
+	revisionRepo *repository.RevisionRepository
+}
+
+// NewImporter 创建文章导入器
+func NewImporter() *Importer {
+	return &Importer{
+		postRepo:     repository.NewPostRepository(),
+		tagRepo:      repository.NewTagRepository(),
+		revisionRepo: repository.NewRevisionRepository(),
+	}
+}
+
+// planned 预演结果中待新建的文章
+type planned struct {
+	index int // 在报告中的位置
+	doc   *Document
+}
+
+// Plan 预演导入，不写入任何数据
+func (im *Importer) Plan(files []File) *model.ImportReport {
+	report, _ := im.plan(files, time.Now())
+	report.DryRun = true
+	return report
+}
+
+// Apply 执行导入：重新预演后仅新建报告中标记为 create 的文章
+// 相关文章的重算由调用方负责
+func (im *Importer) Apply(files []File, authorID uint) *model.ImportReport {
+	now := time.Now()
+	report, pending := im.plan(files, now)
+
+	for _, p := range pending {
+		item := &report.Items[p.index]
+		post, err := im.create(item, p.doc, authorID, now)
+		if err != nil {
+			item.Action = model.ImportActionConflict
+			item.Reason = fmt.Sprintf("创建失败: %v", err)
+			continue
+		}
+		item.PostID = post.ID
+	}
+
+	report.Count()
+	return report
+}
+
+// ===========================================
+// 内部实现
+// ===========================================
+
+// plan 逐个解析文件并判断导入动作
+func (im *Importer) plan(files []File, now time.Time) (*model.ImportReport, []planned) {
+	report := &model.ImportReport{
+		Items:   make([]model.ImportItem, 0, len(files)),
+		NewTags: []string{},
+	}
+	var pending []planned
+
+	slugs := make(map[string]string) // 本批次已占用的 slug -> 文件
+	newTags := make(map[string]bool)
+
+	for _, file := range files {
+		item := model.ImportItem{File: file.Path}
+
+		doc, err := Parse(file.Path, file.Data)
+		if err != nil {
+			item.Action = model.ImportActionSkip
+			item.Reason = err.Error()
+			report.Items = append(report.Items, item)
+			continue
+		}
+
+		item.Title = doc.Title
+		item.Slug = normalizeSlug(doc.Slug, doc.Title)
+		item.Tags = doc.Tags
+		item.Status, item.PublishedAt = resolveStatus(doc, now)
+
+		switch {
+		case doc.Title == "":
+			item.Action = model.ImportActionSkip
+			item.Reason = "缺少标题"
+		case doc.Content == "":
+			item.Action = model.ImportActionSkip
+			item.Reason = "正文为空"
+		case item.Slug == "":
+			item.Action = model.ImportActionSkip
+			item.Reason = "无法生成 slug"
+		case slugs[item.Slug] != "":
+			item.Action = model.ImportActionConflict
+			item.Reason = fmt.Sprintf("slug 与 %s 重复", slugs[item.Slug])
+		default:
+			if existing, err := im.postRepo.FindBySlug(item.Slug); err == nil {
+				if existing.Title == doc.Title {
+					item.Action = model.ImportActionSkip
+					item.Reason = fmt.Sprintf("已存在同名文章 #%d", existing.ID)
+				} else {
+					item.Action = model.ImportActionConflict
+					item.Reason = fmt.Sprintf("slug 已被文章 #%d 占用", existing.ID)
+				}
+				item.PostID = existing.ID
+			} else {
+				item.Action = model.ImportActionCreate
+			}
+		}
+
+		if item.Slug != "" && slugs[item.Slug] == "" {
+			slugs[item.Slug] = file.Path
+		}
+
+		if item.Action == model.ImportActionCreate {
+			for _, name := range doc.Tags {
+				if _, err := im.tagRepo.FindByName(name); err != nil && !newTags[strings.ToLower(name)] {
+					newTags[strings.ToLower(name)] = true
+					report.NewTags = append(report.NewTags, name)
+				}
+			}
+			pending = append(pending, planned{index: len(report.Items), doc: doc})
+		}
+
+		report.Items = append(report.Items, item)
+	}
+
+	sort.Strings(report.NewTags)
+	report.Count()
+	return report, pending
+}
+
+// create 新建一篇文章及其标签
+func (im *Importer) create(item *model.ImportItem, doc *Document, authorID uint, now time.Time) (*model.Post, error) {
+	tagIDs := make([]uint, 0, len(doc.Tags))
+	for _, name := range doc.Tags {
+		tag, _, err := im.tagRepo.FindOrCreate(name)
+		if err != nil {
+			return nil, fmt.Errorf("标签 %s: %v", name, err)
+		}
+		tagIDs = append(tagIDs, tag.ID)
+	}
+
+	excerpt := doc.Excerpt
+	if excerpt == "" {
+		excerpt = utils.GenerateExcerpt(doc.Content, constants.ExcerptMaxLength)
+	}
+
+	post := model.Post{
+		Title:       doc.Title,
+		Slug:        item.Slug,
+		Content:     doc.Content,
+		Excerpt:     excerpt,
+		CoverImage:  doc.CoverImage,
+		Status:      item.Status,
+		AuthorID:    authorID,
+		PublishedAt: item.PublishedAt,
+	}
+
+	// 保留原始的创建和更新时间
+	if doc.Date != nil && doc.Date.Before(now) {
+		post.CreatedAt = *doc.Date
+	}
+	if doc.Updated != nil && doc.Updated.Before(now) {
+		post.UpdatedAt = *doc.Updated
+	}
+
+	if err := im.postRepo.Create(&post); err != nil {
+		return nil, err
+	}
+	if len(tagIDs) > 0 {
+		if err := im.postRepo.UpdateTags(&post, tagIDs); err != nil {
+			log.Printf("Failed to set tags for imported post %d: %v", post.ID, err)
+		}
+	}
+
+	revision := model.NewPostRevision(&post, string(constants.RevisionReasonImport), authorID)
+	if _, err := im.revisionRepo.Save(&revision); err != nil {
+		log.Printf("Failed to save post revision: %v", err)
+	}
+
+	search.SyncPost(&post)
+	return &post, nil
+}
+
+// normalizeSlug 保留原始 slug，格式不合法时按规则重新生成
+func normalizeSlug(slug, title string) string {
+	if utils.IsValidSlug(slug) {
+		return slug
+	}
+	if s := utils.GenerateSlug(slug); s != "" {
+		return s
+	}
+	return utils.GenerateSlug(title)
+}
+
+// resolveStatus 根据草稿标记和原始日期确定状态和发布时间
+// 日期在未来的文章按定时发布处理
+func resolveStatus(doc *Document, now time.Time) (string, *time.Time) {
+	if doc.Draft {
+		return string(constants.PostStatusDraft), nil
+	}
+
+	publishedAt := now
+	if doc.Date != nil {
+		publishedAt = *doc.Date
+	}
+	if publishedAt.After(now) {
+		return string(constants.PostStatusScheduled), &publishedAt
+	}
+	return string(constants.PostStatusPublished), &publishedAt
+}
@@ -0,0 +1,190 @@
+package importer
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ===========================================
+// 读取限制
+// ===========================================
+
+const (
+	// maxFiles 单次导入的最大文件数
+	maxFiles = 2000
+	// maxFileSize 单个 Markdown 文件的最大大小
+	maxFileSize = 2 << 20
+	// maxTotalSize 单次导入解压后的最大总大小
+	maxTotalSize = 64 << 20
+)
+
+// ErrTooLarge 导入内容超出限制
+var ErrTooLarge = errors.New("导入内容过大")
+
+// ===========================================
+// 导入源
+// ===========================================
+
+// File 待导入的 Markdown 文件
+type File struct {
+	Path string // 相对路径，使用 / 分隔
+	Data []byte
+}
+
+// IsMarkdown 判断是否为需要导入的 Markdown 文件
+// 忽略隐藏文件和 macOS 压缩包产生的 __MACOSX 目录
+func IsMarkdown(name string) bool {
+	name = filepath.ToSlash(name)
+	for _, part := range strings.Split(name, "/") {
+		if strings.HasPrefix(part, ".") || part == "__MACOSX" {
+			return false
+		}
+	}
+	switch strings.ToLower(path.Ext(name)) {
+	case ".md", ".markdown", ".mdown":
+		return true
+	}
+	return false
+}
+
+// ReadZip 读取 zip 压缩包中的 Markdown 文件
+func ReadZip(r io.ReaderAt, size int64) ([]File, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("无效的 zip 文件: %w", err)
+	}
+
+	var files []File
+	var total int64
+	for _, entry := range zr.File {
+		if entry.FileInfo().IsDir() || !IsMarkdown(entry.Name) {
+			continue
+		}
+
+		data, err := readZipEntry(entry)
+		if err != nil {
+			return nil, err
+		}
+
+		total += int64(len(data))
+		if len(files) >= maxFiles || total > maxTotalSize {
+			return nil, ErrTooLarge
+		}
+		files = append(files, File{Path: path.Clean(entry.Name), Data: data})
+	}
+
+	sortFiles(files)
+	return files, nil
+}
+
+// ReadDir 递归读取目录中的 Markdown 文件
+func ReadDir(root string) ([]File, error) {
+	var files []File
+	var total int64
+
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(root, p)
+		if d.IsDir() {
+			if rel != "." && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !IsMarkdown(rel) {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if info.Size() > maxFileSize {
+			return fmt.Errorf("%s: %w", rel, ErrTooLarge)
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+
+		total += int64(len(data))
+		if len(files) >= maxFiles || total > maxTotalSize {
+			return ErrTooLarge
+		}
+		files = append(files, File{Path: filepath.ToSlash(rel), Data: data})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sortFiles(files)
+	return files, nil
+}
+
+// ReadPath 读取本地路径，支持目录、zip 压缩包和单个 Markdown 文件
+func ReadPath(p string) ([]File, error) {
+	info, err := os.Stat(p)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return ReadDir(p)
+	}
+
+	f, err := os.Open(p)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	if strings.EqualFold(filepath.Ext(p), ".zip") {
+		return ReadZip(f, info.Size())
+	}
+	if !IsMarkdown(filepath.Base(p)) {
+		return nil, fmt.Errorf("不支持的文件类型: %s", filepath.Base(p))
+	}
+	if info.Size() > maxFileSize {
+		return nil, ErrTooLarge
+	}
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+	return []File{{Path: filepath.Base(p), Data: data}}, nil
+}
+
+// readZipEntry 读取压缩包中的单个文件，按解压后的实际大小限制（防止压缩炸弹）
+func readZipEntry(entry *zip.File) ([]byte, error) {
+	rc, err := entry.Open()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", entry.Name, err)
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(io.LimitReader(rc, maxFileSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", entry.Name, err)
+	}
+	if len(data) > maxFileSize {
+		return nil, fmt.Errorf("%s: %w", entry.Name, ErrTooLarge)
+	}
+	return data, nil
+}
+
+// sortFiles 按路径排序，保证报告顺序稳定
+func sortFiles(files []File) {
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Path < files[j].Path
+	})
+}
@@ -0,0 +1,67 @@
+// Package middleware 草稿预览令牌
+package middleware
+
+import (
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"kuaiyu/internal/config"
+)
+
+// ===========================================
+// 预览令牌
+// ===========================================
+
+// previewAudience 预览令牌的受众标识
+const previewAudience = "preview"
+
+// PreviewClaims 草稿预览令牌声明
+type PreviewClaims struct {
+	TargetType string `json:"target_type"` // post | life
+	TargetID   uint   `json:"target_id"`
+	jwt.RegisteredClaims
+}
+
+// GeneratePreviewToken 生成草稿预览令牌
+// linkID 为预览链接记录的唯一标识，用于撤销和统计打开次数
+func GeneratePreviewToken(linkID, targetType string, targetID uint, expiresAt time.Time) (string, error) {
+	cfg := config.Get()
+
+	claims := PreviewClaims{
+		TargetType: targetType,
+		TargetID:   targetID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        linkID,
+			Audience:  jwt.ClaimStrings{previewAudience},
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			Issuer:    cfg.JWT.Issuer,
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(previewKey())
+}
+
+// ParsePreviewToken 解析草稿预览令牌
+func ParsePreviewToken(tokenString string) (*PreviewClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &PreviewClaims{}, func(token *jwt.Token) (interface{}, error) {
+		return previewKey(), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Name}), jwt.WithAudience(previewAudience))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if claims, ok := token.Claims.(*PreviewClaims); ok && token.Valid && claims.ID != "" {
+		return claims, nil
+	}
+
+	return nil, jwt.ErrSignatureInvalid
+}
+
+// previewKey 预览令牌签名密钥
+// 由 JWT 密钥派生，保证预览令牌无法被当作登录令牌使用
+func previewKey() []byte {
+	return []byte(config.Get().JWT.Secret + ":" + previewAudience)
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"kuaiyu/pkg/constants"
 	"kuaiyu/pkg/response"
 )
 
@@ -147,3 +148,15 @@ func UploadRateLimit() gin.HandlerFunc {
 	return RateLimit(10, time.Minute)
 }
 
+
+// ReactionRateLimit 读者表态限流 (30次/分钟)
+func ReactionRateLimit() gin.HandlerFunc {
+	return RateLimit(constants.RateLimitReaction, time.Minute)
+}
+
+// UnlockRateLimit 加密文章解锁限流 (10次/15分钟，按 IP 和文章计)
+func UnlockRateLimit() gin.HandlerFunc {
+	return RateLimitByKey(constants.RateLimitUnlock, 15*time.Minute, func(c *gin.Context) string {
+		return c.ClientIP() + ":" + c.Param("id")
+	})
+}
@@ -0,0 +1,65 @@
+// Package middleware 加密文章解锁令牌
+package middleware
+
+import (
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"kuaiyu/internal/config"
+)
+
+// ===========================================
+// 解锁令牌
+// ===========================================
+
+// unlockAudience 解锁令牌的受众标识
+const unlockAudience = "unlock"
+
+// UnlockClaims 加密文章解锁令牌声明
+type UnlockClaims struct {
+	PostID      uint   `json:"post_id"`
+	Fingerprint string `json:"fp"` // 密码指纹，修改密码后旧令牌失效
+	jwt.RegisteredClaims
+}
+
+// GenerateUnlockToken 生成加密文章解锁令牌
+func GenerateUnlockToken(postID uint, fingerprint string, expiresAt time.Time) (string, error) {
+	cfg := config.Get()
+
+	claims := UnlockClaims{
+		PostID:      postID,
+		Fingerprint: fingerprint,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Audience:  jwt.ClaimStrings{unlockAudience},
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			Issuer:    cfg.JWT.Issuer,
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(unlockKey())
+}
+
+// ParseUnlockToken 解析加密文章解锁令牌
+func ParseUnlockToken(tokenString string) (*UnlockClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &UnlockClaims{}, func(token *jwt.Token) (interface{}, error) {
+		return unlockKey(), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Name}), jwt.WithAudience(unlockAudience))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if claims, ok := token.Claims.(*UnlockClaims); ok && token.Valid && claims.PostID != 0 {
+		return claims, nil
+	}
+
+	return nil, jwt.ErrSignatureInvalid
+}
+
+// unlockKey 解锁令牌签名密钥
+// 由 JWT 密钥派生，与登录令牌和预览令牌互不通用
+func unlockKey() []byte {
+	return []byte(config.Get().JWT.Secret + ":" + unlockAudience)
+}
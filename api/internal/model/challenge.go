@@ -0,0 +1,35 @@
+// Package model 评论工作量证明模型
+package model
+
+import (
+	"time"
+)
+
+// ===========================================
+// 工作量证明
+// ===========================================
+
+// ChallengeAlgorithm 工作量证明使用的哈希算法
+const ChallengeAlgorithm = "sha256"
+
+// ChallengeVO 工作量证明题目
+// 客户端需找到 nonce，使 SHA-256(challenge + nonce) 的前 difficulty 位均为 0
+type ChallengeVO struct {
+	Required   bool      `json:"required"` // 提交评论是否需要工作量证明
+	Challenge  string    `json:"challenge"`
+	Algorithm  string    `json:"algorithm"`
+	Difficulty int       `json:"difficulty"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// ChallengeRedemption 已使用的题目，保证每道题只能提交一次
+type ChallengeRedemption struct {
+	ID        string    `gorm:"primaryKey;size:36" json:"id"`     // 题目令牌的 jti
+	ExpiresAt time.Time `gorm:"not null;index" json:"expires_at"` // 过期后记录可清理
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName 表名
+func (ChallengeRedemption) TableName() string {
+	return "challenge_redemptions"
+}
@@ -12,6 +12,9 @@ type Comment struct {
 	LifeRecordID   *uint  `gorm:"index" json:"life_record_id"`                                                      // 保留用于向后兼容
 	ParentID       *uint  `gorm:"index" json:"parent_id"`
 	ReplyToID      *uint  `gorm:"index" json:"reply_to_id"`
+	RootID         *uint  `gorm:"index" json:"root_id"`       // 所在主题的顶层评论，顶层评论为空
+	Depth          int    `gorm:"default:0" json:"depth"`     // 层级，顶层评论为 0
+	Path           string `gorm:"size:760;index" json:"-"`    // 物化路径，见 SetThread
 	Nickname       string `gorm:"size:50;not null" json:"nickname"`
 	Email          string `gorm:"size:100;not null;index" json:"email"`
 	Avatar         string `gorm:"size:500" json:"avatar"`
@@ -22,6 +25,11 @@ type Comment struct {
 	Status         string `gorm:"size:20;default:pending;index" json:"status"`
 	IPAddress      string `gorm:"size:45" json:"ip_address,omitempty"`
 	UserAgent      string `gorm:"size:500" json:"-"`
+	SpamScore      float64 `gorm:"default:0" json:"spam_score"` // 反垃圾检查总分
+	SpamReasons    string  `gorm:"type:text" json:"-"`          // 各项检查结果（JSON）
+	EditTokenHash  string     `gorm:"size:64" json:"-"`               // 作者编辑令牌的 SHA-256
+	EditedAt       *time.Time `json:"edited_at"`                      // 作者最后编辑时间
+	Withdrawn      bool       `gorm:"default:false" json:"withdrawn"` // 作者已删除，保留占位以维持主题结构
 	
 	Parent   *Comment  `gorm:"foreignKey:ParentID" json:"parent,omitempty"`
 	Replies  []Comment `gorm:"foreignKey:ParentID" json:"replies,omitempty"`
@@ -44,6 +52,10 @@ type CreateCommentRequest struct {
 	Avatar        string `json:"avatar" binding:"max=500"`
 	Website       string `json:"website" binding:"max=500"`
 	Content       string `json:"content" binding:"required,max=2000"`
+	Honeypot      string `json:"honeypot"`    // 隐藏字段，正常用户不会填写
+	RenderedAt    int64  `json:"rendered_at"` // 评论表单打开时间（Unix 毫秒）
+	Challenge     string `json:"challenge"`   // 工作量证明题目
+	Nonce         string `json:"nonce"`       // 工作量证明的解
 }
 
 type AdminReplyRequest struct {
@@ -61,6 +73,9 @@ type CommentVO struct {
 	PostID         *uint        `json:"post_id,omitempty"`    // 保留用于向后兼容
 	LifeRecordID   *uint        `json:"life_record_id,omitempty"` // 保留用于向后兼容
 	ParentID       *uint        `json:"parent_id,omitempty"`
+	ReplyToID      *uint        `json:"reply_to_id,omitempty"`
+	RootID         *uint        `json:"root_id,omitempty"`
+	Depth          int          `json:"depth"`
 	ParentNickname string       `json:"parent_nickname,omitempty"`
 	Nickname       string       `json:"nickname"`
 	Email          string       `json:"email,omitempty"`
@@ -71,9 +86,14 @@ type CommentVO struct {
 	IsPinned       bool         `json:"is_pinned"`
 	Status         string       `json:"status"`
 	CreatedAt      time.Time    `json:"created_at"`
+	Edited         bool         `json:"edited"`
+	EditedAt       *time.Time   `json:"edited_at,omitempty"`
+	Withdrawn      bool         `json:"withdrawn,omitempty"`
 	Replies        []CommentVO  `json:"replies,omitempty"`
 	ReplyCount     int          `json:"reply_count,omitempty"`
 	HasMore        bool         `json:"has_more,omitempty"`
+	NextCursor     string       `json:"next_cursor,omitempty"` // 加载更多回复的游标
+	Reactions      map[string]int `json:"reactions,omitempty"` // 各表情的表态数
 }
 
 type CommentListVO struct {
@@ -95,12 +115,18 @@ type CommentListVO struct {
 	CreatedAt     time.Time `json:"created_at"`
 	PostTitle     string    `json:"post_title,omitempty"`
 	LifeTitle     string    `json:"life_title,omitempty"`
+	SpamScore     float64      `json:"spam_score"`
+	SpamReasons   []SpamReason `json:"spam_reasons,omitempty"` // 各项反垃圾检查的结果
+	EditedAt      *time.Time   `json:"edited_at"`
+	Withdrawn     bool         `json:"withdrawn"`
 }
 
 type CreateCommentResponse struct {
-	ID      uint   `json:"id"`
-	Status  string `json:"status"`
-	Message string `json:"message"`
+	ID            uint      `json:"id"`
+	Status        string    `json:"status"`
+	Message       string    `json:"message"`
+	EditToken     string    `json:"edit_token"`     // 作者编辑令牌，仅返回一次
+	EditableUntil time.Time `json:"editable_until"` // 可修改内容的截止时间
 }
 
 func (c *Comment) ToVO() CommentVO {
@@ -111,6 +137,9 @@ func (c *Comment) ToVO() CommentVO {
 		PostID:      c.PostID,
 		LifeRecordID: c.LifeRecordID,
 		ParentID:    c.ParentID,
+		ReplyToID:   c.ReplyToID,
+		RootID:      c.RootID,
+		Depth:       c.Depth,
 		Nickname:    c.Nickname,
 		Avatar:      c.Avatar,
 		Website:     c.Website,
@@ -119,6 +148,9 @@ func (c *Comment) ToVO() CommentVO {
 		IsPinned:    c.IsPinned,
 		Status:      c.Status,
 		CreatedAt:   c.CreatedAt,
+		Edited:      c.EditedAt != nil,
+		EditedAt:    c.EditedAt,
+		Withdrawn:   c.Withdrawn,
 	}
 	
 	return vo
@@ -148,6 +180,10 @@ func (c *Comment) ToListVO() CommentListVO {
 		Status:       c.Status,
 		IPAddress:    c.IPAddress,
 		CreatedAt:    c.CreatedAt,
+		SpamScore:    c.SpamScore,
+		SpamReasons:  c.GetSpamReasons(),
+		EditedAt:     c.EditedAt,
+		Withdrawn:    c.Withdrawn,
 	}
 }
 
@@ -0,0 +1,73 @@
+// Package model 评论作者编辑模型
+package model
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"time"
+
+	"kuaiyu/pkg/utils"
+)
+
+// ===========================================
+// 编辑令牌
+// ===========================================
+
+// NewCommentEditToken 生成评论作者的编辑令牌，返回令牌及其哈希
+// 令牌只在评论提交成功时返回给作者，数据库只保存哈希
+func NewCommentEditToken() (string, string) {
+	token := utils.GenerateRandomString(48)
+	return token, HashCommentEditToken(token)
+}
+
+// HashCommentEditToken 编辑令牌的哈希
+func HashCommentEditToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// CheckEditToken 校验编辑令牌
+func (c *Comment) CheckEditToken(token string) bool {
+	if c.EditTokenHash == "" || token == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(c.EditTokenHash), []byte(HashCommentEditToken(token))) == 1
+}
+
+// EditableUntil 作者可修改内容的截止时间
+func (c *Comment) EditableUntil(window time.Duration) time.Time {
+	return c.CreatedAt.Add(window)
+}
+
+// ===========================================
+// 编辑记录
+// ===========================================
+
+// 编辑操作
+const (
+	CommentEditActionEdit     = "edit"
+	CommentEditActionWithdraw = "withdraw"
+)
+
+// CommentEdit 评论作者的编辑记录，保存修改前的内容供管理员查看
+type CommentEdit struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	CommentID uint      `gorm:"not null;index" json:"comment_id"`
+	Action    string    `gorm:"size:20;not null" json:"action"` // edit | withdraw
+	Content   string    `gorm:"type:text" json:"content"`       // 修改前的内容
+	Status    string    `gorm:"size:20" json:"status"`          // 修改前的状态
+	SpamScore float64   `json:"spam_score"`                     // 修改前的反垃圾分数
+	IPAddress string    `gorm:"size:45" json:"ip_address"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName 表名
+func (CommentEdit) TableName() string {
+	return "comment_edits"
+}
+
+// EditCommentRequest 作者编辑评论请求
+type EditCommentRequest struct {
+	Content string `json:"content" binding:"required,max=2000"`
+}
@@ -0,0 +1,99 @@
+// Package model 评论主题
+package model
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ===========================================
+// 物化路径
+// ===========================================
+
+const (
+	// CommentPathSegment 物化路径中每个评论 ID 的宽度
+	CommentPathSegment = 10
+	// CommentMaxDepth 最大回复层级，受 path 列宽限制（760 / 10 - 1）
+	CommentMaxDepth = 75
+)
+
+// CommentPathOf 评论 ID 在物化路径中的片段
+func CommentPathOf(id uint) string {
+	return fmt.Sprintf("%0*d", CommentPathSegment, id)
+}
+
+// SetThread 根据父评论设置主题、层级和物化路径，parent 为空表示顶层评论
+// 物化路径由顶层评论到自身的定宽 ID 依次拼接而成，按路径排序即为深度优先、同层按时间先后的顺序，
+// 某条评论的全部后代为以其路径为前缀的评论。需要在评论写入、已有 ID 后调用
+func (c *Comment) SetThread(parent *Comment) {
+	if parent == nil {
+		c.ParentID = nil
+		c.RootID = nil
+		c.Depth = 0
+		c.Path = CommentPathOf(c.ID)
+		return
+	}
+
+	rootID := parent.ID
+	if parent.RootID != nil {
+		rootID = *parent.RootID
+	}
+	parentID := parent.ID
+	c.ParentID = &parentID
+	c.RootID = &rootID
+	c.Depth = parent.Depth + 1
+	c.Path = parent.Path + CommentPathOf(c.ID)
+}
+
+// CanReply 是否还能在该评论下回复
+func (c *Comment) CanReply() bool {
+	return c.Depth < CommentMaxDepth
+}
+
+// ===========================================
+// 游标分页
+// ===========================================
+
+// ErrInvalidCursor 游标无效
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// CommentCursor 评论列表的游标，记录上一页最后一条评论
+// 顶层评论使用置顶状态、创建时间和 ID，回复使用物化路径
+type CommentCursor struct {
+	Pinned    bool      `json:"p,omitempty"`
+	CreatedAt time.Time `json:"t,omitempty"`
+	ID        uint      `json:"id,omitempty"`
+	Path      string    `json:"path,omitempty"`
+}
+
+// Encode 编码为不透明的游标字符串
+func (c CommentCursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCommentCursor 解析游标字符串，空字符串返回 nil
+func DecodeCommentCursor(s string) (*CommentCursor, error) {
+	if s == "" {
+		return nil, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var cursor CommentCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return &cursor, nil
+}
+
+// CommentPageVO 按游标分页的评论
+type CommentPageVO struct {
+	Items      []CommentVO `json:"items"`
+	NextCursor string      `json:"next_cursor,omitempty"`
+	HasMore    bool        `json:"has_more"`
+}
@@ -0,0 +1,24 @@
+// Package model 订阅源模型
+package model
+
+// ===========================================
+// 订阅源 DTO
+// ===========================================
+
+// FeedLinkVO 订阅源自动发现信息
+// 前端据此输出 <link rel="alternate" type="..." title="..." href="...">
+type FeedLinkVO struct {
+	Type  string `json:"type"`
+	Title string `json:"title"`
+	URL   string `json:"url"`
+}
+
+// FeedLinks 同一订阅源的 RSS、Atom、JSON Feed 三种格式
+// path 为 RSS 地址（站内路径），其余格式通过 format 参数区分
+func FeedLinks(title, path string) []FeedLinkVO {
+	return []FeedLinkVO{
+		{Type: "application/rss+xml", Title: title + " (RSS)", URL: path},
+		{Type: "application/atom+xml", Title: title + " (Atom)", URL: path + "?format=atom"},
+		{Type: "application/feed+json", Title: title + " (JSON Feed)", URL: path + "?format=json"},
+	}
+}
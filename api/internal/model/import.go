@@ -0,0 +1,57 @@
+// Package model 文章导入模型
+package model
+
+import (
+	"time"
+)
+
+// ===========================================
+// 导入报告
+// ===========================================
+
+// ImportAction 导入动作
+const (
+	ImportActionCreate   = "create"   // 新建文章
+	ImportActionSkip     = "skip"     // 跳过（已导入过、格式错误等）
+	ImportActionConflict = "conflict" // slug 与已有文章或同批文件冲突
+)
+
+// ImportItem 单个文件的导入结果
+type ImportItem struct {
+	File        string     `json:"file"`
+	Action      string     `json:"action"`
+	Reason      string     `json:"reason,omitempty"`
+	Title       string     `json:"title,omitempty"`
+	Slug        string     `json:"slug,omitempty"`
+	Status      string     `json:"status,omitempty"`
+	PublishedAt *time.Time `json:"published_at,omitempty"`
+	Tags        []string   `json:"tags,omitempty"`
+	PostID      uint       `json:"post_id,omitempty"` // 实际导入后的文章 ID
+}
+
+// ImportReport 导入报告
+type ImportReport struct {
+	DryRun     bool         `json:"dry_run"`
+	Total      int          `json:"total"`
+	Created    int          `json:"created"`
+	Skipped    int          `json:"skipped"`
+	Conflicted int          `json:"conflicted"`
+	NewTags    []string     `json:"new_tags"` // 需要新建的标签
+	Items      []ImportItem `json:"items"`
+}
+
+// Count 重新统计各动作数量
+func (r *ImportReport) Count() {
+	r.Total = len(r.Items)
+	r.Created, r.Skipped, r.Conflicted = 0, 0, 0
+	for _, item := range r.Items {
+		switch item.Action {
+		case ImportActionCreate:
+			r.Created++
+		case ImportActionSkip:
+			r.Skipped++
+		case ImportActionConflict:
+			r.Conflicted++
+		}
+	}
+}
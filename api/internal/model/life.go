@@ -15,7 +15,7 @@ type LifeRecord struct {
 	Title       string     `gorm:"size:200" json:"title"`
 	Content     string     `gorm:"type:text" json:"content"`
 	CoverImage  string     `gorm:"size:500" json:"cover_image"`
-	Status      string     `gorm:"size:20;default:draft" json:"status"` // draft | published
+	Status      string     `gorm:"size:20;default:draft" json:"status"` // draft | published | scheduled
 	ViewCount   int        `gorm:"default:0" json:"view_count"`         // 阅读量
 	AuthorID    uint       `gorm:"index" json:"author_id"`
 	PublishedAt *time.Time `json:"published_at"`
@@ -38,14 +38,16 @@ func (LifeRecord) TableName() string {
 type CreateLifeRequest struct {
 	Content    string `json:"content" binding:"required"`
 	CoverImage string `json:"cover_image"`
-	Status     string `json:"status" binding:"oneof=draft published"`
+	Status     string `json:"status" binding:"oneof=draft published scheduled"`
+	PublishAt  *time.Time `json:"publish_at"` // 定时发布时间（status=scheduled 时必填）
 }
 
 // UpdateLifeRequest 更新生活记录请求
 type UpdateLifeRequest struct {
 	Content    string `json:"content"`
 	CoverImage string `json:"cover_image"`
-	Status     string `json:"status" binding:"omitempty,oneof=draft published"`
+	Status     string `json:"status" binding:"omitempty,oneof=draft published scheduled"`
+	PublishAt  *time.Time `json:"publish_at"` // 定时发布时间
 }
 
 // LifeRecordVO 生活记录视图对象
@@ -61,6 +63,8 @@ type LifeRecordVO struct {
 	UpdatedAt   time.Time  `json:"updated_at"`
 	Author      *UserVO    `json:"author,omitempty"`
 	IsExpanded  bool       `json:"is_expanded"` // 是否展开全文
+	Reactions   map[string]int `json:"reactions,omitempty"` // 各表情的表态数
+	*RenderedVO // 渲染结果（html、toc、word_count、reading_minutes），仅详情接口返回
 }
 
 // LifeRecordListVO 生活记录列表视图对象
@@ -73,6 +77,7 @@ type LifeRecordListVO struct {
 	PublishedAt *time.Time `json:"published_at"`
 	CreatedAt   time.Time  `json:"created_at"`
 	HasMore     bool       `json:"has_more"` // 是否有更多内容
+	Reactions   map[string]int `json:"reactions,omitempty"` // 各表情的表态数
 }
 
 // ===========================================
@@ -0,0 +1,64 @@
+// Package model 邮件通知模型
+package model
+
+import (
+	"time"
+)
+
+// ===========================================
+// 邮件发件箱
+// ===========================================
+
+// 通知类型
+const (
+	NotificationKindReply   = "reply"   // 评论被回复，通知原评论者
+	NotificationKindComment = "comment" // 新评论或待审核评论，通知站长
+)
+
+// 发件状态
+const (
+	EmailStatusPending = "pending" // 等待发送或等待重试
+	EmailStatusSent    = "sent"
+	EmailStatusFailed  = "failed" // 重试次数用尽
+)
+
+// EmailOutbox 待发送的邮件
+// 通知在业务操作时渲染好写入发件箱，由后台队列发送，失败后按退避时间重试
+type EmailOutbox struct {
+	ID             uint       `gorm:"primaryKey" json:"id"`
+	Kind           string     `gorm:"size:20;not null;index" json:"kind"`
+	CommentID      uint       `gorm:"index" json:"comment_id"` // 触发通知的评论
+	Recipient      string     `gorm:"size:100;not null;index" json:"recipient"`
+	Subject        string     `gorm:"size:255;not null" json:"subject"`
+	HTML           string     `gorm:"type:text" json:"-"`
+	Text           string     `gorm:"type:text" json:"-"`
+	UnsubscribeURL string     `gorm:"size:500" json:"-"`
+	Status         string     `gorm:"size:20;not null;default:pending;index:idx_email_outbox_due,priority:1" json:"status"`
+	Attempts       int        `gorm:"default:0" json:"attempts"`
+	NextAttemptAt  *time.Time `gorm:"index:idx_email_outbox_due,priority:2" json:"next_attempt_at"`
+	Error          string     `gorm:"size:500" json:"error,omitempty"`
+	SentAt         *time.Time `json:"sent_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// TableName 表名
+func (EmailOutbox) TableName() string {
+	return "email_outbox"
+}
+
+// ===========================================
+// 退订
+// ===========================================
+
+// EmailUnsubscribe 已退订通知的邮箱
+type EmailUnsubscribe struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Email     string    `gorm:"size:100;not null;uniqueIndex" json:"email"` // 小写
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName 表名
+func (EmailUnsubscribe) TableName() string {
+	return "email_unsubscribes"
+}
@@ -0,0 +1,26 @@
+// Package model 分享图模型
+package model
+
+import (
+	"time"
+)
+
+// ===========================================
+// 分享图模型
+// ===========================================
+
+// OGImage 文章自动生成的 OpenGraph 分享图
+// Hash 由标题、标签、站点名和日期计算，内容变化后重新生成并上传到 COS
+type OGImage struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	PostID    uint      `gorm:"uniqueIndex;not null" json:"post_id"`
+	Hash      string    `gorm:"size:64;not null" json:"hash"`
+	URL       string    `gorm:"size:500" json:"url"` // COS 地址
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName 表名
+func (OGImage) TableName() string {
+	return "og_images"
+}
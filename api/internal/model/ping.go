@@ -0,0 +1,97 @@
+// Package model 搜索引擎推送模型
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// ===========================================
+// 推送记录
+// ===========================================
+
+// 推送方式
+const (
+	PingKindIndexNow = "indexnow" // IndexNow 协议批量提交 URL
+	PingKindSitemap  = "sitemap"  // 请求 sitemap ping 地址
+)
+
+// 推送状态
+const (
+	PingStatusPending = "pending" // 等待发送或等待重试
+	PingStatusSuccess = "success"
+	PingStatusFailed  = "failed" // 重试次数用尽或不可重试的错误
+)
+
+// 推送原因
+const (
+	PingReasonPublish = "publish"
+	PingReasonUpdate  = "update"
+	PingReasonDelete  = "delete"
+	PingReasonManual  = "manual" // 管理员手动提交
+)
+
+// PingLog 搜索引擎推送记录
+// 每条记录是一次待发送的推送任务，后台队列按 next_attempt_at 取出发送，失败后按退避时间重试
+type PingLog struct {
+	ID            uint       `gorm:"primaryKey" json:"id"`
+	Kind          string     `gorm:"size:20;not null;index" json:"kind"`
+	Endpoint      string     `gorm:"size:500;not null" json:"endpoint"`
+	Reason        string     `gorm:"size:20" json:"reason"`
+	URLs          string     `gorm:"type:text" json:"-"` // 提交的地址，换行分隔
+	URLCount      int        `gorm:"default:0" json:"url_count"`
+	Status        string     `gorm:"size:20;not null;default:pending;index:idx_ping_logs_due,priority:1" json:"status"`
+	Attempts      int        `gorm:"default:0" json:"attempts"`
+	NextAttemptAt *time.Time `gorm:"index:idx_ping_logs_due,priority:2" json:"next_attempt_at"`
+	StatusCode    int        `gorm:"default:0" json:"status_code"` // 最后一次请求的 HTTP 状态码，请求失败时为 0
+	Error         string     `gorm:"size:500" json:"error,omitempty"`
+	SentAt        *time.Time `json:"sent_at"` // 最后一次发送时间
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// TableName 表名
+func (PingLog) TableName() string {
+	return "ping_logs"
+}
+
+// URLList 提交的地址列表
+func (p *PingLog) URLList() []string {
+	if p.URLs == "" {
+		return nil
+	}
+	return strings.Split(p.URLs, "\n")
+}
+
+// SetURLs 设置提交的地址
+func (p *PingLog) SetURLs(urls []string) {
+	p.URLs = strings.Join(urls, "\n")
+	p.URLCount = len(urls)
+}
+
+// ===========================================
+// 推送记录 DTO
+// ===========================================
+
+// SubmitPingRequest 手动提交地址请求
+type SubmitPingRequest struct {
+	URLs []string `json:"urls" binding:"required,min=1,max=100,dive,required,max=500"` // 站内绝对地址或以 / 开头的路径
+}
+
+// ===========================================
+// 推送记录 VO
+// ===========================================
+
+// PingLogVO 推送记录视图对象
+type PingLogVO struct {
+	PingLog
+	URLs []string `json:"urls"`
+}
+
+// ToVO 转换为 VO
+func (p *PingLog) ToVO() PingLogVO {
+	return PingLogVO{
+		PingLog: *p,
+		URLs:    p.URLList(),
+	}
+}
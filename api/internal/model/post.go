@@ -2,6 +2,8 @@
 package model
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"time"
 )
 
@@ -17,10 +19,15 @@ type Post struct {
 	Content     string     `gorm:"type:text" json:"content"`
 	Excerpt     string     `gorm:"type:text" json:"excerpt"`
 	CoverImage  string     `gorm:"size:500" json:"cover_image"`
-	Status      string     `gorm:"size:20;default:draft" json:"status"` // draft | published
+	Status      string     `gorm:"size:20;default:draft" json:"status"` // draft | published | scheduled
 	ViewCount   int        `gorm:"default:0" json:"view_count"`
 	AuthorID    uint       `gorm:"index" json:"author_id"`
 	PublishedAt *time.Time `json:"published_at"`
+	Visibility   string     `gorm:"size:20;default:public;index" json:"visibility"` // public | unlisted | protected
+	PasswordHash string     `gorm:"size:255" json:"-"`                              // 加密文章的访问密码（bcrypt）
+	PinOrder     int        `gorm:"default:0;index" json:"pin_order"`               // 置顶顺序，0 表示未置顶，越小越靠前
+	PinnedUntil  *time.Time `json:"pinned_until"`                                   // 置顶到期时间，为空表示长期置顶
+	FeatureOrder int        `gorm:"default:0;index" json:"feature_order"`           // 精选顺序，0 表示未精选
 	
 	// 关联
 	Author   User   `gorm:"foreignKey:AuthorID" json:"author,omitempty"`
@@ -44,8 +51,11 @@ type CreatePostRequest struct {
 	Content    string   `json:"content" binding:"required"`
 	Excerpt    string   `json:"excerpt"`
 	CoverImage string   `json:"cover_image"`
-	Status     string   `json:"status" binding:"oneof=draft published"`
+	Status     string   `json:"status" binding:"oneof=draft published scheduled"`
+	PublishAt  *time.Time `json:"publish_at"` // 定时发布时间（status=scheduled 时必填）
 	TagIDs     []uint   `json:"tag_ids"`
+	Visibility string   `json:"visibility" binding:"omitempty,oneof=public unlisted protected"`
+	Password   string   `json:"password" binding:"max=100"` // visibility=protected 时必填
 }
 
 // UpdatePostRequest 更新文章请求
@@ -55,10 +65,24 @@ type UpdatePostRequest struct {
 	Content    string   `json:"content"`
 	Excerpt    string   `json:"excerpt"`
 	CoverImage string   `json:"cover_image"`
-	Status     string   `json:"status" binding:"omitempty,oneof=draft published"`
+	Status     string   `json:"status" binding:"omitempty,oneof=draft published scheduled"`
+	PublishAt  *time.Time `json:"publish_at"` // 定时发布时间
+	Visibility string   `json:"visibility" binding:"omitempty,oneof=public unlisted protected"`
+	Password   string   `json:"password" binding:"max=100"` // 留空则保留原密码
 	TagIDs     []uint   `json:"tag_ids"`
 }
 
+// PinPostRequest 置顶文章请求
+type PinPostRequest struct {
+	Order int        `json:"order" binding:"min=0"` // 置顶顺序，0 表示排在已置顶文章之后
+	Until *time.Time `json:"until"`                 // 到期时间，为空表示长期置顶
+}
+
+// SetFeaturedRequest 设置精选文章请求（整体替换，按数组顺序排列）
+type SetFeaturedRequest struct {
+	PostIDs []uint `json:"post_ids" binding:"max=20"`
+}
+
 // PostVO 文章视图对象
 type PostVO struct {
 	ID          uint      `json:"id"`
@@ -67,6 +91,7 @@ type PostVO struct {
 	Content     string    `json:"content,omitempty"`
 	Excerpt     string    `json:"excerpt"`
 	CoverImage  string    `json:"cover_image"`
+	OGImage     string    `json:"og_image,omitempty"` // 分享图地址（封面或自动生成的卡片），仅详情接口返回
 	Status      string    `json:"status"`
 	ViewCount   int       `json:"view_count"`
 	AuthorID    uint      `json:"author_id"`
@@ -75,6 +100,17 @@ type PostVO struct {
 	UpdatedAt   time.Time `json:"updated_at"`
 	Author      *UserVO   `json:"author,omitempty"`
 	Tags        []TagVO   `json:"tags,omitempty"`
+	Series      *PostSeriesVO `json:"series,omitempty"` // 所属系列及前后篇
+	Visibility  string    `json:"visibility"`
+	Locked      bool      `json:"locked,omitempty"` // 加密文章尚未解锁，不含正文
+	Pinned      bool      `json:"pinned"` // 当前是否置顶（已过期的置顶为 false）
+	PinOrder    int       `json:"pin_order"`
+	PinnedUntil *time.Time `json:"pinned_until"`
+	FeatureOrder int      `json:"feature_order"`
+	Locale      string    `json:"locale"`              // 当前返回内容的语言
+	Languages   []PostLanguageVO `json:"languages,omitempty"` // 全部可用语言（含原文）
+	Reactions   map[string]int `json:"reactions,omitempty"` // 各表情的表态数
+	*RenderedVO // 渲染结果（html、toc、word_count、reading_minutes），仅详情接口返回
 }
 
 // PostListVO 文章列表视图对象（不含内容）
@@ -89,6 +125,11 @@ type PostListVO struct {
 	PublishedAt *time.Time `json:"published_at"`
 	CreatedAt   time.Time `json:"created_at"`
 	Tags        []TagVO   `json:"tags,omitempty"`
+	Visibility  string    `json:"visibility"`
+	Pinned      bool      `json:"pinned"`
+	Locale      string    `json:"locale"`
+	Languages   []string  `json:"languages,omitempty"` // 全部可用语言（含原文）
+	Reactions   map[string]int `json:"reactions,omitempty"` // 各表情的表态数
 }
 
 // ===========================================
@@ -110,6 +151,11 @@ func (p *Post) ToVO() PostVO {
 		PublishedAt: p.PublishedAt,
 		CreatedAt:   p.CreatedAt,
 		UpdatedAt:   p.UpdatedAt,
+		Visibility:  p.GetVisibility(),
+		Pinned:      p.IsPinned(time.Now()),
+		PinOrder:    p.PinOrder,
+		PinnedUntil: p.PinnedUntil,
+		FeatureOrder: p.FeatureOrder,
 	}
 	
 	// 转换作者
@@ -141,6 +187,8 @@ func (p *Post) ToListVO() PostListVO {
 		ViewCount:   p.ViewCount,
 		PublishedAt: p.PublishedAt,
 		CreatedAt:   p.CreatedAt,
+		Visibility:  p.GetVisibility(),
+		Pinned:      p.IsPinned(time.Now()),
 	}
 	
 	// 转换标签
@@ -154,3 +202,42 @@ func (p *Post) ToListVO() PostListVO {
 	return vo
 }
 
+
+// ===========================================
+// 可见性
+// ===========================================
+
+// UnlockPostRequest 解锁加密文章请求
+type UnlockPostRequest struct {
+	Password string `json:"password" binding:"required,max=100"`
+}
+
+// UnlockPostVO 解锁结果，后续请求通过 X-Unlock-Token 头携带令牌
+type UnlockPostVO struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// GetVisibility 获取可见性（迁移前的数据视为公开）
+func (p *Post) GetVisibility() string {
+	if p.Visibility == "" {
+		return "public"
+	}
+	return p.Visibility
+}
+
+// IsPinned 在 now 时刻是否处于置顶状态
+func (p *Post) IsPinned(now time.Time) bool {
+	return p.PinOrder > 0 && (p.PinnedUntil == nil || p.PinnedUntil.After(now))
+}
+
+// IsProtected 是否为加密文章
+func (p *Post) IsProtected() bool {
+	return p.Visibility == "protected"
+}
+
+// PasswordFingerprint 访问密码的指纹，写入解锁令牌，修改密码后旧令牌随之失效
+func (p *Post) PasswordFingerprint() string {
+	sum := sha256.Sum256([]byte(p.PasswordHash))
+	return hex.EncodeToString(sum[:8])
+}
@@ -0,0 +1,88 @@
+// Package model 草稿预览链接模型
+package model
+
+import (
+	"time"
+)
+
+// ===========================================
+// 预览链接模型
+// ===========================================
+
+// PreviewLink 草稿预览链接
+type PreviewLink struct {
+	ID           uint       `gorm:"primaryKey" json:"id"`
+	LinkID       string     `gorm:"size:32;uniqueIndex;not null" json:"link_id"`                                   // 令牌中的唯一标识（jti）
+	TargetType   string     `gorm:"size:20;not null;index:idx_preview_links_target,priority:1" json:"target_type"` // post | life
+	TargetID     uint       `gorm:"not null;index:idx_preview_links_target,priority:2" json:"target_id"`
+	Note         string     `gorm:"size:100" json:"note"` // 备注，如分享对象
+	ExpiresAt    time.Time  `gorm:"not null" json:"expires_at"`
+	RevokedAt    *time.Time `json:"revoked_at"`
+	Views        int        `gorm:"default:0" json:"views"` // 打开次数
+	LastViewedAt *time.Time `json:"last_viewed_at"`
+	CreatedBy    uint       `json:"created_by"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// TableName 表名
+func (PreviewLink) TableName() string {
+	return "preview_links"
+}
+
+// IsActive 预览链接是否仍然有效
+func (p *PreviewLink) IsActive() bool {
+	return p.RevokedAt == nil && time.Now().Before(p.ExpiresAt)
+}
+
+// ===========================================
+// 预览链接 DTO
+// ===========================================
+
+// CreatePreviewRequest 创建预览链接请求
+type CreatePreviewRequest struct {
+	ExpiresIn int    `json:"expires_in" binding:"omitempty,min=1,max=720"` // 有效期（小时），默认 72
+	Note      string `json:"note" binding:"max=100"`
+}
+
+// PreviewLinkVO 预览链接视图对象
+type PreviewLinkVO struct {
+	ID           uint       `json:"id"`
+	TargetType   string     `json:"target_type"`
+	TargetID     uint       `json:"target_id"`
+	Note         string     `json:"note"`
+	Token        string     `json:"token,omitempty"` // 仅在创建时返回
+	ExpiresAt    time.Time  `json:"expires_at"`
+	RevokedAt    *time.Time `json:"revoked_at"`
+	Active       bool       `json:"active"`
+	Views        int        `json:"views"`
+	LastViewedAt *time.Time `json:"last_viewed_at"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// PreviewVO 草稿预览内容
+type PreviewVO struct {
+	Type      string        `json:"type"` // post | life
+	Post      *PostVO       `json:"post,omitempty"`
+	Life      *LifeRecordVO `json:"life,omitempty"`
+	ExpiresAt time.Time     `json:"expires_at"`
+}
+
+// ===========================================
+// 转换方法
+// ===========================================
+
+// ToVO 转换为视图对象
+func (p *PreviewLink) ToVO() PreviewLinkVO {
+	return PreviewLinkVO{
+		ID:           p.ID,
+		TargetType:   p.TargetType,
+		TargetID:     p.TargetID,
+		Note:         p.Note,
+		ExpiresAt:    p.ExpiresAt,
+		RevokedAt:    p.RevokedAt,
+		Active:       p.IsActive(),
+		Views:        p.Views,
+		LastViewedAt: p.LastViewedAt,
+		CreatedAt:    p.CreatedAt,
+	}
+}
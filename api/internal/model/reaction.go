@@ -0,0 +1,82 @@
+// Package model 读者表态模型
+package model
+
+import (
+	"time"
+)
+
+// ===========================================
+// 读者表态模型
+// ===========================================
+
+// 表态对象类型，与评论的 comment_type 取值一致
+const (
+	ReactionTargetPost    = "post"
+	ReactionTargetLife    = "life"
+	ReactionTargetComment = "comment"
+)
+
+// ReactionEmojiCollation 表情列的排序规则
+// utf8mb4_unicode_ci 等排序规则下所有补充平面字符（包括绝大多数表情）互相等价，
+// 表情列必须按字节比较，否则不同表情会被当作同一个表态
+const ReactionEmojiCollation = "utf8mb4_bin"
+
+// Reaction 读者对文章、生活记录或评论的一次表态
+// 同一读者（匿名指纹）对同一对象的同一表情只记录一次，再次提交即取消
+type Reaction struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	TargetType  string    `gorm:"size:20;not null;uniqueIndex:idx_reactions_unique,priority:1;index:idx_reactions_target,priority:1" json:"target_type"` // post | life | comment
+	TargetID    uint      `gorm:"not null;uniqueIndex:idx_reactions_unique,priority:2;index:idx_reactions_target,priority:2" json:"target_id"`
+	Emoji       string    `gorm:"type:varchar(32) COLLATE utf8mb4_bin;not null;uniqueIndex:idx_reactions_unique,priority:3" json:"emoji"`
+	Fingerprint string    `gorm:"size:64;not null;uniqueIndex:idx_reactions_unique,priority:4" json:"-"` // 客户端 ID 或 IP+UA 的哈希
+	CreatedAt   time.Time `gorm:"index" json:"created_at"`
+}
+
+// TableName 表名
+func (Reaction) TableName() string {
+	return "reactions"
+}
+
+// ===========================================
+// 读者表态 DTO
+// ===========================================
+
+// ToggleReactionRequest 切换表态请求
+type ToggleReactionRequest struct {
+	TargetType string `json:"target_type" binding:"required,oneof=post life comment"`
+	TargetID   uint   `json:"target_id" binding:"required"`
+	Emoji      string `json:"emoji" binding:"required,max=32"`
+	ClientID   string `json:"client_id" binding:"max=64"` // 客户端生成的匿名 ID，为空时按 IP+UA 识别
+}
+
+// ReactionSummaryVO 对象的表态汇总
+type ReactionSummaryVO struct {
+	TargetType string         `json:"target_type"`
+	TargetID   uint           `json:"target_id"`
+	Emojis     []string       `json:"emojis"` // 可用的表情
+	Counts     map[string]int `json:"counts"` // 各表情的数量
+	Mine       []string       `json:"mine"`   // 当前读者已表态的表情
+	Active     bool           `json:"active"` // 本次切换后当前表情是否处于表态状态（仅切换接口返回）
+}
+
+// ReactionDayVO 单日表态统计
+type ReactionDayVO struct {
+	Date   string         `json:"date"`
+	Total  int            `json:"total"`
+	Counts map[string]int `json:"counts"`
+}
+
+// ReactionTargetVO 表态最多的对象
+type ReactionTargetVO struct {
+	TargetType string `json:"target_type"`
+	TargetID   uint   `json:"target_id"`
+	Title      string `json:"title"`
+	Count      int    `json:"count"`
+}
+
+// ReactionTrendVO 表态趋势
+type ReactionTrendVO struct {
+	Days       []ReactionDayVO    `json:"days"`
+	Totals     map[string]int     `json:"totals"`
+	TopTargets []ReactionTargetVO `json:"top_targets"`
+}
@@ -0,0 +1,72 @@
+// Package model 重定向模型
+package model
+
+import (
+	"time"
+)
+
+// ===========================================
+// 重定向模型
+// ===========================================
+
+// 重定向类型
+const (
+	RedirectTypePost        = "post"        // 文章旧 slug
+	RedirectTypeTranslation = "translation" // 文章翻译旧 slug，与文章共用 /blog/ 地址
+	RedirectTypeTag         = "tag"         // 标签旧 slug
+	RedirectTypeCustom      = "custom"      // 自定义路径，如旧站的 /p/123
+)
+
+// SlugRedirect 旧地址到新地址的重定向
+// 文章、翻译和标签记录目标 ID，slug 多次变更后旧地址都指向当前 slug；自定义重定向直接记录目标地址
+type SlugRedirect struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	Type      string     `gorm:"size:20;not null;uniqueIndex:idx_slug_redirects_source,priority:1" json:"type"`
+	Source    string     `gorm:"size:255;not null;uniqueIndex:idx_slug_redirects_source,priority:2" json:"source"` // 旧 slug 或自定义路径
+	TargetID  uint       `gorm:"index" json:"target_id,omitempty"`                                                 // 文章、翻译或标签 ID
+	Target    string     `gorm:"size:500" json:"target,omitempty"`                                                 // 自定义重定向的目标地址
+	Permanent bool       `gorm:"default:true" json:"permanent"`                                                    // 301 或 302
+	Hits      int        `gorm:"default:0" json:"hits"`
+	LastHitAt *time.Time `json:"last_hit_at"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// TableName 表名
+func (SlugRedirect) TableName() string {
+	return "slug_redirects"
+}
+
+// ===========================================
+// 重定向 DTO
+// ===========================================
+
+// CreateRedirectRequest 创建自定义重定向请求
+type CreateRedirectRequest struct {
+	Source    string `json:"source" binding:"required,max=255"`
+	Target    string `json:"target" binding:"required,max=500"`
+	Permanent *bool  `json:"permanent"` // 默认 true
+}
+
+// UpdateRedirectRequest 更新自定义重定向请求
+type UpdateRedirectRequest struct {
+	Source    string `json:"source" binding:"max=255"`
+	Target    string `json:"target" binding:"max=500"`
+	Permanent *bool  `json:"permanent"`
+}
+
+// RedirectVO 重定向指示，前端据此跳转到新地址
+type RedirectVO struct {
+	Type      string `json:"type"`
+	Slug      string `json:"slug,omitempty"` // 文章、翻译或标签的当前 slug
+	Location  string `json:"location"`       // 前端页面地址
+	Permanent bool   `json:"permanent"`
+}
+
+// StatusCode 重定向对应的 HTTP 状态码
+func (r *RedirectVO) StatusCode() int {
+	if r.Permanent {
+		return 301
+	}
+	return 302
+}
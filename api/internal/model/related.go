@@ -0,0 +1,72 @@
+// Package model 相关文章推荐模型
+package model
+
+import (
+	"time"
+)
+
+// ===========================================
+// 相关文章模型
+// ===========================================
+
+// RelatedPost 预计算的相关文章得分
+type RelatedPost struct {
+	PostID       uint      `gorm:"primaryKey" json:"post_id"`
+	RelatedID    uint      `gorm:"primaryKey" json:"related_id"`
+	Score        float64   `gorm:"index" json:"score"` // 综合得分
+	TagScore     float64   `json:"tag_score"`          // 标签重合度（Jaccard）
+	TextScore    float64   `json:"text_score"`         // 正文 TF-IDF 余弦相似度
+	RecencyScore float64   `json:"recency_score"`      // 候选文章的新鲜度
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// TableName 表名
+func (RelatedPost) TableName() string {
+	return "related_posts"
+}
+
+// RelatedOverride 相关文章的人工干预（置顶或排除）
+type RelatedOverride struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	PostID    uint      `gorm:"not null;uniqueIndex:idx_related_overrides_pair,priority:1" json:"post_id"`
+	RelatedID uint      `gorm:"not null;uniqueIndex:idx_related_overrides_pair,priority:2" json:"related_id"`
+	Action    string    `gorm:"size:20;not null" json:"action"` // pin | exclude
+	Position  int       `gorm:"default:0" json:"position"`      // 置顶顺序，越小越靠前
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName 表名
+func (RelatedOverride) TableName() string {
+	return "related_overrides"
+}
+
+// ===========================================
+// 相关文章 DTO
+// ===========================================
+
+// SetRelatedOverrideRequest 设置相关文章干预请求
+type SetRelatedOverrideRequest struct {
+	Action   string `json:"action" binding:"required,oneof=pin exclude"`
+	Position int    `json:"position"`
+}
+
+// RelatedPostVO 相关文章视图对象
+type RelatedPostVO struct {
+	PostListVO
+	Score  float64 `json:"score"`
+	Pinned bool    `json:"pinned,omitempty"`
+}
+
+// AdminRelatedPostVO 管理后台相关文章视图对象（含得分明细和干预状态）
+type AdminRelatedPostVO struct {
+	ID           uint    `json:"id"`
+	Title        string  `json:"title"`
+	Slug         string  `json:"slug"`
+	Status       string  `json:"status"`
+	Score        float64 `json:"score"`
+	TagScore     float64 `json:"tag_score"`
+	TextScore    float64 `json:"text_score"`
+	RecencyScore float64 `json:"recency_score"`
+	Action       string  `json:"action,omitempty"` // pin | exclude
+	Position     int     `json:"position,omitempty"`
+}
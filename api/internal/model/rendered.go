@@ -0,0 +1,79 @@
+// Package model Markdown 渲染缓存模型
+package model
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"kuaiyu/pkg/markdown"
+)
+
+// ===========================================
+// 渲染缓存模型
+// ===========================================
+
+// RenderedContent Markdown 渲染结果缓存
+// 以渲染器版本和正文的哈希为键，同一修订版本的正文只渲染一次
+type RenderedContent struct {
+	ID             uint      `gorm:"primaryKey" json:"id"`
+	Hash           string    `gorm:"size:64;uniqueIndex;not null" json:"hash"`
+	HTML           string    `gorm:"type:mediumtext" json:"html"`
+	TOC            string    `gorm:"type:text" json:"toc"`           // 目录 JSON
+	CodeLanguages  string    `gorm:"size:500" json:"code_languages"` // 代码块语言，逗号分隔
+	WordCount      int       `json:"word_count"`
+	ReadingMinutes int       `json:"reading_minutes"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// TableName 表名
+func (RenderedContent) TableName() string {
+	return "rendered_contents"
+}
+
+// ===========================================
+// 渲染结果 DTO
+// ===========================================
+
+// RenderedVO 渲染结果视图对象（嵌入到内容视图对象中）
+type RenderedVO struct {
+	HTML           string              `json:"html"`
+	TOC            []*markdown.TOCItem `json:"toc"`
+	CodeLanguages  []string            `json:"code_languages,omitempty"`
+	WordCount      int                 `json:"word_count"`
+	ReadingMinutes int                 `json:"reading_minutes"`
+}
+
+// ===========================================
+// 转换方法
+// ===========================================
+
+// NewRenderedContent 根据渲染结果创建缓存记录
+func NewRenderedContent(hash string, r *markdown.Result) RenderedContent {
+	toc, _ := json.Marshal(r.TOC)
+	return RenderedContent{
+		Hash:           hash,
+		HTML:           r.HTML,
+		TOC:            string(toc),
+		CodeLanguages:  strings.Join(r.Languages(), ","),
+		WordCount:      r.WordCount,
+		ReadingMinutes: r.ReadingMinutes,
+	}
+}
+
+// ToVO 转换为视图对象
+func (r *RenderedContent) ToVO() *RenderedVO {
+	vo := &RenderedVO{
+		HTML:           r.HTML,
+		TOC:            []*markdown.TOCItem{},
+		WordCount:      r.WordCount,
+		ReadingMinutes: r.ReadingMinutes,
+	}
+	if r.TOC != "" && r.TOC != "null" {
+		json.Unmarshal([]byte(r.TOC), &vo.TOC)
+	}
+	if r.CodeLanguages != "" {
+		vo.CodeLanguages = strings.Split(r.CodeLanguages, ",")
+	}
+	return vo
+}
@@ -0,0 +1,124 @@
+// Package model 内容修订历史模型
+package model
+
+import (
+	"time"
+)
+
+// ===========================================
+// 修订历史模型
+// ===========================================
+
+// PostRevision 内容修订快照（文章和生活记录共用）
+type PostRevision struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	TargetType string    `gorm:"size:20;not null;default:post;uniqueIndex:idx_revisions_target_version,priority:1" json:"target_type"` // post | life
+	TargetID   uint      `gorm:"not null;uniqueIndex:idx_revisions_target_version,priority:2" json:"target_id"`
+	Version    int       `gorm:"not null;uniqueIndex:idx_revisions_target_version,priority:3" json:"version"` // 同一内容内递增的版本号
+	Title      string    `gorm:"size:200" json:"title"`
+	Slug       string    `gorm:"size:200" json:"slug"`
+	Excerpt    string    `gorm:"type:text" json:"excerpt"`
+	Content    string    `gorm:"type:text" json:"content"`
+	CoverImage string    `gorm:"size:500" json:"cover_image"`
+	Status     string    `gorm:"size:20" json:"status"`
+	Reason     string    `gorm:"size:20" json:"reason"` // create | update | restore
+	EditorID   uint      `json:"editor_id"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// TableName 表名
+func (PostRevision) TableName() string {
+	return "post_revisions"
+}
+
+// ===========================================
+// 修订历史 DTO
+// ===========================================
+
+// PostRevisionVO 修订视图对象
+type PostRevisionVO struct {
+	ID          uint      `json:"id"`
+	TargetType  string    `json:"target_type"`
+	TargetID    uint      `json:"target_id"`
+	Version     int       `json:"version"`
+	Title       string    `json:"title"`
+	Slug        string    `json:"slug,omitempty"`
+	Excerpt     string    `json:"excerpt,omitempty"`
+	Content     string    `json:"content,omitempty"`
+	CoverImage  string    `json:"cover_image,omitempty"`
+	Status      string    `json:"status"`
+	Reason      string    `json:"reason"`
+	EditorID    uint      `json:"editor_id"`
+	CreatedAt   time.Time `json:"created_at"`
+	*RenderedVO           // 修订正文的渲染结果，仅详情接口返回
+}
+
+// ===========================================
+// 转换方法
+// ===========================================
+
+// NewPostRevision 根据文章创建修订快照
+func NewPostRevision(p *Post, reason string, editorID uint) PostRevision {
+	return PostRevision{
+		TargetType: "post",
+		TargetID:   p.ID,
+		Title:      p.Title,
+		Slug:       p.Slug,
+		Excerpt:    p.Excerpt,
+		Content:    p.Content,
+		CoverImage: p.CoverImage,
+		Status:     p.Status,
+		Reason:     reason,
+		EditorID:   editorID,
+	}
+}
+
+// NewLifeRevision 根据生活记录创建修订快照
+func NewLifeRevision(l *LifeRecord, reason string, editorID uint) PostRevision {
+	return PostRevision{
+		TargetType: "life",
+		TargetID:   l.ID,
+		Title:      l.Title,
+		Content:    l.Content,
+		CoverImage: l.CoverImage,
+		Status:     l.Status,
+		Reason:     reason,
+		EditorID:   editorID,
+	}
+}
+
+// SameContent 判断两个快照的正文字段是否一致（状态变化不算内容变化）
+func (r *PostRevision) SameContent(other *PostRevision) bool {
+	return r.Title == other.Title &&
+		r.Slug == other.Slug &&
+		r.Excerpt == other.Excerpt &&
+		r.Content == other.Content &&
+		r.CoverImage == other.CoverImage
+}
+
+// ToVO 转换为视图对象
+func (r *PostRevision) ToVO() PostRevisionVO {
+	return PostRevisionVO{
+		ID:         r.ID,
+		TargetType: r.TargetType,
+		TargetID:   r.TargetID,
+		Version:    r.Version,
+		Title:      r.Title,
+		Slug:       r.Slug,
+		Excerpt:    r.Excerpt,
+		Content:    r.Content,
+		CoverImage: r.CoverImage,
+		Status:     r.Status,
+		Reason:     r.Reason,
+		EditorID:   r.EditorID,
+		CreatedAt:  r.CreatedAt,
+	}
+}
+
+// ToListVO 转换为列表视图对象（不含正文）
+func (r *PostRevision) ToListVO() PostRevisionVO {
+	vo := r.ToVO()
+	vo.Content = ""
+	vo.Excerpt = ""
+	return vo
+}
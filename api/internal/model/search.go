@@ -0,0 +1,63 @@
+// Package model 全文检索模型
+package model
+
+import (
+	"time"
+)
+
+// ===========================================
+// 检索索引模型
+// ===========================================
+
+// SearchDocument 检索文档（每个公开的文章/生活记录/评论一条）
+type SearchDocument struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	DocType     string     `gorm:"size:20;not null;uniqueIndex:idx_search_documents_doc,priority:1" json:"doc_type"` // post | life | comment
+	DocID       uint       `gorm:"not null;uniqueIndex:idx_search_documents_doc,priority:2" json:"doc_id"`
+	Title       string     `gorm:"size:200" json:"title"`
+	Body        string     `gorm:"type:mediumtext" json:"body"` // 去除 Markdown 后的纯文本
+	Slug        string     `gorm:"size:200" json:"slug"`
+	TargetType  string     `gorm:"size:20" json:"target_type"` // 评论所属内容类型
+	TargetID    *uint      `json:"target_id"`                  // 评论所属内容 ID
+	Length      int        `json:"length"`                     // 加权后的文档长度（词项数）
+	PublishedAt *time.Time `json:"published_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// TableName 表名
+func (SearchDocument) TableName() string {
+	return "search_documents"
+}
+
+// SearchTerm 倒排索引词项
+type SearchTerm struct {
+	ID      uint   `gorm:"primaryKey" json:"id"`
+	Term    string `gorm:"size:64;not null;index:idx_search_terms_term,priority:1" json:"term"`
+	DocType string `gorm:"size:20;not null;index:idx_search_terms_term,priority:2;index:idx_search_terms_doc,priority:1" json:"doc_type"`
+	DocID   uint   `gorm:"not null;index:idx_search_terms_doc,priority:2" json:"doc_id"`
+	TitleTF int    `gorm:"default:0" json:"title_tf"` // 标题中的词频
+	BodyTF  int    `gorm:"default:0" json:"body_tf"`  // 正文中的词频
+	Length  int    `json:"length"`                    // 冗余文档长度，避免打分时回表
+}
+
+// TableName 表名
+func (SearchTerm) TableName() string {
+	return "search_terms"
+}
+
+// ===========================================
+// 检索 DTO
+// ===========================================
+
+// SearchHitVO 检索结果视图对象
+type SearchHitVO struct {
+	Type        string     `json:"type"` // post | life | comment
+	ID          uint       `json:"id"`
+	Title       string     `json:"title"`   // 已高亮（HTML）
+	Snippet     string     `json:"snippet"` // 已高亮（HTML）
+	Slug        string     `json:"slug,omitempty"`
+	TargetType  string     `json:"target_type,omitempty"`
+	TargetID    *uint      `json:"target_id,omitempty"`
+	Score       float64    `json:"score"`
+	PublishedAt *time.Time `json:"published_at"`
+}
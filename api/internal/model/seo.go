@@ -0,0 +1,69 @@
+// Package model SEO 元数据模型
+package model
+
+import (
+	"time"
+)
+
+// ===========================================
+// SEO 元数据 DTO
+// ===========================================
+
+// 页面类型
+const (
+	SEOPageHome    = "home"
+	SEOPagePost    = "post"
+	SEOPageLife    = "life"
+	SEOPageTag     = "tag"
+	SEOPageSeries  = "series"
+	SEOPageArchive = "archive"
+	SEOPageList    = "list" // 其他列表页，如 /blog、/life、/category
+)
+
+// SEOMetaVO 页面 SEO 元数据，前端 SSR 据此输出 <head>
+type SEOMetaVO struct {
+	Type        string           `json:"type"`
+	Title       string           `json:"title"`       // 完整的页面标题（含站点名）
+	Description string           `json:"description"` // 纯文本描述
+	Canonical   string           `json:"canonical"`   // 规范地址（绝对地址）
+	Locale      string           `json:"locale"`
+	Robots      string           `json:"robots,omitempty"` // 如 noindex（不公开列出的文章）
+	Keywords    []string         `json:"keywords,omitempty"`
+	Alternates  []SEOAlternateVO `json:"alternates,omitempty"` // 其他语言版本
+	OpenGraph   OpenGraphVO      `json:"open_graph"`
+	Twitter     TwitterCardVO    `json:"twitter"`
+	JSONLD      JSONLD           `json:"json_ld"` // schema.org 文档（@graph）
+}
+
+// SEOAlternateVO 语言版本链接
+type SEOAlternateVO struct {
+	Hreflang string `json:"hreflang"`
+	Href     string `json:"href"`
+}
+
+// OpenGraphVO OpenGraph 字段
+type OpenGraphVO struct {
+	Type          string     `json:"type"` // website | article
+	Title         string     `json:"title"`
+	Description   string     `json:"description"`
+	URL           string     `json:"url"`
+	Image         string     `json:"image,omitempty"`
+	SiteName      string     `json:"site_name"`
+	Locale        string     `json:"locale"` // 如 zh_CN
+	PublishedTime *time.Time `json:"published_time,omitempty"`
+	ModifiedTime  *time.Time `json:"modified_time,omitempty"`
+	Author        string     `json:"author,omitempty"`
+	Tags          []string   `json:"tags,omitempty"`
+}
+
+// TwitterCardVO Twitter Card 字段
+type TwitterCardVO struct {
+	Card        string `json:"card"` // summary | summary_large_image
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Image       string `json:"image,omitempty"`
+	Site        string `json:"site,omitempty"` // 站点账号，如 @example
+}
+
+// JSONLD schema.org 结构化数据
+type JSONLD map[string]interface{}
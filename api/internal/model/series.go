@@ -0,0 +1,135 @@
+// Package model 文章系列模型
+package model
+
+import (
+	"time"
+)
+
+// ===========================================
+// 系列模型
+// ===========================================
+
+// Series 文章系列（多篇连载教程等）
+type Series struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	Name        string    `gorm:"uniqueIndex;size:100;not null" json:"name"`
+	Slug        string    `gorm:"uniqueIndex;size:100" json:"slug"`
+	Description string    `gorm:"type:text" json:"description"`
+	CoverImage  string    `gorm:"size:500" json:"cover_image"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// TableName 表名
+func (Series) TableName() string {
+	return "series"
+}
+
+// SeriesPost 系列文章关联（一篇文章最多属于一个系列）
+type SeriesPost struct {
+	SeriesID uint `gorm:"primaryKey;index:idx_series_posts_position,priority:1"`
+	PostID   uint `gorm:"primaryKey;uniqueIndex"`
+	Position int  `gorm:"not null;index:idx_series_posts_position,priority:2"` // 系列内的顺序，从 1 开始
+}
+
+// TableName 表名
+func (SeriesPost) TableName() string {
+	return "series_posts"
+}
+
+// ===========================================
+// 系列 DTO
+// ===========================================
+
+// CreateSeriesRequest 创建系列请求
+type CreateSeriesRequest struct {
+	Name        string `json:"name" binding:"required,max=100"`
+	Slug        string `json:"slug" binding:"max=100"`
+	Description string `json:"description"`
+	CoverImage  string `json:"cover_image"`
+	PostIDs     []uint `json:"post_ids"` // 按顺序排列的文章 ID
+}
+
+// UpdateSeriesRequest 更新系列请求
+type UpdateSeriesRequest struct {
+	Name        string `json:"name" binding:"max=100"`
+	Slug        string `json:"slug" binding:"max=100"`
+	Description string `json:"description"`
+	CoverImage  string `json:"cover_image"`
+}
+
+// SetSeriesPostsRequest 设置系列文章及顺序请求
+type SetSeriesPostsRequest struct {
+	PostIDs []uint `json:"post_ids" binding:"required"` // 按顺序排列的文章 ID，未列出的文章将移出系列
+}
+
+// SeriesVO 系列视图对象
+type SeriesVO struct {
+	ID          uint         `json:"id"`
+	Name        string       `json:"name"`
+	Slug        string       `json:"slug"`
+	Description string       `json:"description,omitempty"`
+	CoverImage  string       `json:"cover_image,omitempty"`
+	PostCount   int          `json:"post_count"`
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+	Posts       []PostListVO `json:"posts,omitempty"`
+	Feeds       []FeedLinkVO `json:"feeds,omitempty"` // 订阅源，仅详情返回
+}
+
+// SeriesNavVO 系列内的相邻文章
+type SeriesNavVO struct {
+	ID       uint   `json:"id"`
+	Title    string `json:"title"`
+	Slug     string `json:"slug"`
+	Position int    `json:"position"`
+}
+
+// PostSeriesVO 文章所属系列信息
+type PostSeriesVO struct {
+	ID       uint         `json:"id"`
+	Name     string       `json:"name"`
+	Slug     string       `json:"slug"`
+	Position int          `json:"position"` // 当前文章在系列中的位置，从 1 开始
+	Total    int          `json:"total"`    // 系列文章总数
+	Prev     *SeriesNavVO `json:"prev"`
+	Next     *SeriesNavVO `json:"next"`
+}
+
+// ===========================================
+// 转换方法
+// ===========================================
+
+// ToVO 转换为视图对象
+func (s *Series) ToVO() SeriesVO {
+	return SeriesVO{
+		ID:          s.ID,
+		Name:        s.Name,
+		Slug:        s.Slug,
+		Description: s.Description,
+		CoverImage:  s.CoverImage,
+		CreatedAt:   s.CreatedAt,
+		UpdatedAt:   s.UpdatedAt,
+	}
+}
+
+// ToVOWithPosts 转换为带文章列表的视图对象
+func (s *Series) ToVOWithPosts(posts []Post) SeriesVO {
+	vo := s.ToVO()
+	vo.PostCount = len(posts)
+	vo.Posts = make([]PostListVO, len(posts))
+	for i, post := range posts {
+		vo.Posts[i] = post.ToListVO()
+	}
+	return vo
+}
+
+// ToSeriesNavVO 转换为系列导航视图对象
+func (p *Post) ToSeriesNavVO(position int) *SeriesNavVO {
+	return &SeriesNavVO{
+		ID:       p.ID,
+		Title:    p.Title,
+		Slug:     p.Slug,
+		Position: position,
+	}
+}
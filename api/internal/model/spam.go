@@ -0,0 +1,75 @@
+// Package model 评论反垃圾模型
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// ===========================================
+// 检查结果
+// ===========================================
+
+// SpamReason 单项反垃圾检查的结果
+type SpamReason struct {
+	Check  string  `json:"check"`  // 检查项名称，如 keyword、links、bayes
+	Score  float64 `json:"score"`  // 该项贡献的分数，可为负
+	Detail string  `json:"detail"` // 说明，如命中的敏感词
+}
+
+// GetSpamReasons 获取反垃圾检查结果
+func (c *Comment) GetSpamReasons() []SpamReason {
+	var reasons []SpamReason
+	if c.SpamReasons != "" {
+		json.Unmarshal([]byte(c.SpamReasons), &reasons)
+	}
+	return reasons
+}
+
+// SetSpamReasons 设置反垃圾检查结果
+func (c *Comment) SetSpamReasons(reasons []SpamReason) error {
+	data, err := json.Marshal(reasons)
+	if err != nil {
+		return err
+	}
+	c.SpamReasons = string(data)
+	return nil
+}
+
+// ===========================================
+// 贝叶斯分类器
+// ===========================================
+
+// 训练样本类别
+const (
+	SpamLabelSpam = "spam"
+	SpamLabelHam  = "ham" // 正常评论
+)
+
+// SpamToken 词项在两类训练样本中出现的评论数
+type SpamToken struct {
+	Token string `gorm:"primaryKey;size:64" json:"token"`
+	Spam  int    `gorm:"not null;default:0" json:"spam"`
+	Ham   int    `gorm:"not null;default:0" json:"ham"`
+}
+
+// TableName 表名
+func (SpamToken) TableName() string {
+	return "spam_tokens"
+}
+
+// SpamTraining 已用于训练的评论及其类别
+// 管理员改变审核结果时据此撤销原来的训练，保证每条评论只计入一次
+type SpamTraining struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	CommentID uint      `gorm:"not null;uniqueIndex" json:"comment_id"`
+	Label     string    `gorm:"size:10;not null;index" json:"label"`
+	Tokens    string    `gorm:"type:text" json:"-"` // 训练时的词项，换行分隔，撤销时使用
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName 表名
+func (SpamTraining) TableName() string {
+	return "spam_trainings"
+}
@@ -60,12 +60,13 @@ type UpdateTagRequest struct {
 
 // TagVO 标签视图对象
 type TagVO struct {
-	ID          uint   `json:"id"`
-	Name        string `json:"name"`
-	Slug        string `json:"slug"`
-	Description string `json:"description,omitempty"`
-	Color       string `json:"color,omitempty"`
-	PostCount   int    `json:"post_count,omitempty"`
+	ID          uint         `json:"id"`
+	Name        string       `json:"name"`
+	Slug        string       `json:"slug"`
+	Description string       `json:"description,omitempty"`
+	Color       string       `json:"color,omitempty"`
+	PostCount   int          `json:"post_count,omitempty"`
+	Feeds       []FeedLinkVO `json:"feeds,omitempty"` // 订阅源，仅详情返回
 }
 
 // TagWithPostsVO 带文章的标签视图对象
@@ -0,0 +1,78 @@
+// Package model 文章翻译模型
+package model
+
+import (
+	"time"
+)
+
+// ===========================================
+// 文章翻译模型
+// ===========================================
+
+// PostTranslation 文章的其他语言版本
+// 标题、slug、正文和摘要独立，标签、阅读量、评论等与原文共享
+type PostTranslation struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	PostID    uint      `gorm:"not null;uniqueIndex:idx_post_translations_locale,priority:1" json:"post_id"`
+	Locale    string    `gorm:"size:20;not null;uniqueIndex:idx_post_translations_locale,priority:2" json:"locale"`
+	Title     string    `gorm:"size:200;not null" json:"title"`
+	Slug      string    `gorm:"uniqueIndex;size:200" json:"slug"`
+	Content   string    `gorm:"type:text" json:"content"`
+	Excerpt   string    `gorm:"type:text" json:"excerpt"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName 表名
+func (PostTranslation) TableName() string {
+	return "post_translations"
+}
+
+// ===========================================
+// 文章翻译 DTO
+// ===========================================
+
+// SavePostTranslationRequest 创建或更新翻译请求（语言由路径参数指定）
+type SavePostTranslationRequest struct {
+	Title   string `json:"title" binding:"required,max=200"`
+	Slug    string `json:"slug" binding:"max=200"`
+	Content string `json:"content" binding:"required"`
+	Excerpt string `json:"excerpt"`
+}
+
+// PostLanguageVO 文章的一个可用语言版本
+type PostLanguageVO struct {
+	Locale string `json:"locale"`
+	Slug   string `json:"slug"`
+	Title  string `json:"title"`
+}
+
+// ===========================================
+// 转换方法
+// ===========================================
+
+// ApplyTo 用翻译内容覆盖文章视图
+func (t *PostTranslation) ApplyTo(vo *PostVO) {
+	vo.Title = t.Title
+	vo.Slug = t.Slug
+	vo.Content = t.Content
+	vo.Excerpt = t.Excerpt
+	vo.Locale = t.Locale
+}
+
+// ApplyToList 用翻译内容覆盖文章列表视图
+func (t *PostTranslation) ApplyToList(vo *PostListVO) {
+	vo.Title = t.Title
+	vo.Slug = t.Slug
+	vo.Excerpt = t.Excerpt
+	vo.Locale = t.Locale
+}
+
+// ToLanguageVO 转换为语言版本视图
+func (t *PostTranslation) ToLanguageVO() PostLanguageVO {
+	return PostLanguageVO{
+		Locale: t.Locale,
+		Slug:   t.Slug,
+		Title:  t.Title,
+	}
+}
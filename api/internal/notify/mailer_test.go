@@ -0,0 +1,288 @@
+package notify
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testMessage() *Message {
+	return &Message{
+		From:           "Yu.kuai <noreply@example.com>",
+		To:             "reader@example.org",
+		Subject:        "[Yu.kuai] 你在《测试》的评论收到了回复",
+		Text:           "你好，\n" + strings.Repeat("很长的一行纯文本，", 20) + "\n= 结束",
+		HTML:           `<p class="reply">你好，<a href="https://example.com/blog/a?x=1&y=2">查看</a></p>`,
+		UnsubscribeURL: "https://example.com/api/notifications/unsubscribe?token=abc",
+	}
+}
+
+// ===========================================
+// 邮件编码
+// ===========================================
+
+func TestMessageBytes(t *testing.T) {
+	msg := testMessage()
+	data, err := msg.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsed, err := mail.ReadMessage(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("read message: %v", err)
+	}
+	h := parsed.Header
+
+	for key, want := range map[string]string{
+		"From":                  `"Yu.kuai" <noreply@example.com>`,
+		"To":                    "<reader@example.org>",
+		"MIME-Version":          "1.0",
+		"Auto-Submitted":        "auto-generated",
+		"List-Unsubscribe":      "<" + msg.UnsubscribeURL + ">",
+		"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+	} {
+		if got := h.Get(key); got != want {
+			t.Errorf("%s = %q, want %q", key, got, want)
+		}
+	}
+
+	rawSubject := h.Get("Subject")
+	if !strings.HasPrefix(rawSubject, "=?utf-8?b?") {
+		t.Errorf("Subject = %q, want B-encoded", rawSubject)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(rawSubject)
+	if err != nil || subject != msg.Subject {
+		t.Errorf("decoded Subject = %q (%v), want %q", subject, err, msg.Subject)
+	}
+	if _, err := h.Date(); err != nil {
+		t.Errorf("Date: %v", err)
+	}
+	if id := h.Get("Message-ID"); !strings.HasPrefix(id, "<") || !strings.HasSuffix(id, "@example.com>") {
+		t.Errorf("Message-ID = %q", id)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(h.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/alternative" {
+		t.Fatalf("Content-Type = %q (%v)", h.Get("Content-Type"), err)
+	}
+
+	reader := multipart.NewReader(parsed.Body, params["boundary"])
+	for _, want := range []struct {
+		contentType string
+		content     string
+	}{
+		{"text/plain; charset=utf-8", msg.Text},
+		{"text/html; charset=utf-8", msg.HTML},
+	} {
+		part, err := reader.NextRawPart()
+		if err != nil {
+			t.Fatalf("next part: %v", err)
+		}
+		if got := part.Header.Get("Content-Type"); got != want.contentType {
+			t.Errorf("part Content-Type = %q, want %q", got, want.contentType)
+		}
+		if got := part.Header.Get("Content-Transfer-Encoding"); got != "quoted-printable" {
+			t.Errorf("part Content-Transfer-Encoding = %q", got)
+		}
+
+		raw, err := io.ReadAll(part)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, line := range strings.Split(string(raw), "\r\n") {
+			if len(line) > 76 {
+				t.Errorf("encoded line longer than 76 characters: %q", line)
+			}
+		}
+		decoded, err := io.ReadAll(quotedprintable.NewReader(bytes.NewReader(raw)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		// 文本换行按 MIME 规范编码为 CRLF
+		if got := strings.ReplaceAll(string(decoded), "\r\n", "\n"); got != want.content {
+			t.Errorf("decoded %s = %q, want %q", want.contentType, got, want.content)
+		}
+	}
+	if _, err := reader.NextPart(); err != io.EOF {
+		t.Errorf("expected exactly two parts, got %v", err)
+	}
+}
+
+func TestMessageBytesWithoutUnsubscribe(t *testing.T) {
+	msg := testMessage()
+	msg.UnsubscribeURL = ""
+	data, err := msg.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(data, []byte("List-Unsubscribe")) {
+		t.Error("List-Unsubscribe headers written without an unsubscribe URL")
+	}
+}
+
+func TestMessageBytesInvalidAddress(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		edit func(*Message)
+	}{
+		{"from", func(m *Message) { m.From = "not an address" }},
+		{"to", func(m *Message) { m.To = "" }},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			msg := testMessage()
+			tc.edit(msg)
+			if _, err := msg.Bytes(); err == nil || !strings.Contains(err.Error(), "invalid "+tc.name) {
+				t.Errorf("Bytes() error = %v, want invalid %s address", err, tc.name)
+			}
+		})
+	}
+}
+
+// ===========================================
+// SMTP 发送
+// ===========================================
+
+// smtpSession 假 SMTP 服务器收到的一封邮件
+type smtpSession struct {
+	commands []string
+	from     string
+	to       []string
+	data     string
+}
+
+// fakeSMTP 启动只支持明文会话的假 SMTP 服务器，处理一个连接后把会话内容发到返回的通道
+// rejectRcpt 不为空时拒绝该收件人
+func fakeSMTP(t *testing.T, rejectRcpt string) (string, int, <-chan smtpSession) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	sessions := make(chan smtpSession, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+		tp := textproto.NewConn(conn)
+		var s smtpSession
+		defer func() { sessions <- s }()
+
+		tp.PrintfLine("220 fake.smtp ESMTP")
+		for {
+			line, err := tp.ReadLine()
+			if err != nil {
+				return
+			}
+			verb, arg, _ := strings.Cut(line, " ")
+			verb = strings.ToUpper(verb)
+			s.commands = append(s.commands, verb)
+
+			switch verb {
+			case "EHLO", "HELO":
+				tp.PrintfLine("250-fake.smtp")
+				tp.PrintfLine("250 8BITMIME")
+			case "MAIL":
+				s.from = arg
+				tp.PrintfLine("250 OK")
+			case "RCPT":
+				if rejectRcpt != "" && strings.Contains(arg, rejectRcpt) {
+					tp.PrintfLine("550 no such user")
+					continue
+				}
+				s.to = append(s.to, arg)
+				tp.PrintfLine("250 OK")
+			case "DATA":
+				tp.PrintfLine("354 go ahead")
+				data, err := tp.ReadDotBytes()
+				if err != nil {
+					return
+				}
+				s.data = string(data)
+				tp.PrintfLine("250 queued")
+			case "QUIT":
+				tp.PrintfLine("221 bye")
+				return
+			default:
+				tp.PrintfLine("502 not implemented")
+			}
+		}
+	}()
+
+	addr := ln.Addr().(*net.TCPAddr)
+	return addr.IP.String(), addr.Port, sessions
+}
+
+func TestSMTPMailerSend(t *testing.T) {
+	host, port, sessions := fakeSMTP(t, "")
+	mailer := &SMTPMailer{Host: host, Port: port, TLS: "none", Timeout: 5 * time.Second}
+
+	msg := testMessage()
+	if err := mailer.Send(msg); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	s := <-sessions
+	if got, want := strings.Join(s.commands, " "), "EHLO MAIL RCPT DATA QUIT"; got != want {
+		t.Errorf("commands = %q, want %q", got, want)
+	}
+	if !strings.HasPrefix(s.from, "FROM:<noreply@example.com>") {
+		t.Errorf("MAIL %q", s.from)
+	}
+	if len(s.to) != 1 || s.to[0] != "TO:<reader@example.org>" {
+		t.Errorf("RCPT %q", s.to)
+	}
+
+	// DATA 的内容在传输时统一换行为 CRLF，读取时还原为 LF
+	parsed, err := mail.ReadMessage(bufio.NewReader(strings.NewReader(s.data)))
+	if err != nil {
+		t.Fatalf("read delivered message: %v", err)
+	}
+	if got := parsed.Header.Get("To"); got != "<reader@example.org>" {
+		t.Errorf("delivered To = %q", got)
+	}
+	if got := parsed.Header.Get("List-Unsubscribe"); got != "<"+msg.UnsubscribeURL+">" {
+		t.Errorf("delivered List-Unsubscribe = %q", got)
+	}
+}
+
+func TestSMTPMailerRejectedRecipient(t *testing.T) {
+	host, port, sessions := fakeSMTP(t, "reader@example.org")
+	mailer := &SMTPMailer{Host: host, Port: port, TLS: "none", Timeout: 5 * time.Second}
+
+	err := mailer.Send(testMessage())
+	if err == nil || !strings.Contains(err.Error(), "no such user") {
+		t.Fatalf("Send error = %v, want rejected recipient", err)
+	}
+	if s := <-sessions; s.data != "" {
+		t.Error("message data sent after the recipient was rejected")
+	}
+}
+
+func TestSMTPMailerRequiresStartTLS(t *testing.T) {
+	host, port, sessions := fakeSMTP(t, "")
+	mailer := &SMTPMailer{Host: host, Port: port, TLS: "starttls", Timeout: 5 * time.Second}
+
+	err := mailer.Send(testMessage())
+	if err == nil || !strings.Contains(err.Error(), "STARTTLS") {
+		t.Fatalf("Send error = %v, want missing STARTTLS", err)
+	}
+	if s := <-sessions; s.from != "" {
+		t.Error("message sent over an unencrypted connection")
+	}
+}
@@ -0,0 +1,346 @@
+// Package notify 邮件通知
+// 评论被回复时通知原评论者，有新评论或待审核评论时通知站长。
+// 通知渲染后写入发件箱（email_outbox），由后台队列通过 SMTP 或开发用的文件、日志方式发送，
+// 失败后按指数退避重试；每封邮件都带有签名的一键退订链接
+package notify
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"embed"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"gorm.io/gorm/clause"
+	"kuaiyu/internal/config"
+	"kuaiyu/internal/database"
+	"kuaiyu/internal/model"
+	"kuaiyu/pkg/constants"
+	"kuaiyu/pkg/utils"
+)
+
+// ===========================================
+// 模板
+// ===========================================
+
+//go:embed templates
+var templateFS embed.FS
+
+var (
+	htmlTemplates = htmltemplate.Must(htmltemplate.ParseFS(templateFS, "templates/*.html"))
+	textTemplates = texttemplate.Must(texttemplate.ParseFS(templateFS, "templates/*.txt"))
+)
+
+// mailData 通知模板数据
+type mailData struct {
+	SiteName       string
+	SiteURL        string
+	Subject        string
+	PageTitle      string // 评论所在页面的标题
+	PageURL        string
+	CommentURL     string // 新评论的地址
+	Nickname       string // 新评论的作者
+	Email          string // 新评论的作者邮箱，仅站长通知使用
+	Content        string
+	ParentNickname string // 被回复的评论
+	ParentContent  string
+	Pending        bool // 新评论是否待审核
+	UnsubscribeURL string
+}
+
+// render 渲染 HTML 和纯文本正文
+func render(name string, data *mailData) (string, string, error) {
+	var html, text bytes.Buffer
+	if err := htmlTemplates.ExecuteTemplate(&html, name+".html", data); err != nil {
+		return "", "", err
+	}
+	if err := textTemplates.ExecuteTemplate(&text, name+".txt", data); err != nil {
+		return "", "", err
+	}
+	return html.String(), text.String(), nil
+}
+
+// UnsubscribePage 渲染退订结果页面
+func UnsubscribePage(email string, ok bool) ([]byte, error) {
+	site := config.Get().Site
+	var buf bytes.Buffer
+	err := htmlTemplates.ExecuteTemplate(&buf, "unsubscribe.html", map[string]interface{}{
+		"SiteName": site.Name,
+		"SiteURL":  site.URL,
+		"Email":    email,
+		"OK":       ok,
+	})
+	return buf.Bytes(), err
+}
+
+// ===========================================
+// 退订
+// ===========================================
+
+// 退订令牌错误
+var (
+	ErrInvalidToken = errors.New("invalid unsubscribe token")
+	ErrExpiredToken = errors.New("unsubscribe token expired")
+)
+
+// UnsubscribeToken 邮箱的退订令牌，有效期由 MAIL_UNSUBSCRIBE_TTL 配置
+func UnsubscribeToken(email string) string {
+	return unsubscribeToken(email, time.Now().Add(config.Get().Mail.UnsubscribeTTL))
+}
+
+// unsubscribeToken 退订令牌：base64(邮箱).过期时间戳.base64(HMAC 签名)
+func unsubscribeToken(email string, expiresAt time.Time) string {
+	email = normalizeEmail(email)
+	expires := expiresAt.Unix()
+	return base64.RawURLEncoding.EncodeToString([]byte(email)) + "." +
+		strconv.FormatInt(expires, 10) + "." +
+		base64.RawURLEncoding.EncodeToString(sign(email, expires))
+}
+
+// UnsubscribeURL 邮箱的一键退订地址
+func UnsubscribeURL(email string) string {
+	return config.Get().Site.URL + "/api/notifications/unsubscribe?token=" + url.QueryEscape(UnsubscribeToken(email))
+}
+
+// VerifyUnsubscribeToken 校验退订令牌，返回令牌对应的邮箱
+// 签名不符或格式错误返回 ErrInvalidToken，签名正确但已过期返回 ErrExpiredToken
+func VerifyUnsubscribeToken(token string) (string, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return "", ErrInvalidToken
+	}
+	rawEmail, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return "", ErrInvalidToken
+	}
+	expires, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return "", ErrInvalidToken
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", ErrInvalidToken
+	}
+	email := string(rawEmail)
+	if !hmac.Equal(sig, sign(email, expires)) {
+		return "", ErrInvalidToken
+	}
+	if time.Now().Unix() > expires {
+		return "", ErrExpiredToken
+	}
+	return email, nil
+}
+
+// Unsubscribe 校验令牌并退订，返回退订的邮箱；尚未发送的通知一并取消
+func Unsubscribe(token string) (string, error) {
+	email, err := VerifyUnsubscribeToken(token)
+	if err != nil {
+		return "", err
+	}
+
+	db := database.Get()
+	if err := db.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&model.EmailUnsubscribe{Email: email}).Error; err != nil {
+		return "", err
+	}
+	db.Model(&model.EmailOutbox{}).
+		Where("recipient = ? AND status = ?", email, model.EmailStatusPending).
+		Updates(map[string]interface{}{
+			"status":          model.EmailStatusFailed,
+			"error":           "unsubscribed",
+			"next_attempt_at": nil,
+		})
+	return email, nil
+}
+
+// unsubscribed 邮箱是否已退订
+func unsubscribed(email string) bool {
+	var count int64
+	database.Get().Model(&model.EmailUnsubscribe{}).Where("email = ?", normalizeEmail(email)).Count(&count)
+	return count > 0
+}
+
+// sign 邮箱和过期时间的签名
+func sign(email string, expires int64) []byte {
+	mac := hmac.New(sha256.New, []byte(config.Get().Mail.Secret))
+	mac.Write([]byte("unsubscribe:" + email + ":" + strconv.FormatInt(expires, 10)))
+	return mac.Sum(nil)[:16]
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// ===========================================
+// 评论通知
+// ===========================================
+
+// CommentCreated 新评论提交后调用
+// 通知站长（管理员自己的回复除外）；已通过审核的回复同时通知被回复的评论者
+func CommentCreated(comment *model.Comment) {
+	cfg := config.Get().Mail
+	if !cfg.Enabled || comment.Status == string(constants.CommentStatusSpam) {
+		return
+	}
+
+	page := commentPage(comment)
+	parent := replyTarget(comment)
+
+	if cfg.OwnerEmail != "" && !comment.IsAdmin && !sameEmail(comment.Email, cfg.OwnerEmail) {
+		data := page.data(comment)
+		data.Email = comment.Email
+		data.Pending = comment.Status == string(constants.CommentStatusPending)
+		if parent != nil {
+			data.ParentNickname = parent.Nickname
+		}
+
+		subject := "新评论：《" + page.title + "》"
+		if data.Pending {
+			subject = "新评论待审核：《" + page.title + "》"
+		}
+		enqueue(model.NotificationKindComment, comment.ID, cfg.OwnerEmail, subject, data)
+	}
+
+	if comment.Status == string(constants.CommentStatusApproved) {
+		notifyReply(comment, parent, page)
+	}
+}
+
+// CommentApproved 评论通过审核后调用，通知被回复的评论者
+func CommentApproved(comment *model.Comment) {
+	if !config.Get().Mail.Enabled {
+		return
+	}
+	notifyReply(comment, replyTarget(comment), commentPage(comment))
+}
+
+// notifyReply 通知被回复的评论者
+// 被回复的是管理员（站长已收到新评论通知）或回复自己的评论时不通知
+func notifyReply(comment, parent *model.Comment, page pageInfo) {
+	if parent == nil || parent.IsAdmin || parent.Email == "" || sameEmail(parent.Email, comment.Email) {
+		return
+	}
+	if parent.Status == string(constants.CommentStatusSpam) {
+		return
+	}
+
+	data := page.data(comment)
+	data.ParentNickname = parent.Nickname
+	data.ParentContent = parent.Content
+	enqueue(model.NotificationKindReply, comment.ID, parent.Email, "你在《"+page.title+"》的评论收到了回复", data)
+}
+
+// replyTarget 被回复的评论：优先取 reply_to_id，其次取 parent_id
+func replyTarget(comment *model.Comment) *model.Comment {
+	id := comment.ReplyToID
+	if id == nil {
+		id = comment.ParentID
+	}
+	if id == nil {
+		return nil
+	}
+
+	var parent model.Comment
+	if err := database.Get().First(&parent, *id).Error; err != nil {
+		return nil
+	}
+	return &parent
+}
+
+// pageInfo 评论所在页面
+type pageInfo struct {
+	title string
+	url   string
+}
+
+// commentPage 查询评论所在页面的标题和地址
+func commentPage(comment *model.Comment) pageInfo {
+	site := config.Get().Site
+	db := database.Get()
+
+	targetID := comment.TargetID
+	switch comment.CommentType {
+	case "post":
+		if targetID == nil {
+			targetID = comment.PostID
+		}
+		var post model.Post
+		if targetID != nil && db.First(&post, *targetID).Error == nil {
+			return pageInfo{title: post.Title, url: site.URL + "/blog/" + post.Slug}
+		}
+	case "life":
+		if targetID == nil {
+			targetID = comment.LifeRecordID
+		}
+		var record model.LifeRecord
+		if targetID != nil && db.First(&record, *targetID).Error == nil {
+			title := record.Title
+			if title == "" {
+				title = utils.GenerateExcerpt(record.Content, 30)
+			}
+			return pageInfo{title: title, url: site.URL + "/life/" + strconv.FormatUint(uint64(record.ID), 10)}
+		}
+	}
+	return pageInfo{title: "留言板", url: site.URL + "/guestbook"}
+}
+
+// data 通知模板的公共数据
+func (p pageInfo) data(comment *model.Comment) *mailData {
+	site := config.Get().Site
+	return &mailData{
+		SiteName:   site.Name,
+		SiteURL:    site.URL,
+		PageTitle:  p.title,
+		PageURL:    p.url,
+		CommentURL: fmt.Sprintf("%s#comment-%d", p.url, comment.ID),
+		Nickname:   comment.Nickname,
+		Content:    comment.Content,
+	}
+}
+
+// enqueue 渲染通知并写入发件箱，收件人已退订时忽略
+func enqueue(kind string, commentID uint, to, subject string, data *mailData) {
+	to = normalizeEmail(to)
+	if unsubscribed(to) {
+		return
+	}
+
+	data.Subject = "[" + data.SiteName + "] " + subject
+	data.UnsubscribeURL = UnsubscribeURL(to)
+	html, text, err := render(kind, data)
+	if err != nil {
+		log.Printf("Failed to render %s notification for comment %d: %v", kind, commentID, err)
+		return
+	}
+
+	now := time.Now()
+	entry := model.EmailOutbox{
+		Kind:           kind,
+		CommentID:      commentID,
+		Recipient:      to,
+		Subject:        data.Subject,
+		HTML:           html,
+		Text:           text,
+		UnsubscribeURL: data.UnsubscribeURL,
+		Status:         model.EmailStatusPending,
+		NextAttemptAt:  &now,
+	}
+	if err := database.Get().Create(&entry).Error; err != nil {
+		log.Printf("Failed to queue %s notification for comment %d: %v", kind, commentID, err)
+		return
+	}
+	Wake()
+}
+
+func sameEmail(a, b string) bool {
+	return normalizeEmail(a) == normalizeEmail(b)
+}
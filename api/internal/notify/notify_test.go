@@ -0,0 +1,102 @@
+package notify
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"kuaiyu/internal/config"
+)
+
+// ===========================================
+// 退订令牌
+// ===========================================
+
+func TestUnsubscribeTokenRoundTrip(t *testing.T) {
+	token := UnsubscribeToken("  Reader@Example.ORG ")
+	email, err := VerifyUnsubscribeToken(token)
+	if err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	if email != "reader@example.org" {
+		t.Errorf("email = %q, want normalized reader@example.org", email)
+	}
+}
+
+func TestUnsubscribeTokenTTL(t *testing.T) {
+	cfg := &config.Get().Mail
+	ttl := cfg.UnsubscribeTTL
+	t.Cleanup(func() { cfg.UnsubscribeTTL = ttl })
+	cfg.UnsubscribeTTL = 48 * time.Hour
+
+	parts := strings.Split(UnsubscribeToken("reader@example.org"), ".")
+	if len(parts) != 3 {
+		t.Fatalf("token has %d parts, want 3", len(parts))
+	}
+	expires, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := time.Now().Add(48 * time.Hour).Unix()
+	if expires < want-5 || expires > want+5 {
+		t.Errorf("expires = %d, want about %d", expires, want)
+	}
+}
+
+func TestVerifyUnsubscribeTokenExpired(t *testing.T) {
+	token := unsubscribeToken("reader@example.org", time.Now().Add(-time.Minute))
+	if _, err := VerifyUnsubscribeToken(token); !errors.Is(err, ErrExpiredToken) {
+		t.Errorf("verify expired token: %v, want ErrExpiredToken", err)
+	}
+}
+
+func TestVerifyUnsubscribeTokenInvalid(t *testing.T) {
+	expiresAt := time.Now().Add(time.Hour)
+	valid := unsubscribeToken("reader@example.org", expiresAt)
+	parts := strings.Split(valid, ".")
+	encode := base64.RawURLEncoding.EncodeToString
+
+	// 用另一个邮箱的签名冒充
+	other := strings.Split(unsubscribeToken("other@example.org", expiresAt), ".")
+
+	// 把已过期令牌的过期时间改到未来
+	expired := strings.Split(unsubscribeToken("reader@example.org", time.Now().Add(-time.Hour)), ".")
+
+	sig, _ := base64.RawURLEncoding.DecodeString(parts[2])
+	sig[0] ^= 1
+
+	for name, token := range map[string]string{
+		"empty":            "",
+		"legacy format":    parts[0] + "." + parts[2],
+		"extra part":       valid + ".x",
+		"bad email base64": "!!." + parts[1] + "." + parts[2],
+		"bad expiry":       parts[0] + ".soon." + parts[2],
+		"bad sig base64":   parts[0] + "." + parts[1] + ".!!",
+		"tampered email":   encode([]byte("victim@example.org")) + "." + parts[1] + "." + parts[2],
+		"borrowed sig":     parts[0] + "." + parts[1] + "." + other[2],
+		"tampered sig":     parts[0] + "." + parts[1] + "." + encode(sig),
+		"extended expiry":  expired[0] + "." + strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10) + "." + expired[2],
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := VerifyUnsubscribeToken(token); !errors.Is(err, ErrInvalidToken) {
+				t.Errorf("verify %q: %v, want ErrInvalidToken", token, err)
+			}
+		})
+	}
+}
+
+func TestVerifyUnsubscribeTokenSecret(t *testing.T) {
+	cfg := &config.Get().Mail
+	secret := cfg.Secret
+	t.Cleanup(func() { cfg.Secret = secret })
+
+	cfg.Secret = "old-secret"
+	token := UnsubscribeToken("reader@example.org")
+	cfg.Secret = "new-secret"
+	if _, err := VerifyUnsubscribeToken(token); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("verify token signed with another secret: %v, want ErrInvalidToken", err)
+	}
+}
@@ -0,0 +1,90 @@
+package notify
+
+import (
+	"log"
+	"net/mail"
+	"net/url"
+	"time"
+
+	"gorm.io/gorm"
+	"kuaiyu/internal/config"
+	"kuaiyu/internal/database"
+	"kuaiyu/internal/model"
+	"kuaiyu/internal/outbox"
+)
+
+// ===========================================
+// 发件箱队列
+// ===========================================
+
+// wake 唤醒队列立即发送新写入的邮件
+var wake = outbox.NewSignal()
+
+// Wake 唤醒队列
+func Wake() {
+	wake.Notify()
+}
+
+// Queue 发件箱队列
+// 周期性地发送到期的邮件；写入新邮件后会被立即唤醒
+type Queue struct {
+	*outbox.Worker
+	db     *gorm.DB
+	mailer Mailer
+}
+
+// NewQueue 创建发件箱队列
+func NewQueue(mailer Mailer, interval time.Duration) *Queue {
+	cfg := config.Get().Mail
+	q := &Queue{
+		db:     database.Get(),
+		mailer: mailer,
+	}
+	q.Worker = outbox.NewWorker(outbox.Options{
+		Name:        "Mail outbox",
+		Model:       &model.EmailOutbox{},
+		Pending:     model.EmailStatusPending,
+		Succeeded:   model.EmailStatusSent,
+		Failed:      model.EmailStatusFailed,
+		Interval:    interval,
+		MaxAttempts: cfg.MaxAttempts,
+		RetryDelay:  cfg.RetryDelay,
+		Wake:        wake,
+	}, q.deliver)
+	return q
+}
+
+// deliver 发送一封邮件
+func (q *Queue) deliver(id uint, attempt int) outbox.Result {
+	var entry model.EmailOutbox
+	if err := q.db.First(&entry, id).Error; err != nil {
+		return outbox.Result{Err: err}
+	}
+
+	err := q.mailer.Send(&Message{
+		From:           sender(),
+		To:             entry.Recipient,
+		Subject:        entry.Subject,
+		Text:           entry.Text,
+		HTML:           entry.HTML,
+		UnsubscribeURL: entry.UnsubscribeURL,
+	})
+	if err != nil {
+		log.Printf("Failed to send mail %d to %s (attempt %d): %v", id, entry.Recipient, attempt, err)
+		return outbox.Result{Err: err}
+	}
+	return outbox.Result{Updates: map[string]interface{}{"sent_at": time.Now()}}
+}
+
+// sender 发件人，未配置时使用站点名和站点域名
+func sender() string {
+	cfg := config.Get()
+	if cfg.Mail.From != "" {
+		return cfg.Mail.From
+	}
+	host := "localhost"
+	if u, err := url.Parse(cfg.Site.URL); err == nil && u.Hostname() != "" {
+		host = u.Hostname()
+	}
+	return (&mail.Address{Name: cfg.Site.Name, Address: "noreply@" + host}).String()
+}
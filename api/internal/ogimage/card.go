@@ -0,0 +1,324 @@
+// Package ogimage 文章分享图生成
+// 为没有封面的文章生成 1200x630 的 PNG 卡片（标题、标签、站点名和日期），
+// 使用内嵌的文泉驿微米黑字体，中英文混排均可正常显示；生成结果缓存到 COS
+package ogimage
+
+import (
+	"bytes"
+	"crypto/sha256"
+	_ "embed"
+	"encoding/hex"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+)
+
+// ===========================================
+// 卡片
+// ===========================================
+
+// Version 卡片样式版本，修改布局后递增，已生成的图片随之失效
+const Version = "1"
+
+// 画布尺寸（OpenGraph 推荐 1.91:1）
+const (
+	Width  = 1200
+	Height = 630
+)
+
+// Card 分享图内容
+type Card struct {
+	Title    string
+	Tags     []string
+	SiteName string
+	Date     time.Time
+}
+
+// Hash 卡片内容哈希，内容或样式版本变化时改变
+// 只由卡片内容决定，不同文章的卡片可能相同，COS 路径还需加上文章 ID（见 objectKey）
+func (c Card) Hash() string {
+	raw := strings.Join([]string{
+		Version,
+		c.Title,
+		strings.Join(c.Tags, ","),
+		c.SiteName,
+		c.Date.Format("2006-01-02"),
+	}, "\x00")
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
+
+// ===========================================
+// 样式
+// ===========================================
+
+var (
+	colorBackgroundTop    = color.RGBA{R: 0x0f, G: 0x17, B: 0x2a, A: 0xff}
+	colorBackgroundBottom = color.RGBA{R: 0x1e, G: 0x29, B: 0x3b, A: 0xff}
+	colorAccent           = color.RGBA{R: 0x38, G: 0xbd, B: 0xf8, A: 0xff}
+	colorTitle            = color.RGBA{R: 0xf8, G: 0xfa, B: 0xfc, A: 0xff}
+	colorMuted            = color.RGBA{R: 0x94, G: 0xa3, B: 0xb8, A: 0xff}
+	colorDivider          = color.RGBA{R: 0x33, G: 0x41, B: 0x55, A: 0xff}
+)
+
+const (
+	padding       = 80
+	accentWidth   = 12
+	titleTop      = 190          // 标题区域上边界
+	titleBottom   = Height - 160 // 标题区域下边界（分隔线上方）
+	titleMaxLines = 3
+	tagsMaxCount  = 5
+)
+
+// titleSizes 标题字号，放不下时依次缩小，最小字号仍放不下则截断
+var titleSizes = []float64{68, 58, 50}
+
+// ===========================================
+// 渲染
+// ===========================================
+
+// Render 渲染 PNG
+func (c Card) Render() ([]byte, error) {
+	f, err := loadFont()
+	if err != nil {
+		return nil, err
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, Width, Height))
+	fillGradient(img, colorBackgroundTop, colorBackgroundBottom)
+	draw.Draw(img, image.Rect(0, 0, accentWidth, Height), image.NewUniform(colorAccent), image.Point{}, draw.Src)
+
+	contentWidth := fixed.I(Width - padding*2)
+
+	// 标签
+	if len(c.Tags) > 0 {
+		face, err := newFace(f, 30)
+		if err != nil {
+			return nil, err
+		}
+		tags := c.Tags
+		if len(tags) > tagsMaxCount {
+			tags = tags[:tagsMaxCount]
+		}
+		labels := make([]string, len(tags))
+		for i, tag := range tags {
+			labels[i] = "#" + tag
+		}
+		line := ellipsis(face, strings.Join(labels, "  "), contentWidth)
+		drawText(img, face, colorAccent, line, padding, 130)
+		face.Close()
+	}
+
+	// 标题
+	var titleFace font.Face
+	var lines []string
+	var lineHeight, maxLines int
+	for _, size := range titleSizes {
+		if titleFace != nil {
+			titleFace.Close()
+		}
+		if titleFace, err = newFace(f, size); err != nil {
+			return nil, err
+		}
+		lineHeight = titleFace.Metrics().Height.Ceil() * 13 / 10
+		maxLines = min(titleMaxLines, (titleBottom-titleTop)/lineHeight)
+		lines = wrap(titleFace, c.Title, contentWidth)
+		if len(lines) <= maxLines {
+			break
+		}
+	}
+	if len(lines) > maxLines {
+		lines = lines[:maxLines]
+		lines[maxLines-1] = ellipsis(titleFace, lines[maxLines-1]+"…", contentWidth)
+	}
+	y := titleTop + titleFace.Metrics().Ascent.Ceil()
+	for _, line := range lines {
+		drawText(img, titleFace, colorTitle, line, padding, y)
+		y += lineHeight
+	}
+	titleFace.Close()
+
+	// 底部：站点名和日期
+	draw.Draw(img, image.Rect(padding, Height-140, Width-padding, Height-138), image.NewUniform(colorDivider), image.Point{}, draw.Src)
+
+	footerFace, err := newFace(f, 32)
+	if err != nil {
+		return nil, err
+	}
+	defer footerFace.Close()
+
+	drawText(img, footerFace, colorTitle, ellipsis(footerFace, c.SiteName, contentWidth/2), padding, Height-76)
+	if !c.Date.IsZero() {
+		date := c.Date.Format("2006-01-02")
+		width := font.MeasureString(footerFace, date).Ceil()
+		drawText(img, footerFace, colorMuted, date, Width-padding-width, Height-76)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("encode og image: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// ===========================================
+// 字体
+// ===========================================
+
+//go:embed fonts/wqy-microhei.ttf
+var fontData []byte
+
+var (
+	fontOnce   sync.Once
+	parsedFont *sfnt.Font
+	fontErr    error
+)
+
+// loadFont 解析内嵌字体（只解析一次）
+func loadFont() (*sfnt.Font, error) {
+	fontOnce.Do(func() {
+		parsedFont, fontErr = opentype.Parse(fontData)
+		if fontErr != nil {
+			fontErr = fmt.Errorf("parse og image font: %w", fontErr)
+		}
+	})
+	return parsedFont, fontErr
+}
+
+// newFace 创建指定字号的字体
+func newFace(f *sfnt.Font, size float64) (font.Face, error) {
+	return opentype.NewFace(f, &opentype.FaceOptions{
+		Size:    size,
+		DPI:     72,
+		Hinting: font.HintingFull,
+	})
+}
+
+// ===========================================
+// 排版
+// ===========================================
+
+// drawText 在基线 (x, y) 处绘制一行文字
+func drawText(dst draw.Image, face font.Face, c color.Color, text string, x, y int) {
+	d := &font.Drawer{
+		Dst:  dst,
+		Src:  image.NewUniform(c),
+		Face: face,
+		Dot:  fixed.P(x, y),
+	}
+	d.DrawString(text)
+}
+
+// wrap 按宽度折行：中日韩文字逐字断行，英文按单词断行，超长单词逐字断行
+func wrap(face font.Face, text string, maxWidth fixed.Int26_6) []string {
+	var lines []string
+	var current string
+
+	for _, token := range tokenize(strings.Join(strings.Fields(text), " ")) {
+		if font.MeasureString(face, token) > maxWidth {
+			// 超长单词拆成单个字符
+			for _, r := range token {
+				current, lines = appendToken(face, current, string(r), maxWidth, lines)
+			}
+			continue
+		}
+		current, lines = appendToken(face, current, token, maxWidth, lines)
+	}
+	if current = strings.TrimSpace(current); current != "" {
+		lines = append(lines, current)
+	}
+	return lines
+}
+
+// appendToken 将片段追加到当前行，放不下时换行（行首不保留空格）
+func appendToken(face font.Face, current, token string, maxWidth fixed.Int26_6, lines []string) (string, []string) {
+	if current != "" && font.MeasureString(face, current+token) > maxWidth {
+		lines = append(lines, strings.TrimSpace(current))
+		current = ""
+	}
+	if current == "" && token == " " {
+		return current, lines
+	}
+	return current + token, lines
+}
+
+// tokenize 拆分为断行片段：连续的字母数字为一个片段（连字符之后可以断行），
+// 其余字符（中日韩文字、标点、空格）各为一个片段
+func tokenize(text string) []string {
+	var tokens []string
+	var word []rune
+
+	flush := func() {
+		if len(word) > 0 {
+			tokens = append(tokens, string(word))
+			word = word[:0]
+		}
+	}
+	for _, r := range text {
+		if isWordRune(r) {
+			word = append(word, r)
+			if r == '-' {
+				flush()
+			}
+			continue
+		}
+		flush()
+		tokens = append(tokens, string(r))
+	}
+	flush()
+	return tokens
+}
+
+// isWordRune 是否属于不可拆分的英文单词
+func isWordRune(r rune) bool {
+	if unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) {
+		return false
+	}
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '\'' || r == '.' || r == '_'
+}
+
+// ellipsis 超出宽度时截断并以省略号结尾
+func ellipsis(face font.Face, text string, maxWidth fixed.Int26_6) string {
+	if font.MeasureString(face, text) <= maxWidth {
+		return text
+	}
+	runes := []rune(strings.TrimSuffix(text, "…"))
+	for len(runes) > 0 {
+		runes = runes[:len(runes)-1]
+		candidate := strings.TrimSpace(string(runes)) + "…"
+		if font.MeasureString(face, candidate) <= maxWidth {
+			return candidate
+		}
+	}
+	return "…"
+}
+
+// fillGradient 纵向渐变背景
+func fillGradient(img *image.RGBA, top, bottom color.RGBA) {
+	h := img.Bounds().Dy()
+	for y := 0; y < h; y++ {
+		t := float64(y) / float64(h-1)
+		c := color.RGBA{
+			R: lerp(top.R, bottom.R, t),
+			G: lerp(top.G, bottom.G, t),
+			B: lerp(top.B, bottom.B, t),
+			A: 0xff,
+		}
+		draw.Draw(img, image.Rect(0, y, img.Bounds().Dx(), y+1), image.NewUniform(c), image.Point{}, draw.Src)
+	}
+}
+
+func lerp(a, b uint8, t float64) uint8 {
+	return uint8(float64(a) + (float64(b)-float64(a))*t)
+}
@@ -0,0 +1,131 @@
+package ogimage
+
+import (
+	"bytes"
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"kuaiyu/internal/config"
+	"kuaiyu/internal/database"
+	"kuaiyu/internal/model"
+	"kuaiyu/pkg/constants"
+	"kuaiyu/pkg/cos"
+)
+
+// ===========================================
+// 文章分享图
+// ===========================================
+
+// objectPrefix COS 中分享图的目录（位于上传目录 uploads/ 下）
+const objectPrefix = "og/"
+
+// objectKey 分享图在上传目录下的路径：og/<文章 ID>-<卡片哈希>.png
+// 路径包含文章 ID，内容相同的两篇文章各自持有一个对象，删除或更新一篇不会影响另一篇
+func objectKey(postID uint, hash string) string {
+	return objectPrefix + strconv.FormatUint(uint64(postID), 10) + "-" + hash + ".png"
+}
+
+// URL 文章分享图的访问地址
+func URL(slug string) string {
+	return config.Get().Site.URL + "/api/og/" + url.PathEscape(slug) + ".png"
+}
+
+// CardForPost 文章对应的卡片内容，post 需预加载标签
+func CardForPost(post *model.Post) Card {
+	card := Card{
+		Title:    post.Title,
+		SiteName: config.Get().Site.Name,
+		Date:     post.CreatedAt,
+	}
+	if post.PublishedAt != nil {
+		card.Date = *post.PublishedAt
+	}
+	for _, tag := range post.Tags {
+		card.Tags = append(card.Tags, tag.Name)
+	}
+	return card
+}
+
+// Get 获取文章分享图
+// 已缓存且内容未变化时只返回 COS 地址（data 为 nil）；否则重新生成并上传，
+// COS 不可用时仍返回生成的图片，由调用方直接输出
+func Get(post *model.Post) (string, []byte, error) {
+	card := CardForPost(post)
+	hash := card.Hash()
+
+	db := database.Get()
+
+	var cached model.OGImage
+	if err := db.Where("post_id = ?", post.ID).Limit(1).Find(&cached).Error; err != nil {
+		log.Printf("Failed to read og image cache for post %d: %v", post.ID, err)
+	}
+	// 旧版本按哈希命名的共享对象不再复用，重新上传到文章自己的路径
+	key := objectKey(post.ID, hash)
+	if cached.ID != 0 && cached.Hash == hash && strings.HasSuffix(cached.URL, key) {
+		return cached.URL, nil, nil
+	}
+
+	data, err := card.Render()
+	if err != nil {
+		return "", nil, err
+	}
+
+	fileURL, err := cos.UploadFile(bytes.NewReader(data), key, "image/png")
+	if err != nil {
+		log.Printf("Failed to upload og image for post %d: %v", post.ID, err)
+		return "", data, nil
+	}
+
+	previous := cached.Hash
+	cached.PostID = post.ID
+	cached.Hash = hash
+	cached.URL = fileURL
+	if err := db.Save(&cached).Error; err != nil {
+		log.Printf("Failed to save og image cache for post %d: %v", post.ID, err)
+	}
+	if previous != "" && previous != hash {
+		removeObject(post.ID, previous)
+	}
+
+	return fileURL, data, nil
+}
+
+// Refresh 在后台重新生成文章分享图（标题或标签变化后调用）
+// 只处理已发布且没有封面的文章，内容未变化时不会重新生成
+func Refresh(post *model.Post) {
+	if post.CoverImage != "" || post.Status != string(constants.PostStatusPublished) {
+		return
+	}
+
+	go func() {
+		if _, _, err := Get(post); err != nil {
+			log.Printf("Failed to refresh og image for post %d: %v", post.ID, err)
+		}
+	}()
+}
+
+// Delete 删除文章分享图（文章删除时调用）
+func Delete(postID uint) {
+	db := database.Get()
+
+	var cached model.OGImage
+	if err := db.Where("post_id = ?", postID).Limit(1).Find(&cached).Error; err != nil || cached.ID == 0 {
+		return
+	}
+	if err := db.Delete(&cached).Error; err != nil {
+		log.Printf("Failed to delete og image cache for post %d: %v", postID, err)
+		return
+	}
+	removeObject(postID, cached.Hash)
+}
+
+// removeObject 删除 COS 中文章自己的旧图片，失败仅记录日志
+// 旧版本按哈希命名的对象可能被其他文章共用，不会被删除
+func removeObject(postID uint, hash string) {
+	key := "uploads/" + objectKey(postID, hash)
+	if err := cos.DeleteFile(key); err != nil {
+		log.Printf("Failed to delete og image %s: %v", key, err)
+	}
+}
@@ -0,0 +1,241 @@
+// Package outbox 可重试的后台发件箱
+// 待发送的记录先写入数据库，由后台协程周期性扫描到期记录并调用发送回调；
+// 失败的记录按指数退避重新排队，直到成功或用尽重试次数。邮件通知和搜索引擎推送共用这一实现
+package outbox
+
+import (
+	"log"
+	"reflect"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"kuaiyu/internal/database"
+)
+
+// ===========================================
+// 配置
+// ===========================================
+
+const (
+	// batchSize 每轮最多发送的记录数
+	batchSize = 50
+	// leaseDuration 发送期间占用记录的时长，实例在发送中途退出时记录在此之后重新可取
+	leaseDuration = 5 * time.Minute
+	// maxRetryDelay 重试等待时间上限
+	maxRetryDelay = 6 * time.Hour
+	// maxErrorLength 记录的错误信息最大长度
+	maxErrorLength = 500
+	// defaultInterval 默认扫描间隔
+	defaultInterval = 30 * time.Second
+)
+
+// Options 发件箱配置
+// 记录表需要包含 id、status、attempts、next_attempt_at 和 error 列
+type Options struct {
+	Name        string        // 日志中的名称
+	Model       interface{}   // 记录表对应的模型，如 &model.EmailOutbox{}
+	Pending     string        // 等待发送或等待重试的状态
+	Succeeded   string        // 发送成功的状态
+	Failed      string        // 重试次数用尽或不可重试的状态
+	Interval    time.Duration // 扫描间隔
+	MaxAttempts int           // 最多发送次数（含首次）
+	RetryDelay  time.Duration // 首次重试的等待时间，之后按指数退避
+	Wake        *Signal       // 写入新记录后用于立即唤醒
+}
+
+// Result 一次发送的结果
+type Result struct {
+	Err       error                  // 失败原因，nil 表示发送成功
+	Permanent bool                   // 失败不会因重试而成功，直接标记为失败
+	Updates   map[string]interface{} // 需要一并保存的其他字段
+}
+
+// SendFunc 发送一条记录，attempt 为本次是第几次发送（从 1 开始）
+type SendFunc func(id uint, attempt int) Result
+
+// ===========================================
+// 唤醒信号
+// ===========================================
+
+// Signal 唤醒信号，多次唤醒在处理前合并为一次
+type Signal struct {
+	ch chan struct{}
+}
+
+// NewSignal 创建唤醒信号
+func NewSignal() *Signal {
+	return &Signal{ch: make(chan struct{}, 1)}
+}
+
+// Notify 发出唤醒信号
+func (s *Signal) Notify() {
+	select {
+	case s.ch <- struct{}{}:
+	default:
+	}
+}
+
+// C 接收唤醒信号的通道，未设置时返回 nil（永不就绪）
+func (s *Signal) C() <-chan struct{} {
+	if s == nil {
+		return nil
+	}
+	return s.ch
+}
+
+// ===========================================
+// 后台协程
+// ===========================================
+
+// Worker 发件箱后台协程
+// 周期性地发送到期的记录；收到唤醒信号后立即扫描
+type Worker struct {
+	db   *gorm.DB
+	opts Options
+	send SendFunc
+	stop chan struct{}
+	once sync.Once
+}
+
+// NewWorker 创建发件箱后台协程
+func NewWorker(opts Options, send SendFunc) *Worker {
+	if opts.Interval <= 0 {
+		opts.Interval = defaultInterval
+	}
+	return &Worker{
+		db:   database.Get(),
+		opts: opts,
+		send: send,
+		stop: make(chan struct{}),
+	}
+}
+
+// Start 启动发送协程
+func (w *Worker) Start() {
+	go func() {
+		ticker := time.NewTicker(w.opts.Interval)
+		defer ticker.Stop()
+
+		w.RunOnce()
+		for {
+			select {
+			case <-ticker.C:
+				w.RunOnce()
+			case <-w.opts.Wake.C():
+				w.RunOnce()
+			case <-w.stop:
+				return
+			}
+		}
+	}()
+
+	log.Printf("%s started (interval: %s)", w.opts.Name, w.opts.Interval)
+}
+
+// Stop 停止发送协程
+func (w *Worker) Stop() {
+	w.once.Do(func() {
+		close(w.stop)
+	})
+}
+
+// model 记录表模型的新实例
+// 每次查询使用新实例，避免 Updates 回写字段后影响后续查询
+func (w *Worker) model() interface{} {
+	return reflect.New(reflect.TypeOf(w.opts.Model).Elem()).Interface()
+}
+
+// job 待发送记录的状态
+type job struct {
+	ID       uint
+	Attempts int
+}
+
+// RunOnce 发送一轮到期的记录，返回本实例发送的记录数
+func (w *Worker) RunOnce() int {
+	var jobs []job
+	err := w.db.Model(w.model()).
+		Select("id, attempts").
+		Where("status = ? AND next_attempt_at <= ?", w.opts.Pending, time.Now()).
+		Order("next_attempt_at ASC").
+		Limit(batchSize).
+		Scan(&jobs).Error
+	if err != nil {
+		log.Printf("%s: failed to query due entries: %v", w.opts.Name, err)
+		return 0
+	}
+
+	sent := 0
+	for i := range jobs {
+		if w.claim(&jobs[i]) {
+			w.deliver(&jobs[i])
+			sent++
+		}
+	}
+	return sent
+}
+
+// claim 占用记录：发送次数加一并推迟下次尝试时间
+// 条件更新保证多副本同时扫描时只有一个实例发送
+func (w *Worker) claim(j *job) bool {
+	result := w.db.Model(w.model()).
+		Where("id = ? AND status = ? AND attempts = ?", j.ID, w.opts.Pending, j.Attempts).
+		Updates(map[string]interface{}{
+			"attempts":        j.Attempts + 1,
+			"next_attempt_at": time.Now().Add(leaseDuration),
+		})
+	if result.Error != nil {
+		log.Printf("%s: failed to claim entry %d: %v", w.opts.Name, j.ID, result.Error)
+		return false
+	}
+	if result.RowsAffected == 0 {
+		return false
+	}
+	j.Attempts++
+	return true
+}
+
+// deliver 发送记录并保存结果，可重试的失败按指数退避重新排队
+func (w *Worker) deliver(j *job) {
+	result := w.send(j.ID, j.Attempts)
+
+	updates := map[string]interface{}{"error": ""}
+	for column, value := range result.Updates {
+		updates[column] = value
+	}
+	switch {
+	case result.Err == nil:
+		updates["status"] = w.opts.Succeeded
+		updates["next_attempt_at"] = nil
+	case !result.Permanent && j.Attempts < w.opts.MaxAttempts:
+		updates["error"] = truncate(result.Err.Error(), maxErrorLength)
+		updates["next_attempt_at"] = time.Now().Add(RetryDelay(w.opts.RetryDelay, j.Attempts))
+	default:
+		updates["status"] = w.opts.Failed
+		updates["error"] = truncate(result.Err.Error(), maxErrorLength)
+		updates["next_attempt_at"] = nil
+	}
+
+	if err := w.db.Model(w.model()).Where("id = ?", j.ID).Updates(updates).Error; err != nil {
+		log.Printf("%s: failed to save entry %d result: %v", w.opts.Name, j.ID, err)
+	}
+}
+
+// RetryDelay 第 attempts 次发送失败后的等待时间：从 base 开始每次翻倍，不超过 6 小时
+func RetryDelay(base time.Duration, attempts int) time.Duration {
+	delay := base
+	for i := 1; i < attempts && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxRetryDelay)
+}
+
+// truncate 按字符截断
+func truncate(s string, limit int) string {
+	runes := []rune(s)
+	if len(runes) <= limit {
+		return s
+	}
+	return string(runes[:limit])
+}
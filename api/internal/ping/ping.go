@@ -0,0 +1,193 @@
+// Package ping 搜索引擎推送
+// 内容发布、更新或删除后，通过 IndexNow 协议提交变化的地址，并请求 sitemap ping 地址。
+// 推送任务写入 ping_logs 表后由后台队列发送，失败后按指数退避重试，结果可在管理后台查看
+package ping
+
+import (
+	"log"
+	"strconv"
+	"time"
+
+	"kuaiyu/internal/config"
+	"kuaiyu/internal/database"
+	"kuaiyu/internal/model"
+	"kuaiyu/pkg/constants"
+)
+
+// ===========================================
+// 内容地址
+// ===========================================
+
+// PostURL 文章地址
+func PostURL(slug string) string {
+	return config.Get().Site.URL + "/blog/" + slug
+}
+
+// LifeURL 生活记录地址
+func LifeURL(id uint) string {
+	return config.Get().Site.URL + "/life/" + strconv.FormatUint(uint64(id), 10)
+}
+
+// SitemapURL 站点地图地址
+func SitemapURL() string {
+	return config.Get().Site.URL + "/api/sitemap.xml"
+}
+
+// KeyLocation IndexNow 密钥文件地址
+// 密钥文件必须位于站点根目录，才能提交全站地址
+func KeyLocation() string {
+	return config.Get().Site.URL + "/indexnow-key.txt"
+}
+
+// PublicPost 文章是否对搜索引擎公开（已发布且没有隐藏）
+func PublicPost(post *model.Post) bool {
+	return published(post.Status, post.PublishedAt) &&
+		post.GetVisibility() != string(constants.PostVisibilityUnlisted)
+}
+
+// PublicLife 生活记录是否对搜索引擎公开
+func PublicLife(record *model.LifeRecord) bool {
+	return published(record.Status, record.PublishedAt)
+}
+
+// published 是否已发布且发布时间已到
+func published(status string, publishedAt *time.Time) bool {
+	if status != string(constants.PostStatusPublished) {
+		return false
+	}
+	return publishedAt == nil || !publishedAt.After(time.Now())
+}
+
+// ===========================================
+// 提交
+// ===========================================
+
+// Notify 提交变化的地址（绝对地址）
+// 为每个 IndexNow 地址和 sitemap ping 地址各创建一条推送任务；未启用时忽略
+func Notify(reason string, urls ...string) {
+	cfg := config.Get().Ping
+	if !cfg.Enabled {
+		return
+	}
+	urls = unique(urls)
+	if len(urls) == 0 {
+		return
+	}
+
+	db := database.Get()
+	now := time.Now()
+
+	var logs []model.PingLog
+	if cfg.IndexNowKey != "" {
+		for _, endpoint := range cfg.IndexNowEndpoints {
+			entry := model.PingLog{
+				Kind:          model.PingKindIndexNow,
+				Endpoint:      endpoint,
+				Reason:        reason,
+				Status:        model.PingStatusPending,
+				NextAttemptAt: &now,
+			}
+			entry.SetURLs(urls)
+			logs = append(logs, entry)
+		}
+	}
+	for _, endpoint := range cfg.SitemapEndpoints {
+		// 同一地址已有尚未发送的 ping 时无需重复提交
+		var count int64
+		if err := db.Model(&model.PingLog{}).
+			Where("kind = ? AND endpoint = ? AND status = ? AND attempts = 0",
+				model.PingKindSitemap, endpoint, model.PingStatusPending).
+			Count(&count).Error; err == nil && count > 0 {
+			continue
+		}
+
+		entry := model.PingLog{
+			Kind:          model.PingKindSitemap,
+			Endpoint:      endpoint,
+			Reason:        reason,
+			Status:        model.PingStatusPending,
+			NextAttemptAt: &now,
+		}
+		entry.SetURLs([]string{SitemapURL()})
+		logs = append(logs, entry)
+	}
+	if len(logs) == 0 {
+		return
+	}
+
+	if err := db.Create(&logs).Error; err != nil {
+		log.Printf("Failed to queue search engine ping: %v", err)
+		return
+	}
+	Wake()
+}
+
+// NotifyPost 文章发布、更新或删除后提交地址
+// oldSlug 为变更前的 slug，变更后旧地址也一并提交
+func NotifyPost(reason string, post *model.Post, oldSlug string) {
+	urls := []string{PostURL(post.Slug)}
+	if oldSlug != "" && oldSlug != post.Slug {
+		urls = append(urls, PostURL(oldSlug))
+	}
+	Notify(reason, urls...)
+}
+
+// ChangeReason 内容保存后的推送原因
+// 由不公开变为公开视为发布，由公开变为不公开视为删除，前后都不公开时无需推送
+func ChangeReason(wasPublic, isPublic bool) (string, bool) {
+	switch {
+	case wasPublic && isPublic:
+		return model.PingReasonUpdate, true
+	case isPublic:
+		return model.PingReasonPublish, true
+	case wasPublic:
+		return model.PingReasonDelete, true
+	}
+	return "", false
+}
+
+// OnPublish 定时发布后的回调，提交新发布的内容
+func OnPublish(targetType string, id uint) {
+	db := database.Get()
+	switch targetType {
+	case "post":
+		var post model.Post
+		if err := db.First(&post, id).Error; err == nil && PublicPost(&post) {
+			NotifyPost(model.PingReasonPublish, &post, "")
+		}
+	case "life":
+		var record model.LifeRecord
+		if err := db.First(&record, id).Error; err == nil && PublicLife(&record) {
+			Notify(model.PingReasonPublish, LifeURL(record.ID))
+		}
+	}
+}
+
+// Retry 将推送任务重新加入队列，重新计算发送次数
+func Retry(entry *model.PingLog) error {
+	now := time.Now()
+	err := database.Get().Model(entry).Updates(map[string]interface{}{
+		"status":          model.PingStatusPending,
+		"attempts":        0,
+		"next_attempt_at": now,
+	}).Error
+	if err != nil {
+		return err
+	}
+	Wake()
+	return nil
+}
+
+// unique 去除空地址和重复地址，保持顺序
+func unique(urls []string) []string {
+	seen := make(map[string]bool, len(urls))
+	result := make([]string, 0, len(urls))
+	for _, u := range urls {
+		if u == "" || seen[u] {
+			continue
+		}
+		seen[u] = true
+		result = append(result, u)
+	}
+	return result
+}
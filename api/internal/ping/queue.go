@@ -0,0 +1,177 @@
+package ping
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+
+	"gorm.io/gorm"
+	"kuaiyu/internal/config"
+	"kuaiyu/internal/database"
+	"kuaiyu/internal/model"
+	"kuaiyu/internal/outbox"
+)
+
+// ===========================================
+// 后台队列
+// ===========================================
+
+// wake 唤醒队列立即发送新提交的任务
+var wake = outbox.NewSignal()
+
+// Wake 唤醒队列
+func Wake() {
+	wake.Notify()
+}
+
+// Queue 推送队列
+// 周期性地发送到期的推送任务；提交新任务后会被立即唤醒
+type Queue struct {
+	*outbox.Worker
+	db     *gorm.DB
+	client *http.Client
+}
+
+// NewQueue 创建推送队列
+func NewQueue(interval time.Duration) *Queue {
+	cfg := config.Get().Ping
+	q := &Queue{
+		db:     database.Get(),
+		client: &http.Client{Timeout: cfg.Timeout},
+	}
+	q.Worker = outbox.NewWorker(outbox.Options{
+		Name:        "Search engine ping queue",
+		Model:       &model.PingLog{},
+		Pending:     model.PingStatusPending,
+		Succeeded:   model.PingStatusSuccess,
+		Failed:      model.PingStatusFailed,
+		Interval:    interval,
+		MaxAttempts: cfg.MaxAttempts,
+		RetryDelay:  cfg.RetryDelay,
+		Wake:        wake,
+	}, q.deliver)
+	return q
+}
+
+// deliver 发送一个推送任务，只有可重试的失败会重新排队
+func (q *Queue) deliver(id uint, attempt int) outbox.Result {
+	var entry model.PingLog
+	if err := q.db.First(&entry, id).Error; err != nil {
+		return outbox.Result{Err: err}
+	}
+
+	statusCode, err := q.send(&entry)
+	result := outbox.Result{
+		Err: err,
+		Updates: map[string]interface{}{
+			"status_code": statusCode,
+			"sent_at":     time.Now(),
+		},
+	}
+	if err != nil {
+		result.Permanent = !retryable(statusCode)
+		log.Printf("Search engine ping %d to %s failed (attempt %d): %v", id, entry.Endpoint, attempt, err)
+	}
+	return result
+}
+
+// retryable 失败是否可以重试：网络错误、限流和服务端错误可以重试，其余客户端错误不会因重试而成功
+func retryable(statusCode int) bool {
+	return statusCode == 0 || statusCode == http.StatusTooManyRequests || statusCode >= 500
+}
+
+// ===========================================
+// 发送
+// ===========================================
+
+// indexNowRequest IndexNow 提交请求体
+type indexNowRequest struct {
+	Host        string   `json:"host"`
+	Key         string   `json:"key"`
+	KeyLocation string   `json:"keyLocation"`
+	URLList     []string `json:"urlList"`
+}
+
+// send 发送一次推送，返回 HTTP 状态码（请求失败时为 0）
+func (q *Queue) send(entry *model.PingLog) (int, error) {
+	var req *http.Request
+	var err error
+	switch entry.Kind {
+	case model.PingKindIndexNow:
+		req, err = newIndexNowRequest(entry)
+	case model.PingKindSitemap:
+		req, err = newSitemapRequest(entry)
+	default:
+		err = fmt.Errorf("unknown ping kind %q", entry.Kind)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	resp, err := q.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 256))
+		return resp.StatusCode, fmt.Errorf("unexpected status %s: %s", resp.Status, bytes.TrimSpace(body))
+	}
+	io.Copy(io.Discard, resp.Body)
+	return resp.StatusCode, nil
+}
+
+// newIndexNowRequest 按 IndexNow 协议批量提交地址
+func newIndexNowRequest(entry *model.PingLog) (*http.Request, error) {
+	cfg := config.Get()
+	if cfg.Ping.IndexNowKey == "" {
+		return nil, errors.New("indexnow key is not configured")
+	}
+
+	site, err := url.Parse(cfg.Site.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid site url: %w", err)
+	}
+
+	body, err := json.Marshal(indexNowRequest{
+		Host:        site.Host,
+		Key:         cfg.Ping.IndexNowKey,
+		KeyLocation: KeyLocation(),
+		URLList:     entry.URLList(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, entry.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	return req, nil
+}
+
+// newSitemapRequest 请求 sitemap ping 地址，站点地图地址作为 sitemap 参数
+func newSitemapRequest(entry *model.PingLog) (*http.Request, error) {
+	endpoint, err := url.Parse(entry.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ping endpoint: %w", err)
+	}
+
+	urls := entry.URLList()
+	if len(urls) == 0 {
+		return nil, errors.New("no sitemap url")
+	}
+	query := endpoint.Query()
+	query.Set("sitemap", urls[0])
+	endpoint.RawQuery = query.Encode()
+
+	return http.NewRequest(http.MethodGet, endpoint.String(), nil)
+}
@@ -0,0 +1,285 @@
+package ping
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+	"kuaiyu/internal/config"
+	"kuaiyu/internal/database/dbtest"
+	"kuaiyu/internal/model"
+)
+
+// ===========================================
+// 测试环境
+// ===========================================
+
+// request 测试服务器收到的请求
+type request struct {
+	method      string
+	contentType string
+	query       map[string][]string
+	body        []byte
+}
+
+// endpoint 记录请求并按顺序返回给定状态码的测试服务器，状态码用完后重复最后一个
+type endpoint struct {
+	*httptest.Server
+	mu       sync.Mutex
+	statuses []int
+	requests []request
+}
+
+func newEndpoint(t *testing.T, statuses ...int) *endpoint {
+	t.Helper()
+	e := &endpoint{statuses: statuses}
+	e.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		e.requests = append(e.requests, request{
+			method:      r.Method,
+			contentType: r.Header.Get("Content-Type"),
+			query:       r.URL.Query(),
+			body:        body,
+		})
+		status := e.statuses[min(len(e.requests), len(e.statuses))-1]
+		w.WriteHeader(status)
+		io.WriteString(w, http.StatusText(status))
+	}))
+	t.Cleanup(e.Close)
+	return e
+}
+
+func (e *endpoint) received() []request {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]request(nil), e.requests...)
+}
+
+// setup 启动内存数据库并把推送配置指向测试服务器，测试结束后恢复配置
+func setup(t *testing.T, indexNow, sitemap []string) *gorm.DB {
+	t.Helper()
+	db := dbtest.Open(t, &model.PingLog{})
+
+	cfg := config.Get()
+	site, ping := cfg.Site, cfg.Ping
+	t.Cleanup(func() { cfg.Site, cfg.Ping = site, ping })
+
+	cfg.Site.URL = "https://blog.example.com"
+	cfg.Ping = config.PingConfig{
+		Enabled:           true,
+		IndexNowKey:       "0123456789abcdef",
+		IndexNowEndpoints: indexNow,
+		SitemapEndpoints:  sitemap,
+		MaxAttempts:       3,
+		RetryDelay:        time.Minute,
+		Timeout:           5 * time.Second,
+	}
+	return db
+}
+
+// entries 按 ID 顺序返回全部推送记录
+func entries(t *testing.T, db *gorm.DB) []model.PingLog {
+	t.Helper()
+	var logs []model.PingLog
+	if err := db.Order("id").Find(&logs).Error; err != nil {
+		t.Fatal(err)
+	}
+	return logs
+}
+
+// due 让等待重试的记录立即到期
+func due(t *testing.T, db *gorm.DB) {
+	t.Helper()
+	err := db.Model(&model.PingLog{}).
+		Where("status = ?", model.PingStatusPending).
+		Update("next_attempt_at", time.Now().Add(-time.Second)).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// ===========================================
+// 请求内容
+// ===========================================
+
+func TestIndexNowRequest(t *testing.T) {
+	server := newEndpoint(t, http.StatusOK)
+	db := setup(t, []string{server.URL + "/indexnow"}, nil)
+
+	urls := []string{PostURL("hello"), PostURL("old-hello"), LifeURL(7)}
+	Notify(model.PingReasonUpdate, append(urls, "", PostURL("hello"))...)
+	if sent := NewQueue(time.Hour).RunOnce(); sent != 1 {
+		t.Fatalf("RunOnce sent %d, want 1", sent)
+	}
+
+	requests := server.received()
+	if len(requests) != 1 {
+		t.Fatalf("received %d requests, want 1", len(requests))
+	}
+	req := requests[0]
+	if req.method != http.MethodPost || req.contentType != "application/json; charset=utf-8" {
+		t.Errorf("request %s %q, want POST application/json", req.method, req.contentType)
+	}
+
+	var body indexNowRequest
+	if err := json.Unmarshal(req.body, &body); err != nil {
+		t.Fatalf("decode body %s: %v", req.body, err)
+	}
+	want := indexNowRequest{
+		Host:        "blog.example.com",
+		Key:         "0123456789abcdef",
+		KeyLocation: "https://blog.example.com/indexnow-key.txt",
+		URLList:     urls,
+	}
+	if got, _ := json.Marshal(body); string(got) != mustJSON(t, want) {
+		t.Errorf("body = %s, want %s", got, mustJSON(t, want))
+	}
+
+	logs := entries(t, db)
+	if len(logs) != 1 {
+		t.Fatalf("%d ping logs, want 1", len(logs))
+	}
+	entry := logs[0]
+	if entry.Status != model.PingStatusSuccess || entry.StatusCode != http.StatusOK || entry.Attempts != 1 {
+		t.Errorf("entry status %s code %d attempts %d, want success 200 1", entry.Status, entry.StatusCode, entry.Attempts)
+	}
+	if entry.SentAt == nil || entry.NextAttemptAt != nil || entry.Error != "" {
+		t.Errorf("entry sent_at %v next_attempt_at %v error %q", entry.SentAt, entry.NextAttemptAt, entry.Error)
+	}
+}
+
+func TestSitemapPing(t *testing.T) {
+	server := newEndpoint(t, http.StatusOK)
+	db := setup(t, nil, []string{server.URL + "/ping?source=kuaiyu"})
+
+	Notify(model.PingReasonPublish, PostURL("hello"))
+	// 尚未发送的 sitemap ping 不重复提交
+	Notify(model.PingReasonPublish, PostURL("world"))
+	if sent := NewQueue(time.Hour).RunOnce(); sent != 1 {
+		t.Fatalf("RunOnce sent %d, want 1", sent)
+	}
+
+	requests := server.received()
+	if len(requests) != 1 {
+		t.Fatalf("received %d requests, want 1", len(requests))
+	}
+	req := requests[0]
+	if req.method != http.MethodGet {
+		t.Errorf("method = %s, want GET", req.method)
+	}
+	if got := req.query["sitemap"]; len(got) != 1 || got[0] != "https://blog.example.com/api/sitemap.xml" {
+		t.Errorf("sitemap query = %q", got)
+	}
+	if got := req.query["source"]; len(got) != 1 || got[0] != "kuaiyu" {
+		t.Errorf("existing query lost: source = %q", got)
+	}
+	if logs := entries(t, db); len(logs) != 1 || logs[0].Status != model.PingStatusSuccess {
+		t.Errorf("ping logs = %+v, want one successful entry", logs)
+	}
+}
+
+// ===========================================
+// 重试
+// ===========================================
+
+func TestRetryWithBackoff(t *testing.T) {
+	server := newEndpoint(t, http.StatusServiceUnavailable, http.StatusTooManyRequests, http.StatusOK)
+	db := setup(t, []string{server.URL}, nil)
+	queue := NewQueue(time.Hour)
+
+	Notify(model.PingReasonPublish, PostURL("hello"))
+
+	for attempt, wantCode := range []int{http.StatusServiceUnavailable, http.StatusTooManyRequests} {
+		start := time.Now()
+		if sent := queue.RunOnce(); sent != 1 {
+			t.Fatalf("attempt %d: RunOnce sent %d, want 1", attempt+1, sent)
+		}
+		entry := entries(t, db)[0]
+		if entry.Status != model.PingStatusPending || entry.Attempts != attempt+1 || entry.StatusCode != wantCode {
+			t.Fatalf("attempt %d: status %s attempts %d code %d, want pending %d %d",
+				attempt+1, entry.Status, entry.Attempts, entry.StatusCode, attempt+1, wantCode)
+		}
+		if !strings.Contains(entry.Error, http.StatusText(wantCode)) {
+			t.Errorf("attempt %d: error = %q", attempt+1, entry.Error)
+		}
+
+		// 第 n 次失败后等待 RetryDelay * 2^(n-1)
+		delay := time.Minute << attempt
+		if entry.NextAttemptAt == nil ||
+			entry.NextAttemptAt.Before(start.Add(delay-time.Second)) ||
+			entry.NextAttemptAt.After(time.Now().Add(delay+time.Second)) {
+			t.Errorf("attempt %d: next_attempt_at = %v, want about %s later", attempt+1, entry.NextAttemptAt, delay)
+		}
+
+		// 未到期的记录不会发送
+		if sent := queue.RunOnce(); sent != 0 {
+			t.Fatalf("attempt %d: sent %d entries before they were due", attempt+1, sent)
+		}
+		due(t, db)
+	}
+
+	if sent := queue.RunOnce(); sent != 1 {
+		t.Fatalf("final attempt: RunOnce sent %d, want 1", sent)
+	}
+	entry := entries(t, db)[0]
+	if entry.Status != model.PingStatusSuccess || entry.Attempts != 3 || entry.Error != "" {
+		t.Errorf("final: status %s attempts %d error %q, want success 3 with no error", entry.Status, entry.Attempts, entry.Error)
+	}
+	if n := len(server.received()); n != 3 {
+		t.Errorf("received %d requests, want 3", n)
+	}
+}
+
+func TestFailedAfterMaxAttempts(t *testing.T) {
+	server := newEndpoint(t, http.StatusInternalServerError)
+	db := setup(t, []string{server.URL}, nil)
+	queue := NewQueue(time.Hour)
+
+	Notify(model.PingReasonPublish, PostURL("hello"))
+	for i := 0; i < 5; i++ {
+		queue.RunOnce()
+		due(t, db)
+	}
+
+	entry := entries(t, db)[0]
+	if entry.Status != model.PingStatusFailed || entry.Attempts != 3 || entry.StatusCode != http.StatusInternalServerError {
+		t.Errorf("status %s attempts %d code %d, want failed 3 500", entry.Status, entry.Attempts, entry.StatusCode)
+	}
+	if entry.NextAttemptAt != nil || !strings.Contains(entry.Error, "500") {
+		t.Errorf("next_attempt_at %v error %q, want nil and the last status", entry.NextAttemptAt, entry.Error)
+	}
+	if n := len(server.received()); n != 3 {
+		t.Errorf("received %d requests, want 3", n)
+	}
+}
+
+func TestClientErrorIsNotRetried(t *testing.T) {
+	server := newEndpoint(t, http.StatusUnprocessableEntity)
+	db := setup(t, []string{server.URL}, nil)
+
+	Notify(model.PingReasonPublish, PostURL("hello"))
+	NewQueue(time.Hour).RunOnce()
+
+	entry := entries(t, db)[0]
+	if entry.Status != model.PingStatusFailed || entry.Attempts != 1 || entry.NextAttemptAt != nil {
+		t.Errorf("status %s attempts %d next_attempt_at %v, want failed after one attempt",
+			entry.Status, entry.Attempts, entry.NextAttemptAt)
+	}
+}
+
+func mustJSON(t *testing.T, v interface{}) string {
+	t.Helper()
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
@@ -0,0 +1,467 @@
+// Package related 相关文章推荐
+// 综合标签重合度、正文 TF-IDF 相似度和新鲜度为每篇文章预计算相关文章，读取时只需查表
+package related
+
+import (
+	"log"
+	"math"
+	"sort"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+	"kuaiyu/internal/database"
+	"kuaiyu/internal/model"
+	"kuaiyu/internal/repository"
+	"kuaiyu/pkg/tokenizer"
+	"kuaiyu/pkg/utils"
+)
+
+// ===========================================
+// 打分参数
+// ===========================================
+
+const (
+	// tagWeight 标签重合度权重
+	tagWeight = 0.5
+	// textWeight 正文相似度权重
+	textWeight = 0.4
+	// recencyWeight 新鲜度权重
+	recencyWeight = 0.1
+	// recencyHalfLife 新鲜度半衰期
+	recencyHalfLife = 180 * 24 * time.Hour
+	// titleTermBoost 标题词项的权重
+	titleTermBoost = 2
+	// minTextScore 无共同标签时，正文相似度的最低门槛
+	minTextScore = 0.05
+	// maxStored 每篇文章保存的相关文章数量
+	maxStored = 20
+)
+
+// ===========================================
+// 后台刷新
+// ===========================================
+
+var (
+	mu      sync.Mutex
+	running bool
+	full    bool          // 待执行全量计算
+	pending map[uint]bool // 待增量更新的文章
+)
+
+// Refresh 在后台重新计算相关文章
+// 传入文章 ID 时只增量更新这些文章及受其影响的文章（文章保存或发布时调用）；
+// 不传时全量重算（标签删除、批量导入等影响面较大的变更）。
+// 计算进行中再次调用会在本轮结束后合并为一次计算
+func Refresh(ids ...uint) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if len(ids) == 0 {
+		full = true
+	}
+	for _, id := range ids {
+		if pending == nil {
+			pending = make(map[uint]bool)
+		}
+		pending[id] = true
+	}
+	if running {
+		return
+	}
+	running = true
+
+	go func() {
+		for {
+			mu.Lock()
+			rebuild, changed := full, pending
+			full, pending = false, nil
+			if !rebuild && len(changed) == 0 {
+				running = false
+				mu.Unlock()
+				return
+			}
+			mu.Unlock()
+
+			if rebuild {
+				if _, err := Rebuild(); err != nil {
+					log.Printf("Failed to rebuild related posts: %v", err)
+				}
+				continue
+			}
+			ids := make([]uint, 0, len(changed))
+			for id := range changed {
+				ids = append(ids, id)
+			}
+			if _, err := Update(ids); err != nil {
+				log.Printf("Failed to update related posts of %v: %v", ids, err)
+			}
+		}
+	}()
+}
+
+// OnPublish 定时发布后的回调
+func OnPublish(targetType string, id uint) {
+	if targetType == "post" {
+		Refresh(id)
+	}
+}
+
+// EnsureComputed 尚未计算过时执行一次全量计算（用于首次部署）
+func EnsureComputed() {
+	var count int64
+	if err := database.Get().Model(&model.RelatedPost{}).Count(&count).Error; err != nil {
+		log.Printf("Failed to check related posts: %v", err)
+		return
+	}
+	if count == 0 {
+		Refresh()
+	}
+}
+
+// ===========================================
+// 全量计算
+// ===========================================
+
+// Rebuild 重新计算全部公开文章的相关文章，返回参与计算的文章数
+func Rebuild() (int, error) {
+	db := database.Get()
+
+	posts, err := publicPosts(db)
+	if err != nil {
+		return 0, err
+	}
+
+	rows := Compute(posts, time.Now())
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(&model.RelatedPost{}).Error; err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(rows, 500).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return len(posts), nil
+}
+
+// Compute 计算每篇文章得分最高的相关文章
+func Compute(posts []model.Post, now time.Time) []model.RelatedPost {
+	s := newScorer(posts, now)
+
+	var rows []model.RelatedPost
+	for i := range posts {
+		rows = append(rows, s.top(i)...)
+	}
+	return rows
+}
+
+// ===========================================
+// 增量更新
+// ===========================================
+
+// Update 只重新计算 ids 对应文章的相关文章，以及列表受其影响的文章：
+// 原列表中包含这些文章的（得分变化、文章已不公开或已删除），和这些文章的新得分足以进入列表的。
+// 其余文章的列表保持不变；正文变化对其他文章词项权重的细微影响留到下次全量计算。
+// 返回重新计算的文章数
+func Update(ids []uint) (int, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	db := database.Get()
+
+	posts, err := publicPosts(db)
+	if err != nil {
+		return 0, err
+	}
+	s := newScorer(posts, time.Now())
+
+	index := make(map[uint]int, len(posts))
+	for i, post := range posts {
+		index[post.ID] = i
+	}
+	changed := make(map[uint]bool, len(ids))
+	for _, id := range ids {
+		changed[id] = true
+	}
+
+	var stored []model.RelatedPost
+	if err := db.Select("post_id", "related_id", "score").Find(&stored).Error; err != nil {
+		return 0, err
+	}
+	lists := make(map[uint][]model.RelatedPost)
+	for _, row := range stored {
+		lists[row.PostID] = append(lists[row.PostID], row)
+	}
+
+	affected := make(map[uint]bool, len(ids))
+	for id := range changed {
+		affected[id] = true
+	}
+	for postID, list := range lists {
+		for _, row := range list {
+			if changed[row.RelatedID] {
+				affected[postID] = true
+				break
+			}
+		}
+	}
+	for j, post := range posts {
+		if affected[post.ID] {
+			continue
+		}
+		list := lists[post.ID]
+		for id := range changed {
+			i, ok := index[id]
+			if !ok {
+				continue
+			}
+			if row, ok := s.pair(j, i); ok && (len(list) < maxStored || row.Score >= lowest(list)) {
+				affected[post.ID] = true
+				break
+			}
+		}
+	}
+
+	targets := make([]uint, 0, len(affected))
+	var rows []model.RelatedPost
+	for id := range affected {
+		targets = append(targets, id)
+		if i, ok := index[id]; ok {
+			rows = append(rows, s.top(i)...)
+		}
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("post_id IN ?", targets).Delete(&model.RelatedPost{}).Error; err != nil {
+			return err
+		}
+		// 已不公开的文章不再出现在任何列表中
+		var hidden []uint
+		for id := range changed {
+			if _, ok := index[id]; !ok {
+				hidden = append(hidden, id)
+			}
+		}
+		if len(hidden) > 0 {
+			if err := tx.Where("related_id IN ?", hidden).Delete(&model.RelatedPost{}).Error; err != nil {
+				return err
+			}
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(rows, 500).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return len(targets), nil
+}
+
+// publicPosts 参与计算的文章：已发布且公开列出，预加载标签
+func publicPosts(db *gorm.DB) ([]model.Post, error) {
+	var posts []model.Post
+	err := db.Scopes(repository.PublishedScope("posts"), repository.ListedScope("posts")).
+		Preload("Tags").
+		Order("id ASC").
+		Find(&posts).Error
+	return posts, err
+}
+
+// lowest 列表中的最低得分
+func lowest(list []model.RelatedPost) float64 {
+	score := math.Inf(1)
+	for _, row := range list {
+		score = math.Min(score, row.Score)
+	}
+	return score
+}
+
+// ===========================================
+// 打分
+// ===========================================
+
+// scorer 两两打分所需的预处理结果
+type scorer struct {
+	posts   []model.Post
+	vectors []vector
+	tagSets []map[uint]bool
+	recency []float64
+	now     time.Time
+}
+
+// newScorer 预处理文章的词项向量、标签集合和新鲜度
+func newScorer(posts []model.Post, now time.Time) *scorer {
+	s := &scorer{
+		posts:   posts,
+		vectors: tfidf(posts),
+		tagSets: make([]map[uint]bool, len(posts)),
+		recency: make([]float64, len(posts)),
+		now:     now,
+	}
+
+	for i, post := range posts {
+		s.tagSets[i] = make(map[uint]bool, len(post.Tags))
+		for _, tag := range post.Tags {
+			s.tagSets[i][tag.ID] = true
+		}
+	}
+
+	for i, post := range posts {
+		published := post.CreatedAt
+		if post.PublishedAt != nil {
+			published = *post.PublishedAt
+		}
+		age := now.Sub(published)
+		if age < 0 {
+			age = 0
+		}
+		s.recency[i] = math.Exp2(-float64(age) / float64(recencyHalfLife))
+	}
+
+	return s
+}
+
+// pair 文章 i 推荐文章 j 的得分，相关度不足时返回 false
+func (s *scorer) pair(i, j int) (model.RelatedPost, bool) {
+	tagScore := jaccard(s.tagSets[i], s.tagSets[j])
+	textScore := cosine(s.vectors[i], s.vectors[j])
+	if tagScore == 0 && textScore < minTextScore {
+		return model.RelatedPost{}, false
+	}
+
+	return model.RelatedPost{
+		PostID:       s.posts[i].ID,
+		RelatedID:    s.posts[j].ID,
+		Score:        round(tagWeight*tagScore + textWeight*textScore + recencyWeight*s.recency[j]),
+		TagScore:     round(tagScore),
+		TextScore:    round(textScore),
+		RecencyScore: round(s.recency[j]),
+		UpdatedAt:    s.now,
+	}, true
+}
+
+// top 文章 i 得分最高的相关文章
+func (s *scorer) top(i int) []model.RelatedPost {
+	candidates := make([]model.RelatedPost, 0, len(s.posts))
+	for j := range s.posts {
+		if i == j {
+			continue
+		}
+		if row, ok := s.pair(i, j); ok {
+			candidates = append(candidates, row)
+		}
+	}
+
+	sort.Slice(candidates, func(a, b int) bool {
+		if candidates[a].Score != candidates[b].Score {
+			return candidates[a].Score > candidates[b].Score
+		}
+		return candidates[a].RelatedID > candidates[b].RelatedID
+	})
+	if len(candidates) > maxStored {
+		candidates = candidates[:maxStored]
+	}
+	return candidates
+}
+
+// ===========================================
+// 内部实现
+// ===========================================
+
+// vector 归一化后的稀疏 TF-IDF 向量
+type vector map[string]float64
+
+// tfidf 构建每篇文章的 TF-IDF 向量
+func tfidf(posts []model.Post) []vector {
+	tfs := make([]map[string]int, len(posts))
+	df := make(map[string]int)
+
+	for i, post := range posts {
+		tf := make(map[string]int)
+		for term, n := range tokenizer.Count(tokenizer.Tokenize(post.Title)) {
+			if significant(term) {
+				tf[term] += n * titleTermBoost
+			}
+		}
+		for term, n := range tokenizer.Count(tokenizer.Tokenize(utils.StripMarkdown(post.Content))) {
+			if significant(term) {
+				tf[term] += n
+			}
+		}
+		for term := range tf {
+			df[term]++
+		}
+		tfs[i] = tf
+	}
+
+	n := float64(len(posts))
+	vectors := make([]vector, len(posts))
+	for i, tf := range tfs {
+		v := make(vector, len(tf))
+		var norm float64
+		for term, count := range tf {
+			// 只出现在一篇文章中的词项对相似度没有贡献
+			if df[term] < 2 {
+				continue
+			}
+			w := (1 + math.Log(float64(count))) * math.Log(n/float64(df[term]))
+			if w <= 0 {
+				continue
+			}
+			v[term] = w
+			norm += w * w
+		}
+		norm = math.Sqrt(norm)
+		for term := range v {
+			v[term] /= norm
+		}
+		vectors[i] = v
+	}
+
+	return vectors
+}
+
+// significant 过滤单字词项（中文单字和单个数字信息量过低）
+func significant(term string) bool {
+	return utf8.RuneCountInString(term) > 1
+}
+
+// cosine 两个归一化向量的余弦相似度
+func cosine(a, b vector) float64 {
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	var sum float64
+	for term, w := range a {
+		sum += w * b[term]
+	}
+	return sum
+}
+
+// jaccard 两个标签集合的 Jaccard 系数
+func jaccard(a, b map[uint]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	shared := 0
+	for id := range a {
+		if b[id] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
+
+// round 保留四位小数
+func round(v float64) float64 {
+	return math.Round(v*10000) / 10000
+}
@@ -0,0 +1,212 @@
+package related
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+	"kuaiyu/internal/database/dbtest"
+	"kuaiyu/internal/model"
+	"kuaiyu/pkg/constants"
+)
+
+const (
+	// groupSize 每组文章数，组内文章共用标签和词项，组间互不相关；
+	// 候选数超过 maxStored，列表保存满后才能检验补位
+	groupSize = maxStored + 4
+	// firstID 第一篇文章的 ID；测试用内存数据库按拼接后的值判断联合主键是否重复，
+	// ID 位数相同才不会把 (1, 12) 和 (11, 2) 当作同一行
+	firstID = 10
+)
+
+// seed 创建两组文章并全量计算一次，返回文章（按 ID 升序）
+func seed(t *testing.T) (*gorm.DB, []model.Post) {
+	t.Helper()
+	db := dbtest.Open(t, &model.Post{}, &model.Tag{}, &model.RelatedPost{})
+
+	tags := []model.Tag{{Name: "go", Slug: "go"}, {Name: "rust", Slug: "rust"}, {Name: "misc", Slug: "misc"}}
+	if err := db.Create(&tags).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	published := time.Now().Add(-30 * 24 * time.Hour)
+	var posts []model.Post
+	for g, topic := range []string{"gopher", "crab"} {
+		for i := 0; i < groupSize; i++ {
+			date := published.Add(time.Duration(g*groupSize+i) * time.Hour)
+			posts = append(posts, model.Post{
+				BaseModel:   model.BaseModel{ID: uint(firstID + g*groupSize + i)},
+				Title:       topic + " notes",
+				Slug:        fmt.Sprintf("%s-%d", topic, i),
+				Content:     fmt.Sprintf("%[1]s %[1]sworld %[1]sland %[1]sday%[2]d %[1]sday%[3]d unique%[1]s%[2]d", topic, i%4, i%3),
+				Status:      string(constants.PostStatusPublished),
+				PublishedAt: &date,
+			})
+		}
+	}
+	if err := db.Create(&posts).Error; err != nil {
+		t.Fatal(err)
+	}
+	for i, post := range posts {
+		if err := db.Exec("INSERT INTO post_tags (post_id, tag_id) VALUES (?, ?)", post.ID, tags[i/groupSize].ID).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := Rebuild(); err != nil {
+		t.Fatal(err)
+	}
+	return db, posts
+}
+
+// storedRows 当前保存的得分，按文章和相关文章排序
+func storedRows(t *testing.T, db *gorm.DB) []model.RelatedPost {
+	t.Helper()
+	var rows []model.RelatedPost
+	if err := db.Order("post_id, related_id").Find(&rows).Error; err != nil {
+		t.Fatal(err)
+	}
+	return rows
+}
+
+// key 忽略计算时间的得分摘要
+func key(rows []model.RelatedPost) []string {
+	keys := make([]string, len(rows))
+	for i, row := range rows {
+		keys[i] = fmt.Sprintf("%d->%d:%.4f", row.PostID, row.RelatedID, row.Score)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func TestUpdateMatchesRebuildForTagChange(t *testing.T) {
+	db, posts := seed(t)
+	before := storedRows(t, db)
+
+	// 标签变化不影响词项权重，增量结果应与全量计算完全一致
+	changed := posts[0]
+	if err := db.Exec("DELETE FROM post_tags WHERE post_id = ?", changed.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Exec("INSERT INTO post_tags (post_id, tag_id) VALUES (?, 1), (?, 3)", changed.ID, changed.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := Update([]uint{changed.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n == 0 || n > groupSize {
+		t.Errorf("Update recomputed %d posts, want only posts in the changed post's group (%d)", n, groupSize)
+	}
+
+	after := storedRows(t, db)
+	all, err := publicPosts(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := key(Compute(all, time.Now()))
+	got := key(after)
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("incremental rows differ from a full rebuild:\n got %v\nwant %v", got, want)
+	}
+
+	// 另一组的记录没有被重写
+	untouched := make(map[uint]bool)
+	for _, post := range posts[groupSize:] {
+		untouched[post.ID] = true
+	}
+	updatedAt := make(map[[2]uint]time.Time)
+	for _, row := range before {
+		updatedAt[[2]uint{row.PostID, row.RelatedID}] = row.UpdatedAt
+	}
+	for _, row := range after {
+		if untouched[row.PostID] && !row.UpdatedAt.Equal(updatedAt[[2]uint{row.PostID, row.RelatedID}]) {
+			t.Errorf("row %d->%d of an unaffected post was rewritten", row.PostID, row.RelatedID)
+		}
+	}
+}
+
+func TestUpdateRemovesHiddenPost(t *testing.T) {
+	for name, hide := range map[string]func(db *gorm.DB, post *model.Post) error{
+		"unpublished": func(db *gorm.DB, post *model.Post) error {
+			return db.Model(post).Update("status", constants.PostStatusDraft).Error
+		},
+		"deleted": func(db *gorm.DB, post *model.Post) error {
+			return db.Delete(post).Error
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			db, posts := seed(t)
+
+			hidden := posts[1]
+			if err := hide(db, &hidden); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := Update([]uint{hidden.ID}); err != nil {
+				t.Fatal(err)
+			}
+
+			counts := make(map[uint]int)
+			for _, row := range storedRows(t, db) {
+				if row.PostID == hidden.ID || row.RelatedID == hidden.ID {
+					t.Errorf("row %d->%d still references the %s post", row.PostID, row.RelatedID, name)
+				}
+				counts[row.PostID]++
+			}
+			for _, post := range posts[:groupSize] {
+				if post.ID != hidden.ID && counts[post.ID] != maxStored {
+					t.Errorf("post %d has %d related posts, want %d", post.ID, counts[post.ID], maxStored)
+				}
+			}
+		})
+	}
+}
+
+func TestUpdateAddsNewPost(t *testing.T) {
+	db, posts := seed(t)
+
+	date := time.Now().Add(-time.Hour)
+	post := model.Post{
+		BaseModel:   model.BaseModel{ID: firstID + 2*groupSize},
+		Title:       "gopher notes new",
+		Slug:        "gopher-new",
+		Content:     posts[0].Content,
+		Status:      string(constants.PostStatusPublished),
+		PublishedAt: &date,
+	}
+	if err := db.Create(&post).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Exec("INSERT INTO post_tags (post_id, tag_id) VALUES (?, 1)", post.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Update([]uint{post.ID}); err != nil {
+		t.Fatal(err)
+	}
+
+	// 正文相同的文章列表中加入了新文章，另一组不受影响
+	has := make(map[uint]bool)
+	own := 0
+	for _, row := range storedRows(t, db) {
+		if row.RelatedID == post.ID {
+			has[row.PostID] = true
+		}
+		if row.PostID == post.ID {
+			own++
+		}
+	}
+	if own != maxStored {
+		t.Errorf("new post has %d related posts, want %d", own, maxStored)
+	}
+	if !has[posts[0].ID] {
+		t.Errorf("post %d with the same content does not list the new post", posts[0].ID)
+	}
+	for _, p := range posts[groupSize:] {
+		if has[p.ID] {
+			t.Errorf("unrelated post %d lists the new post", p.ID)
+		}
+	}
+}
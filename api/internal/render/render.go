@@ -0,0 +1,182 @@
+// Package render 内容渲染服务
+// 渲染文章/生活记录的 Markdown 正文，结果按内容哈希缓存到数据库和进程内存，
+// 文章详情、RSS 和静态导出复用同一份输出
+package render
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"sync"
+	"time"
+
+	"gorm.io/gorm/clause"
+	"kuaiyu/internal/database"
+	"kuaiyu/internal/model"
+	"kuaiyu/pkg/markdown"
+)
+
+// memoryCacheSize 进程内缓存的最大条目数
+const memoryCacheSize = 256
+
+var (
+	mu     sync.RWMutex
+	memory = make(map[string]*model.RenderedVO)
+)
+
+// ===========================================
+// 渲染入口
+// ===========================================
+
+// Markdown 渲染 Markdown 正文，优先读取缓存
+// 缓存不可用时直接渲染，不影响调用方
+func Markdown(content string) *model.RenderedVO {
+	hash := Hash(content)
+
+	mu.RLock()
+	vo, ok := memory[hash]
+	mu.RUnlock()
+	if ok {
+		return vo
+	}
+
+	db := database.Get()
+
+	var cached model.RenderedContent
+	if err := db.Where("hash = ?", hash).Limit(1).Find(&cached).Error; err != nil {
+		log.Printf("Failed to read render cache: %v", err)
+	}
+
+	if cached.ID == 0 {
+		cached = model.NewRenderedContent(hash, markdown.Render(content))
+		// 并发渲染同一内容时只保留一条
+		if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&cached).Error; err != nil {
+			log.Printf("Failed to write render cache: %v", err)
+		}
+	}
+
+	vo = cached.ToVO()
+	remember(hash, vo)
+	return vo
+}
+
+// Hash 计算缓存键（渲染器版本 + 正文）
+func Hash(content string) string {
+	sum := sha256.Sum256([]byte(markdown.Version + "\x00" + content))
+	return hex.EncodeToString(sum[:])
+}
+
+// ===========================================
+// 视图填充
+// ===========================================
+
+// Post 为文章视图对象填充渲染结果
+func Post(vo *model.PostVO) {
+	vo.RenderedVO = Markdown(vo.Content)
+}
+
+// Life 为生活记录视图对象填充渲染结果
+func Life(vo *model.LifeRecordVO) {
+	vo.RenderedVO = Markdown(vo.Content)
+}
+
+// Revision 为修订视图对象填充渲染结果
+func Revision(vo *model.PostRevisionVO) {
+	vo.RenderedVO = Markdown(vo.Content)
+}
+
+// ===========================================
+// 缓存清理
+// ===========================================
+
+// pruneBatchSize 每次删除的缓存条目数
+const pruneBatchSize = 500
+
+// cachedSources 渲染缓存对应的正文来源
+var cachedSources = []interface{}{
+	&model.Post{},
+	&model.LifeRecord{},
+	&model.PostRevision{},
+}
+
+// Prune 删除不再对应文章、生活记录当前正文或任何修订正文的缓存（如渲染器版本变化后的旧结果），返回删除的条目数
+// 只清理开始清理前写入的缓存，避免误删清理期间新保存内容的渲染结果
+func Prune() (int, error) {
+	db := database.Get()
+	start := time.Now()
+
+	keep := make(map[string]bool)
+	for _, source := range cachedSources {
+		rows, err := db.Model(source).Select("content").Rows()
+		if err != nil {
+			return 0, err
+		}
+		for rows.Next() {
+			var content string
+			if err := rows.Scan(&content); err != nil {
+				rows.Close()
+				return 0, err
+			}
+			keep[Hash(content)] = true
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return 0, err
+		}
+	}
+
+	var cached []model.RenderedContent
+	if err := db.Model(&model.RenderedContent{}).
+		Select("id, hash").
+		Where("created_at < ?", start).
+		Find(&cached).Error; err != nil {
+		return 0, err
+	}
+
+	var stale []uint
+	for _, c := range cached {
+		if !keep[c.Hash] {
+			stale = append(stale, c.ID)
+		}
+	}
+	for i := 0; i < len(stale); i += pruneBatchSize {
+		batch := stale[i:min(i+pruneBatchSize, len(stale))]
+		if err := db.Delete(&model.RenderedContent{}, batch).Error; err != nil {
+			return i, err
+		}
+	}
+
+	if len(stale) > 0 {
+		mu.Lock()
+		memory = make(map[string]*model.RenderedVO)
+		mu.Unlock()
+	}
+	return len(stale), nil
+}
+
+// EnsurePruned 启动时清理过期的渲染缓存，失败仅记录日志
+func EnsurePruned() {
+	n, err := Prune()
+	if err != nil {
+		log.Printf("Failed to prune render cache: %v", err)
+		return
+	}
+	if n > 0 {
+		log.Printf("Pruned %d stale render cache entries", n)
+	}
+}
+
+// ===========================================
+// 内部实现
+// ===========================================
+
+// remember 写入进程内缓存，超过上限时整体清空
+func remember(hash string, vo *model.RenderedVO) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if len(memory) >= memoryCacheSize {
+		memory = make(map[string]*model.RenderedVO)
+	}
+	memory[hash] = vo
+}
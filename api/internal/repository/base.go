@@ -3,8 +3,11 @@
 package repository
 
 import (
+	"time"
+
 	"gorm.io/gorm"
 	"kuaiyu/internal/database"
+	"kuaiyu/pkg/constants"
 )
 
 // ===========================================
@@ -85,3 +88,23 @@ func (r *BaseRepository) Paginate(models interface{}, page, limit int, count *in
 	return query.Offset(offset).Limit(limit).Find(models).Error
 }
 
+// ===========================================
+// 查询作用域
+// ===========================================
+
+// PublishedScope 公开内容查询作用域
+// 只返回已发布且发布时间已到的内容，table 为表名（用于 JOIN 查询时限定列）
+func PublishedScope(table string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where(table+".status = ? AND ("+table+".published_at IS NULL OR "+table+".published_at <= ?)",
+			constants.PostStatusPublished, time.Now())
+	}
+}
+
+// ListedScope 列表可见作用域
+// 排除不公开列出的文章（仅凭链接访问），用于列表、归档、订阅、站点地图等聚合查询
+func ListedScope(table string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where(table+".visibility <> ?", constants.PostVisibilityUnlisted)
+	}
+}
@@ -2,6 +2,9 @@
 package repository
 
 import (
+	"log"
+	"strings"
+
 	"gorm.io/gorm"
 	"kuaiyu/internal/model"
 	"kuaiyu/pkg/constants"
@@ -217,3 +220,220 @@ func (r *CommentRepository) CountByLifeRecordID(lifeRecordID uint) int64 {
 }
 
 
+
+// ===========================================
+// 评论主题
+// ===========================================
+
+// visibleTo 公开可见的评论；提供邮箱时同时包含该邮箱待审核的评论
+func (r *CommentRepository) visibleTo(query *gorm.DB, email string) *gorm.DB {
+	if email == "" {
+		return query.Where("status = ?", constants.CommentStatusApproved)
+	}
+	return query.Where(r.db.Where("status = ?", constants.CommentStatusApproved).
+		Or("status = ? AND email = ?", constants.CommentStatusPending, email))
+}
+
+// FindThreads 按游标分页查找顶层评论，置顶优先，其次按时间倒序
+// 多取一条用于判断是否还有下一页
+func (r *CommentRepository) FindThreads(commentType string, targetID *uint, email string, after *model.CommentCursor, limit int) ([]model.Comment, bool, error) {
+	query := r.db.Where("comment_type = ? AND parent_id IS NULL", commentType)
+	if targetID != nil {
+		query = query.Where("target_id = ?", *targetID)
+	} else {
+		query = query.Where("target_id IS NULL")
+	}
+	query = r.visibleTo(query, email)
+	
+	if after != nil {
+		query = query.Where("(is_pinned < ? OR (is_pinned = ? AND (created_at < ? OR (created_at = ? AND id < ?))))",
+			after.Pinned, after.Pinned, after.CreatedAt, after.CreatedAt, after.ID)
+	}
+	
+	var comments []model.Comment
+	err := query.Order("is_pinned DESC, created_at DESC, id DESC").
+		Limit(limit + 1).
+		Find(&comments).Error
+	if err != nil {
+		return nil, false, err
+	}
+	
+	hasMore := len(comments) > limit
+	if hasMore {
+		comments = comments[:limit]
+	}
+	return comments, hasMore, nil
+}
+
+// FindDescendants 按物化路径顺序查找评论的后代（深度优先，同层按时间先后）
+// afterPath 为上一页最后一条回复的路径，为空时从头开始
+func (r *CommentRepository) FindDescendants(ancestor *model.Comment, email, afterPath string, limit int) ([]model.Comment, bool, error) {
+	after := ancestor.Path
+	if strings.HasPrefix(afterPath, ancestor.Path) && afterPath > after {
+		after = afterPath
+	}
+	
+	query := r.db.Where("path LIKE ? AND path > ?", ancestor.Path+"%", after)
+	query = r.visibleTo(query, email)
+	
+	var replies []model.Comment
+	err := query.Order("path ASC").
+		Limit(limit + 1).
+		Find(&replies).Error
+	if err != nil {
+		return nil, false, err
+	}
+	
+	hasMore := len(replies) > limit
+	if hasMore {
+		replies = replies[:limit]
+	}
+	return replies, hasMore, nil
+}
+
+// FindThreadReplies 批量查找各主题的前 limit 条回复（按物化路径顺序），返回 主题 ID -> 回复 和 主题 ID -> 是否还有更多
+// 每个主题多取一条用于判断是否还有更多回复
+func (r *CommentRepository) FindThreadReplies(rootIDs []uint, email string, limit int) (map[uint][]model.Comment, map[uint]bool, error) {
+	replies := make(map[uint][]model.Comment)
+	hasMore := make(map[uint]bool)
+	if len(rootIDs) == 0 {
+		return replies, hasMore, nil
+	}
+	
+	ranked := r.visibleTo(r.db.Model(&model.Comment{}).Where("root_id IN ?", rootIDs), email).
+		Select("comments.*, ROW_NUMBER() OVER (PARTITION BY root_id ORDER BY path) AS thread_rank")
+	
+	var rows []model.Comment
+	err := r.db.Table("(?) AS ranked", ranked).
+		Where("thread_rank <= ?", limit+1).
+		Order("root_id, path").
+		Find(&rows).Error
+	if err != nil {
+		return nil, nil, err
+	}
+	
+	for _, row := range rows {
+		rootID := *row.RootID
+		if len(replies[rootID]) == limit {
+			hasMore[rootID] = true
+			continue
+		}
+		replies[rootID] = append(replies[rootID], row)
+	}
+	return replies, hasMore, nil
+}
+
+// CountThreadReplies 统计各主题中可见的回复数
+func (r *CommentRepository) CountThreadReplies(rootIDs []uint, email string) map[uint]int {
+	result := make(map[uint]int)
+	if len(rootIDs) == 0 {
+		return result
+	}
+	
+	var counts []struct {
+		RootID uint
+		Count  int
+	}
+	query := r.db.Model(&model.Comment{}).Where("root_id IN ?", rootIDs)
+	r.visibleTo(query, email).
+		Select("root_id, COUNT(*) as count").
+		Group("root_id").
+		Scan(&counts)
+	
+	for _, c := range counts {
+		result[c.RootID] = c.Count
+	}
+	return result
+}
+
+// FindSubtreeIDs 查找评论的全部后代 ID
+func (r *CommentRepository) FindSubtreeIDs(comment *model.Comment) []uint {
+	var ids []uint
+	if comment.Path == "" {
+		r.db.Model(&model.Comment{}).Where("parent_id = ?", comment.ID).Pluck("id", &ids)
+		return ids
+	}
+	r.db.Model(&model.Comment{}).
+		Where("path LIKE ? AND id <> ?", comment.Path+"%", comment.ID).
+		Pluck("id", &ids)
+	return ids
+}
+
+// FindEdits 查找评论的作者编辑历史，最近的在前
+func (r *CommentRepository) FindEdits(commentID uint) ([]model.CommentEdit, error) {
+	var edits []model.CommentEdit
+	err := r.db.Where("comment_id = ?", commentID).
+		Order("created_at DESC, id DESC").
+		Find(&edits).Error
+	return edits, err
+}
+
+// CreateInThread 在父评论下写入评论，parent 为空表示顶层评论
+// 物化路径包含自身 ID，写入后再补充
+func (r *CommentRepository) CreateInThread(comment, parent *model.Comment) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(comment).Error; err != nil {
+			return err
+		}
+		return SaveThread(tx, comment, parent)
+	})
+}
+
+// SaveThread 为已写入的评论设置并保存主题、层级和物化路径（不更新 updated_at）
+func SaveThread(tx *gorm.DB, comment, parent *model.Comment) error {
+	comment.SetThread(parent)
+	return tx.Model(comment).UpdateColumns(map[string]interface{}{
+		"parent_id": comment.ParentID,
+		"root_id":   comment.RootID,
+		"depth":     comment.Depth,
+		"path":      comment.Path,
+	}).Error
+}
+
+// EnsurePaths 为没有物化路径的评论（升级前的数据）生成主题信息
+// 旧数据的回复都挂在顶层评论下，被回复的评论记录在 reply_to_id，这里按 reply_to_id 还原为树形结构；
+// 父评论已不存在的回复作为顶层评论，超出最大层级的回复挂到所在主题的顶层评论下
+func (r *CommentRepository) EnsurePaths() error {
+	var comments []model.Comment
+	if err := r.db.Where("path = '' OR path IS NULL").Order("id ASC").Find(&comments).Error; err != nil {
+		return err
+	}
+	if len(comments) == 0 {
+		return nil
+	}
+	
+	known := make(map[uint]*model.Comment)
+	lookup := func(id *uint) *model.Comment {
+		if id == nil {
+			return nil
+		}
+		if c, ok := known[*id]; ok {
+			return c
+		}
+		var c model.Comment
+		if r.db.First(&c, *id).Error != nil || c.Path == "" {
+			return nil
+		}
+		known[c.ID] = &c
+		return &c
+	}
+	
+	for i := range comments {
+		comment := &comments[i]
+		parent := lookup(comment.ReplyToID)
+		if parent == nil || parent.CommentType != comment.CommentType {
+			parent = lookup(comment.ParentID)
+		}
+		if parent != nil && !parent.CanReply() {
+			parent = lookup(parent.RootID)
+		}
+	
+		if err := SaveThread(r.db, comment, parent); err != nil {
+			return err
+		}
+		known[comment.ID] = comment
+	}
+	
+	log.Printf("Built comment threads for %d comments", len(comments))
+	return nil
+}
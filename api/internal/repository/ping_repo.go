@@ -0,0 +1,60 @@
+// Package repository 搜索引擎推送记录数据访问层
+package repository
+
+import (
+	"kuaiyu/internal/model"
+)
+
+// ===========================================
+// 推送记录仓库
+// ===========================================
+
+// PingRepository 推送记录仓库
+type PingRepository struct {
+	*BaseRepository
+}
+
+// NewPingRepository 创建推送记录仓库
+func NewPingRepository() *PingRepository {
+	return &PingRepository{
+		BaseRepository: NewBaseRepository(),
+	}
+}
+
+// ===========================================
+// 查询方法
+// ===========================================
+
+// FindByID 根据 ID 查找
+func (r *PingRepository) FindByID(id uint) (*model.PingLog, error) {
+	var entry model.PingLog
+	err := r.db.First(&entry, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// FindAll 分页查找推送记录，kind、status 为空时不过滤
+func (r *PingRepository) FindAll(kind, status string, page, limit int) ([]model.PingLog, int64, error) {
+	var logs []model.PingLog
+	var total int64
+
+	query := r.db.Model(&model.PingLog{})
+	if kind != "" {
+		query = query.Where("kind = ?", kind)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	err := query.Order("created_at DESC, id DESC").
+		Offset(offset).Limit(limit).
+		Find(&logs).Error
+	return logs, total, err
+}
@@ -2,7 +2,12 @@
 package repository
 
 import (
+	"fmt"
+	"math"
+	"time"
+
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"kuaiyu/internal/model"
 	"kuaiyu/pkg/constants"
 )
@@ -55,7 +60,7 @@ func (r *PostRepository) FindPublished(page, limit int) ([]model.Post, int64, er
 	var count int64
 	
 	query := r.db.Model(&model.Post{}).
-		Where("status = ?", constants.PostStatusPublished).
+		Scopes(PublishedScope("posts"), ListedScope("posts")).
 		Preload("Tags")
 	
 	// 计数
@@ -63,9 +68,9 @@ func (r *PostRepository) FindPublished(page, limit int) ([]model.Post, int64, er
 		return nil, 0, err
 	}
 	
-	// 查询
+	// 查询：置顶中的文章按置顶顺序排在最前，其余按发布时间
 	offset := (page - 1) * limit
-	err := query.Order("published_at DESC").
+	err := query.Clauses(pinnedFirstOrder(time.Now())).
 		Offset(offset).Limit(limit).
 		Find(&posts).Error
 	
@@ -86,7 +91,8 @@ func (r *PostRepository) FindByTag(tagSlug string, page, limit int) ([]model.Pos
 	// 查询关联文章
 	query := r.db.Model(&model.Post{}).
 		Joins("JOIN post_tags ON post_tags.post_id = posts.id").
-		Where("post_tags.tag_id = ? AND posts.status = ?", tag.ID, constants.PostStatusPublished).
+		Where("post_tags.tag_id = ?", tag.ID).
+		Scopes(PublishedScope("posts"), ListedScope("posts")).
 		Preload("Tags")
 	
 	// 计数
@@ -109,9 +115,10 @@ func (r *PostRepository) Search(keyword string, page, limit int) ([]model.Post,
 	var count int64
 	
 	query := r.db.Model(&model.Post{}).
-		Where("status = ?", constants.PostStatusPublished).
-		Where("title LIKE ? OR excerpt LIKE ? OR content LIKE ?",
-			"%"+keyword+"%", "%"+keyword+"%", "%"+keyword+"%").
+		Scopes(PublishedScope("posts"), ListedScope("posts")).
+		// 加密文章只匹配标题和摘要，避免通过正文关键词探测内容
+		Where("title LIKE ? OR excerpt LIKE ? OR (content LIKE ? AND visibility <> ?)",
+			"%"+keyword+"%", "%"+keyword+"%", "%"+keyword+"%", constants.PostVisibilityProtected).
 		Preload("Tags")
 	
 	// 计数
@@ -128,17 +135,82 @@ func (r *PostRepository) Search(keyword string, page, limit int) ([]model.Post,
 	return posts, count, err
 }
 
-// FindFeatured 查找推荐文章
-func (r *PostRepository) FindFeatured(limit int) ([]model.Post, error) {
-	var posts []model.Post
-	err := r.db.Where("status = ?", constants.PostStatusPublished).
+// FindPublishedByIDs 根据 ID 列表查找已发布文章，保持传入顺序
+func (r *PostRepository) FindPublishedByIDs(ids []uint) ([]model.Post, error) {
+	if len(ids) == 0 {
+		return []model.Post{}, nil
+	}
+	
+	var found []model.Post
+	err := r.db.Scopes(PublishedScope("posts"), ListedScope("posts")).
+		Where("id IN ?", ids).
 		Preload("Tags").
+		Find(&found).Error
+	if err != nil {
+		return nil, err
+	}
+	
+	byID := make(map[uint]model.Post, len(found))
+	for _, post := range found {
+		byID[post.ID] = post
+	}
+	
+	posts := make([]model.Post, 0, len(ids))
+	for _, id := range ids {
+		if post, ok := byID[id]; ok {
+			posts = append(posts, post)
+		}
+	}
+	
+	return posts, nil
+}
+
+// FindFeatured 查找推荐文章（按阅读量），excludeIDs 中的文章不参与
+func (r *PostRepository) FindFeatured(limit int, excludeIDs []uint) ([]model.Post, error) {
+	var posts []model.Post
+	query := r.db.Scopes(PublishedScope("posts"), ListedScope("posts"))
+	if len(excludeIDs) > 0 {
+		query = query.Where("id NOT IN ?", excludeIDs)
+	}
+	err := query.Preload("Tags").
 		Order("view_count DESC").
 		Limit(limit).
 		Find(&posts).Error
 	return posts, err
 }
 
+// FindCurated 查找后台精选的公开文章（按精选顺序）
+func (r *PostRepository) FindCurated(limit int) ([]model.Post, error) {
+	var posts []model.Post
+	err := r.db.Scopes(PublishedScope("posts"), ListedScope("posts")).
+		Where("feature_order > 0").
+		Preload("Tags").
+		Order("feature_order ASC").
+		Limit(limit).
+		Find(&posts).Error
+	return posts, err
+}
+
+// FindAllCurated 查找全部精选文章（管理后台，包含未发布的）
+func (r *PostRepository) FindAllCurated() ([]model.Post, error) {
+	var posts []model.Post
+	err := r.db.Where("feature_order > 0").
+		Preload("Tags").
+		Order("feature_order ASC").
+		Find(&posts).Error
+	return posts, err
+}
+
+// FindPinned 查找全部设置了置顶的文章（管理后台，包含已过期的）
+func (r *PostRepository) FindPinned() ([]model.Post, error) {
+	var posts []model.Post
+	err := r.db.Where("pin_order > 0").
+		Preload("Tags").
+		Order("pin_order ASC").
+		Find(&posts).Error
+	return posts, err
+}
+
 // FindAll 查找所有文章（管理后台）
 func (r *PostRepository) FindAll(page, limit int, status string) ([]model.Post, int64, error) {
 	var posts []model.Post
@@ -180,7 +252,7 @@ func (r *PostRepository) FindArchives() ([]Archive, error) {
 	var archives []Archive
 	err := r.db.Model(&model.Post{}).
 		Select("YEAR(published_at) as year, MONTH(published_at) as month, COUNT(*) as count").
-		Where("status = ?", constants.PostStatusPublished).
+		Scopes(PublishedScope("posts"), ListedScope("posts")).
 		Group("YEAR(published_at), MONTH(published_at)").
 		Order("year DESC, month DESC").
 		Scan(&archives).Error
@@ -190,7 +262,8 @@ func (r *PostRepository) FindArchives() ([]Archive, error) {
 // FindByYear 根据年份查找文章
 func (r *PostRepository) FindByYear(year int) ([]model.Post, error) {
 	var posts []model.Post
-	err := r.db.Where("status = ? AND YEAR(published_at) = ?", constants.PostStatusPublished, year).
+	err := r.db.Scopes(PublishedScope("posts"), ListedScope("posts")).
+		Where("YEAR(published_at) = ?", year).
 		Order("published_at DESC").
 		Find(&posts).Error
 	return posts, err
@@ -200,6 +273,67 @@ func (r *PostRepository) FindByYear(year int) ([]model.Post, error) {
 // 更新方法
 // ===========================================
 
+// Pin 置顶文章，order 为 0 时排在已置顶文章之后
+// 置顶不修改 updated_at，避免影响站点地图的 lastmod
+func (r *PostRepository) Pin(id uint, order int, until *time.Time) error {
+	if order <= 0 {
+		var maxOrder int
+		if err := r.db.Model(&model.Post{}).Select("COALESCE(MAX(pin_order), 0)").Scan(&maxOrder).Error; err != nil {
+			return err
+		}
+		order = maxOrder + 1
+	}
+	return r.db.Model(&model.Post{}).Where("id = ?", id).
+		UpdateColumns(map[string]interface{}{
+			"pin_order":    order,
+			"pinned_until": until,
+		}).Error
+}
+
+// Unpin 取消置顶
+func (r *PostRepository) Unpin(id uint) error {
+	return r.db.Model(&model.Post{}).Where("id = ?", id).
+		UpdateColumns(map[string]interface{}{
+			"pin_order":    0,
+			"pinned_until": nil,
+		}).Error
+}
+
+// SetFeatured 设置精选文章及顺序（整体替换）
+func (r *PostRepository) SetFeatured(postIDs []uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		seen := make(map[uint]bool, len(postIDs))
+		for _, id := range postIDs {
+			if seen[id] {
+				return fmt.Errorf("文章 %d 重复", id)
+			}
+			seen[id] = true
+		}
+		
+		if len(postIDs) > 0 {
+			var count int64
+			if err := tx.Model(&model.Post{}).Where("id IN ?", postIDs).Count(&count).Error; err != nil {
+				return err
+			}
+			if int(count) != len(postIDs) {
+				return fmt.Errorf("部分文章不存在")
+			}
+		}
+		
+		if err := tx.Model(&model.Post{}).Where("feature_order > 0").
+			UpdateColumn("feature_order", 0).Error; err != nil {
+			return err
+		}
+		for i, id := range postIDs {
+			if err := tx.Model(&model.Post{}).Where("id = ?", id).
+				UpdateColumn("feature_order", i+1).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
 // IncrementViewCount 增加阅读量
 func (r *PostRepository) IncrementViewCount(id uint) error {
 	return r.db.Model(&model.Post{}).Where("id = ?", id).
@@ -231,6 +365,7 @@ func (r *PostRepository) UpdateTags(post *model.Post, tagIDs []uint) error {
 // ===========================================
 
 // SlugExists 检查 slug 是否存在
+// 文章和翻译共用 slug 空间，翻译已使用的 slug 也视为存在
 func (r *PostRepository) SlugExists(slug string, excludeID uint) bool {
 	var count int64
 	query := r.db.Model(&model.Post{}).Where("slug = ?", slug)
@@ -238,6 +373,20 @@ func (r *PostRepository) SlugExists(slug string, excludeID uint) bool {
 		query = query.Where("id != ?", excludeID)
 	}
 	query.Count(&count)
+	if count > 0 {
+		return true
+	}
+	
+	r.db.Model(&model.PostTranslation{}).Where("slug = ?", slug).Count(&count)
 	return count > 0
 }
 
+// pinnedFirstOrder 置顶优先的排序：置顶中的文章按置顶顺序，其余按发布时间倒序
+// 已过期的置顶视为未置顶
+func pinnedFirstOrder(now time.Time) clause.OrderBy {
+	return clause.OrderBy{Expression: clause.Expr{
+		SQL:                "CASE WHEN posts.pin_order > 0 AND (posts.pinned_until IS NULL OR posts.pinned_until > ?) THEN posts.pin_order ELSE ? END ASC, posts.published_at DESC",
+		Vars:               []interface{}{now, math.MaxInt32},
+		WithoutParentheses: true,
+	}}
+}
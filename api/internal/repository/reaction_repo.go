@@ -0,0 +1,146 @@
+// Package repository 读者表态数据访问层
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"kuaiyu/internal/model"
+)
+
+// ===========================================
+// 读者表态仓库
+// ===========================================
+
+// ReactionRepository 读者表态仓库
+type ReactionRepository struct {
+	*BaseRepository
+}
+
+// NewReactionRepository 创建读者表态仓库
+func NewReactionRepository() *ReactionRepository {
+	return &ReactionRepository{
+		BaseRepository: NewBaseRepository(),
+	}
+}
+
+// ===========================================
+// 查询方法
+// ===========================================
+
+// CountByTargets 批量统计同类对象的表态数，返回 目标 ID -> 表情 -> 数量
+func (r *ReactionRepository) CountByTargets(targetType string, targetIDs []uint) (map[uint]map[string]int, error) {
+	result := make(map[uint]map[string]int)
+	if len(targetIDs) == 0 {
+		return result, nil
+	}
+
+	var rows []struct {
+		TargetID uint
+		Emoji    string
+		Count    int
+	}
+	err := r.db.Model(&model.Reaction{}).
+		Select("target_id, emoji, COUNT(*) AS count").
+		Where("target_type = ? AND target_id IN ?", targetType, targetIDs).
+		Group("target_id, emoji").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		if result[row.TargetID] == nil {
+			result[row.TargetID] = make(map[string]int)
+		}
+		result[row.TargetID][row.Emoji] = row.Count
+	}
+	return result, nil
+}
+
+// FindMine 查找某个读者对对象已表态的表情
+func (r *ReactionRepository) FindMine(targetType string, targetID uint, fingerprint string) ([]string, error) {
+	var emojis []string
+	err := r.db.Model(&model.Reaction{}).
+		Where("target_type = ? AND target_id = ? AND fingerprint = ?", targetType, targetID, fingerprint).
+		Pluck("emoji", &emojis).Error
+	return emojis, err
+}
+
+// ReactionDailyCount 单日单表情的表态数
+type ReactionDailyCount struct {
+	Date  string
+	Emoji string
+	Count int
+}
+
+// CountDaily 按天和表情统计 since 之后的表态数，targetType 为空时统计全部类型
+func (r *ReactionRepository) CountDaily(targetType string, since time.Time) ([]ReactionDailyCount, error) {
+	var rows []ReactionDailyCount
+	query := r.db.Model(&model.Reaction{}).
+		Select("DATE_FORMAT(created_at, '%Y-%m-%d') AS date, emoji, COUNT(*) AS count").
+		Where("created_at >= ?", since)
+	if targetType != "" {
+		query = query.Where("target_type = ?", targetType)
+	}
+	err := query.Group("date, emoji").Order("date ASC").Scan(&rows).Error
+	return rows, err
+}
+
+// ReactionTargetCount 单个对象的表态数
+type ReactionTargetCount struct {
+	TargetType string
+	TargetID   uint
+	Count      int
+}
+
+// FindTopTargets 查找 since 之后表态最多的对象
+func (r *ReactionRepository) FindTopTargets(targetType string, since time.Time, limit int) ([]ReactionTargetCount, error) {
+	var rows []ReactionTargetCount
+	query := r.db.Model(&model.Reaction{}).
+		Select("target_type, target_id, COUNT(*) AS count").
+		Where("created_at >= ?", since)
+	if targetType != "" {
+		query = query.Where("target_type = ?", targetType)
+	}
+	err := query.Group("target_type, target_id").
+		Order("count DESC").
+		Limit(limit).
+		Scan(&rows).Error
+	return rows, err
+}
+
+// ===========================================
+// 修改方法
+// ===========================================
+
+// Toggle 切换表态：已存在则取消，否则新增；返回切换后是否处于表态状态
+func (r *ReactionRepository) Toggle(reaction *model.Reaction) (bool, error) {
+	active := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var existing model.Reaction
+		err := tx.Where("target_type = ? AND target_id = ? AND emoji = ? AND fingerprint = ?",
+			reaction.TargetType, reaction.TargetID, reaction.Emoji, reaction.Fingerprint).
+			First(&existing).Error
+		if err == nil {
+			return tx.Delete(&existing).Error
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		active = true
+		return tx.Create(reaction).Error
+	})
+	return active, err
+}
+
+// DeleteByTargets 删除对象的全部表态（对象删除时调用）
+func (r *ReactionRepository) DeleteByTargets(targetType string, targetIDs []uint) error {
+	if len(targetIDs) == 0 {
+		return nil
+	}
+	return r.db.Where("target_type = ? AND target_id IN ?", targetType, targetIDs).
+		Delete(&model.Reaction{}).Error
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"testing"
+
+	"kuaiyu/internal/database/dbtest"
+	"kuaiyu/internal/model"
+)
+
+// 默认表情都在补充平面，utf8mb4_unicode_ci 下互相等价
+var testEmojis = []string{"👍", "🎉", "😄", "🤔", "👀"}
+
+func TestReactionToggleDistinguishesEmojis(t *testing.T) {
+	dbtest.Open(t, &model.Reaction{})
+	repo := NewReactionRepository()
+
+	toggle := func(emoji string) bool {
+		t.Helper()
+		active, err := repo.Toggle(&model.Reaction{
+			TargetType:  model.ReactionTargetPost,
+			TargetID:    1,
+			Emoji:       emoji,
+			Fingerprint: "reader",
+		})
+		if err != nil {
+			t.Fatalf("toggle %s: %v", emoji, err)
+		}
+		return active
+	}
+
+	for _, emoji := range testEmojis {
+		if !toggle(emoji) {
+			t.Fatalf("toggle %s removed an existing reaction", emoji)
+		}
+	}
+
+	mine, err := repo.FindMine(model.ReactionTargetPost, 1, "reader")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mine) != len(testEmojis) {
+		t.Fatalf("got reactions %v, want %v", mine, testEmojis)
+	}
+
+	counts, err := repo.CountByTargets(model.ReactionTargetPost, []uint{1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, emoji := range testEmojis {
+		if counts[1][emoji] != 1 {
+			t.Errorf("count of %s = %d, want 1 (counts %v)", emoji, counts[1][emoji], counts[1])
+		}
+	}
+
+	// 再次提交只取消对应的表情
+	if toggle("🎉") {
+		t.Fatal("second toggle of 🎉 should remove it")
+	}
+	counts, err = repo.CountByTargets(model.ReactionTargetPost, []uint{1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if counts[1]["🎉"] != 0 || counts[1]["👍"] != 1 {
+		t.Fatalf("after removing 🎉: %v", counts[1])
+	}
+}
@@ -0,0 +1,171 @@
+// Package repository 重定向数据访问层
+package repository
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"kuaiyu/internal/model"
+)
+
+// ===========================================
+// 重定向仓库
+// ===========================================
+
+// RedirectRepository 重定向仓库
+type RedirectRepository struct {
+	*BaseRepository
+}
+
+// NewRedirectRepository 创建重定向仓库
+func NewRedirectRepository() *RedirectRepository {
+	return &RedirectRepository{
+		BaseRepository: NewBaseRepository(),
+	}
+}
+
+// ===========================================
+// 查询方法
+// ===========================================
+
+// FindBySource 根据类型和来源查找重定向
+func (r *RedirectRepository) FindBySource(redirectType, source string) (*model.SlugRedirect, error) {
+	var redirect model.SlugRedirect
+	err := r.db.Where("type = ? AND source = ?", redirectType, source).First(&redirect).Error
+	if err != nil {
+		return nil, err
+	}
+	return &redirect, nil
+}
+
+// FindByID 根据 ID 查找
+func (r *RedirectRepository) FindByID(id uint) (*model.SlugRedirect, error) {
+	var redirect model.SlugRedirect
+	err := r.db.First(&redirect, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &redirect, nil
+}
+
+// FindByTarget 查找指向某篇文章或某个标签的全部旧 slug
+func (r *RedirectRepository) FindByTarget(redirectType string, targetID uint) ([]model.SlugRedirect, error) {
+	var redirects []model.SlugRedirect
+	err := r.db.Where("type = ? AND target_id = ?", redirectType, targetID).
+		Order("created_at DESC").
+		Find(&redirects).Error
+	return redirects, err
+}
+
+// FindAll 分页查找重定向，redirectType 为空时返回全部类型
+func (r *RedirectRepository) FindAll(redirectType string, page, limit int) ([]model.SlugRedirect, int64, error) {
+	var redirects []model.SlugRedirect
+	var total int64
+
+	query := r.db.Model(&model.SlugRedirect{})
+	if redirectType != "" {
+		query = query.Where("type = ?", redirectType)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	err := query.Order("created_at DESC").
+		Offset(offset).Limit(limit).
+		Find(&redirects).Error
+	return redirects, total, err
+}
+
+// ===========================================
+// 修改方法
+// ===========================================
+
+// RecordSlugChange 记录文章、翻译或标签的 slug 变更
+// 旧 slug 指向目标 ID；新 slug 重新启用后不再作为旧地址
+func (r *RedirectRepository) RecordSlugChange(redirectType string, targetID uint, oldSlug, newSlug string) error {
+	if oldSlug == "" || oldSlug == newSlug {
+		return nil
+	}
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("type = ? AND source = ?", redirectType, newSlug).
+			Delete(&model.SlugRedirect{}).Error; err != nil {
+			return err
+		}
+
+		redirect := model.SlugRedirect{
+			Type:      redirectType,
+			Source:    oldSlug,
+			TargetID:  targetID,
+			Permanent: true,
+		}
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "type"}, {Name: "source"}},
+			DoUpdates: clause.AssignmentColumns([]string{"target_id", "updated_at"}),
+		}).Create(&redirect).Error
+	})
+}
+
+// RecordHit 记录一次命中
+func (r *RedirectRepository) RecordHit(id uint) error {
+	return r.db.Model(&model.SlugRedirect{}).Where("id = ?", id).
+		UpdateColumns(map[string]interface{}{
+			"hits":        gorm.Expr("hits + ?", 1),
+			"last_hit_at": time.Now(),
+		}).Error
+}
+
+// Create 创建重定向
+func (r *RedirectRepository) Create(redirect *model.SlugRedirect) error {
+	return r.db.Create(redirect).Error
+}
+
+// Save 保存重定向
+func (r *RedirectRepository) Save(redirect *model.SlugRedirect) error {
+	return r.db.Save(redirect).Error
+}
+
+// Delete 删除重定向
+func (r *RedirectRepository) Delete(id uint) error {
+	return r.db.Delete(&model.SlugRedirect{}, id).Error
+}
+
+// DeleteByTarget 删除指向某篇文章、某个翻译或某个标签的重定向（目标删除时调用）
+func (r *RedirectRepository) DeleteByTarget(redirectType string, targetID uint) error {
+	return r.db.Where("type = ? AND target_id = ?", redirectType, targetID).
+		Delete(&model.SlugRedirect{}).Error
+}
+
+// MoveTranslationRedirects 翻译删除后，将其旧 slug 改为指向原文
+// 与文章已有重定向冲突的旧 slug 保留文章的重定向
+func (r *RedirectRepository) MoveTranslationRedirects(translationID, postID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var conflicts []string
+		err := tx.Model(&model.SlugRedirect{}).
+			Where("type = ? AND target_id = ?", model.RedirectTypeTranslation, translationID).
+			Where("source IN (?)", tx.Model(&model.SlugRedirect{}).
+				Select("source").
+				Where("type = ?", model.RedirectTypePost)).
+			Pluck("source", &conflicts).Error
+		if err != nil {
+			return err
+		}
+		if len(conflicts) > 0 {
+			if err := tx.Where("type = ? AND target_id = ? AND source IN ?",
+				model.RedirectTypeTranslation, translationID, conflicts).
+				Delete(&model.SlugRedirect{}).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Model(&model.SlugRedirect{}).
+			Where("type = ? AND target_id = ?", model.RedirectTypeTranslation, translationID).
+			Updates(map[string]interface{}{
+				"type":      model.RedirectTypePost,
+				"target_id": postID,
+			}).Error
+	})
+}
@@ -0,0 +1,69 @@
+// Package repository 相关文章数据访问层
+package repository
+
+import (
+	"gorm.io/gorm/clause"
+	"kuaiyu/internal/model"
+)
+
+// ===========================================
+// 相关文章仓库
+// ===========================================
+
+// RelatedRepository 相关文章仓库
+type RelatedRepository struct {
+	*BaseRepository
+}
+
+// NewRelatedRepository 创建相关文章仓库
+func NewRelatedRepository() *RelatedRepository {
+	return &RelatedRepository{
+		BaseRepository: NewBaseRepository(),
+	}
+}
+
+// ===========================================
+// 查询方法
+// ===========================================
+
+// FindScores 查找文章的预计算相关文章，按得分降序
+func (r *RelatedRepository) FindScores(postID uint) ([]model.RelatedPost, error) {
+	var scores []model.RelatedPost
+	err := r.db.Where("post_id = ?", postID).
+		Order("score DESC, related_id DESC").
+		Find(&scores).Error
+	return scores, err
+}
+
+// FindOverrides 查找文章的人工干预，置顶项按顺序排列
+func (r *RelatedRepository) FindOverrides(postID uint) ([]model.RelatedOverride, error) {
+	var overrides []model.RelatedOverride
+	err := r.db.Where("post_id = ?", postID).
+		Order("position ASC, id ASC").
+		Find(&overrides).Error
+	return overrides, err
+}
+
+// ===========================================
+// 修改方法
+// ===========================================
+
+// SetOverride 设置人工干预（已存在则覆盖）
+func (r *RelatedRepository) SetOverride(override *model.RelatedOverride) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "post_id"}, {Name: "related_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"action", "position"}),
+	}).Create(override).Error
+}
+
+// DeleteOverride 取消人工干预
+func (r *RelatedRepository) DeleteOverride(postID, relatedID uint) error {
+	return r.db.Where("post_id = ? AND related_id = ?", postID, relatedID).
+		Delete(&model.RelatedOverride{}).Error
+}
+
+// DeleteOverridesByPost 删除与文章有关的人工干预（文章删除时调用）
+// 预计算的得分由 related.Refresh 增量清理，并为原列表包含该文章的文章补位
+func (r *RelatedRepository) DeleteOverridesByPost(postID uint) error {
+	return r.db.Where("post_id = ? OR related_id = ?", postID, postID).Delete(&model.RelatedOverride{}).Error
+}
@@ -0,0 +1,138 @@
+// Package repository 修订历史数据访问层
+package repository
+
+import (
+	"errors"
+
+	"github.com/go-sql-driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"kuaiyu/internal/model"
+	"kuaiyu/pkg/constants"
+)
+
+// ===========================================
+// 修订历史仓库
+// ===========================================
+
+// RevisionRepository 修订历史仓库
+type RevisionRepository struct {
+	*BaseRepository
+}
+
+// NewRevisionRepository 创建修订历史仓库
+func NewRevisionRepository() *RevisionRepository {
+	return &RevisionRepository{
+		BaseRepository: NewBaseRepository(),
+	}
+}
+
+// ===========================================
+// 查询方法
+// ===========================================
+
+// FindByTarget 查找某个内容的修订历史（分页，按版本倒序）
+func (r *RevisionRepository) FindByTarget(targetType string, targetID uint, page, limit int) ([]model.PostRevision, int64, error) {
+	var revisions []model.PostRevision
+	var count int64
+
+	query := r.db.Model(&model.PostRevision{}).
+		Where("target_type = ? AND target_id = ?", targetType, targetID)
+
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	err := query.Order("version DESC").
+		Offset(offset).Limit(limit).
+		Find(&revisions).Error
+
+	return revisions, count, err
+}
+
+// FindByID 根据 ID 查找修订，并校验其所属内容
+func (r *RevisionRepository) FindByID(targetType string, targetID, id uint) (*model.PostRevision, error) {
+	var revision model.PostRevision
+	err := r.db.Where("target_type = ? AND target_id = ?", targetType, targetID).
+		First(&revision, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &revision, nil
+}
+
+// FindLatest 查找最新修订
+func (r *RevisionRepository) FindLatest(targetType string, targetID uint) (*model.PostRevision, error) {
+	var revision model.PostRevision
+	err := r.db.Where("target_type = ? AND target_id = ?", targetType, targetID).
+		Order("version DESC").
+		First(&revision).Error
+	if err != nil {
+		return nil, err
+	}
+	return &revision, nil
+}
+
+// ===========================================
+// 写入方法
+// ===========================================
+
+// saveAttempts 并发保存冲突时的最大尝试次数
+const saveAttempts = 3
+
+// Save 保存快照，正文与最新修订一致时跳过
+// 返回 false 表示未产生新修订。版本号在事务中锁定最新修订后计算；
+// 同一内容的首个修订没有可锁定的行，并发写入仍可能冲突，此时重新计算版本号重试
+func (r *RevisionRepository) Save(revision *model.PostRevision) (bool, error) {
+	for attempt := 1; ; attempt++ {
+		saved, err := r.save(revision)
+		if err == nil || attempt >= saveAttempts || !isWriteConflict(err) {
+			return saved, err
+		}
+	}
+}
+
+// save 在事务中计算版本号并写入修订
+func (r *RevisionRepository) save(revision *model.PostRevision) (bool, error) {
+	saved := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var latest model.PostRevision
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("target_type = ? AND target_id = ?", revision.TargetType, revision.TargetID).
+			Order("version DESC").
+			First(&latest).Error
+		switch {
+		case err == nil:
+			// 仅状态变化（如发布）不产生新修订
+			if revision.Reason != string(constants.RevisionReasonRestore) && latest.SameContent(revision) {
+				return nil
+			}
+			revision.Version = latest.Version + 1
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			revision.Version = 1
+		default:
+			return err
+		}
+
+		if err := tx.Create(revision).Error; err != nil {
+			return err
+		}
+		saved = true
+		return nil
+	})
+	if err != nil {
+		revision.ID = 0
+		return false, err
+	}
+	return saved, nil
+}
+
+// isWriteConflict 是否为并发写入导致的唯一索引冲突或死锁
+func isWriteConflict(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	if !errors.As(err, &mysqlErr) {
+		return errors.Is(err, gorm.ErrDuplicatedKey)
+	}
+	return mysqlErr.Number == 1062 || mysqlErr.Number == 1213
+}
@@ -0,0 +1,236 @@
+// Package repository 文章系列数据访问层
+package repository
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+	"kuaiyu/internal/model"
+)
+
+// ===========================================
+// 系列仓库
+// ===========================================
+
+// SeriesRepository 系列仓库
+type SeriesRepository struct {
+	*BaseRepository
+}
+
+// NewSeriesRepository 创建系列仓库
+func NewSeriesRepository() *SeriesRepository {
+	return &SeriesRepository{
+		BaseRepository: NewBaseRepository(),
+	}
+}
+
+// ===========================================
+// 查询方法
+// ===========================================
+
+// FindAll 查找所有系列
+func (r *SeriesRepository) FindAll() ([]model.Series, error) {
+	var series []model.Series
+	err := r.db.Order("created_at DESC").Find(&series).Error
+	return series, err
+}
+
+// FindByID 根据 ID 查找
+func (r *SeriesRepository) FindByID(id uint) (*model.Series, error) {
+	var series model.Series
+	if err := r.db.First(&series, id).Error; err != nil {
+		return nil, err
+	}
+	return &series, nil
+}
+
+// FindBySlug 根据 slug 查找
+func (r *SeriesRepository) FindBySlug(slug string) (*model.Series, error) {
+	var series model.Series
+	if err := r.db.Where("slug = ?", slug).First(&series).Error; err != nil {
+		return nil, err
+	}
+	return &series, nil
+}
+
+// FindPosts 按系列顺序查找文章，publishedOnly 为 true 时只返回公开文章
+func (r *SeriesRepository) FindPosts(seriesID uint, publishedOnly bool) ([]model.Post, error) {
+	var posts []model.Post
+	query := r.db.Model(&model.Post{}).
+		Joins("JOIN series_posts ON series_posts.post_id = posts.id").
+		Where("series_posts.series_id = ?", seriesID)
+	if publishedOnly {
+		query = query.Scopes(PublishedScope("posts"), ListedScope("posts"))
+	}
+	err := query.Preload("Tags").
+		Order("series_posts.position ASC").
+		Find(&posts).Error
+	return posts, err
+}
+
+// CountPosts 统计各系列的文章数，publishedOnly 为 true 时只统计公开文章
+func (r *SeriesRepository) CountPosts(publishedOnly bool) (map[uint]int, error) {
+	var rows []struct {
+		SeriesID uint
+		Count    int
+	}
+	query := r.db.Table("series_posts").
+		Select("series_posts.series_id, COUNT(*) AS count").
+		Joins("JOIN posts ON posts.id = series_posts.post_id AND posts.deleted_at IS NULL")
+	if publishedOnly {
+		query = query.Scopes(PublishedScope("posts"), ListedScope("posts"))
+	}
+	if err := query.Group("series_posts.series_id").Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	counts := make(map[uint]int, len(rows))
+	for _, row := range rows {
+		counts[row.SeriesID] = row.Count
+	}
+	return counts, nil
+}
+
+// FindPostSeries 查找文章所属系列及其在公开文章中的位置和前后篇
+// 文章不属于任何系列时返回 nil
+func (r *SeriesRepository) FindPostSeries(postID uint) (*model.PostSeriesVO, error) {
+	var membership model.SeriesPost
+	err := r.db.Where("post_id = ?", postID).Limit(1).Find(&membership).Error
+	if err != nil || membership.SeriesID == 0 {
+		return nil, err
+	}
+
+	series, err := r.FindByID(membership.SeriesID)
+	if err != nil {
+		return nil, err
+	}
+
+	posts, err := r.FindPosts(series.ID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	vo := &model.PostSeriesVO{
+		ID:    series.ID,
+		Name:  series.Name,
+		Slug:  series.Slug,
+		Total: len(posts),
+	}
+	for i := range posts {
+		if posts[i].ID != postID {
+			continue
+		}
+		vo.Position = i + 1
+		if i > 0 {
+			vo.Prev = posts[i-1].ToSeriesNavVO(i)
+		}
+		if i < len(posts)-1 {
+			vo.Next = posts[i+1].ToSeriesNavVO(i + 2)
+		}
+		break
+	}
+
+	return vo, nil
+}
+
+// FindSeriesByPosts 批量查找文章所属系列，返回 文章 ID -> 系列
+func (r *SeriesRepository) FindSeriesByPosts(postIDs []uint) (map[uint]model.Series, error) {
+	result := make(map[uint]model.Series)
+	if len(postIDs) == 0 {
+		return result, nil
+	}
+
+	var rows []struct {
+		PostID uint
+		model.Series
+	}
+	err := r.db.Table("series_posts").
+		Select("series_posts.post_id, series.*").
+		Joins("JOIN series ON series.id = series_posts.series_id").
+		Where("series_posts.post_id IN ?", postIDs).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		result[row.PostID] = row.Series
+	}
+	return result, nil
+}
+
+// SlugExists 检查 slug 是否存在
+func (r *SeriesRepository) SlugExists(slug string, excludeID uint) bool {
+	var count int64
+	query := r.db.Model(&model.Series{}).Where("slug = ?", slug)
+	if excludeID > 0 {
+		query = query.Where("id != ?", excludeID)
+	}
+	query.Count(&count)
+	return count > 0
+}
+
+// ===========================================
+// 修改方法
+// ===========================================
+
+// SetPosts 设置系列文章及顺序（整体替换）
+// 文章已属于其他系列时返回错误，需先从原系列移出
+func (r *SeriesRepository) SetPosts(seriesID uint, postIDs []uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		seen := make(map[uint]bool, len(postIDs))
+		for _, id := range postIDs {
+			if seen[id] {
+				return fmt.Errorf("文章 %d 重复", id)
+			}
+			seen[id] = true
+		}
+
+		if len(postIDs) > 0 {
+			var count int64
+			if err := tx.Model(&model.Post{}).Where("id IN ?", postIDs).Count(&count).Error; err != nil {
+				return err
+			}
+			if int(count) != len(postIDs) {
+				return fmt.Errorf("部分文章不存在")
+			}
+
+			var conflict model.SeriesPost
+			err := tx.Where("post_id IN ? AND series_id <> ?", postIDs, seriesID).Limit(1).Find(&conflict).Error
+			if err != nil {
+				return err
+			}
+			if conflict.PostID > 0 {
+				return fmt.Errorf("文章 %d 已属于其他系列", conflict.PostID)
+			}
+		}
+
+		if err := tx.Where("series_id = ?", seriesID).Delete(&model.SeriesPost{}).Error; err != nil {
+			return err
+		}
+		if len(postIDs) == 0 {
+			return nil
+		}
+
+		items := make([]model.SeriesPost, len(postIDs))
+		for i, id := range postIDs {
+			items[i] = model.SeriesPost{SeriesID: seriesID, PostID: id, Position: i + 1}
+		}
+		return tx.Create(&items).Error
+	})
+}
+
+// RemovePost 将文章移出其所属系列
+func (r *SeriesRepository) RemovePost(postID uint) error {
+	return r.db.Where("post_id = ?", postID).Delete(&model.SeriesPost{}).Error
+}
+
+// Delete 删除系列及其文章关联（文章本身保留）
+func (r *SeriesRepository) Delete(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("series_id = ?", id).Delete(&model.SeriesPost{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&model.Series{}, id).Error
+	})
+}
@@ -0,0 +1,89 @@
+// Package repository 标签数据访问层
+package repository
+
+import (
+	"strings"
+
+	"kuaiyu/internal/model"
+	"kuaiyu/pkg/utils"
+)
+
+// ===========================================
+// 标签仓库
+// ===========================================
+
+// TagRepository 标签仓库
+type TagRepository struct {
+	*BaseRepository
+}
+
+// NewTagRepository 创建标签仓库
+func NewTagRepository() *TagRepository {
+	return &TagRepository{
+		BaseRepository: NewBaseRepository(),
+	}
+}
+
+// ===========================================
+// 查询方法
+// ===========================================
+
+// FindAll 查找所有标签
+func (r *TagRepository) FindAll() ([]model.Tag, error) {
+	var tags []model.Tag
+	err := r.db.Order("id ASC").Find(&tags).Error
+	return tags, err
+}
+
+// FindByName 根据名称查找（不区分大小写）
+func (r *TagRepository) FindByName(name string) (*model.Tag, error) {
+	var tag model.Tag
+	err := r.db.Where("LOWER(name) = ?", strings.ToLower(name)).First(&tag).Error
+	if err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+// FindBySlug 根据 slug 查找
+func (r *TagRepository) FindBySlug(slug string) (*model.Tag, error) {
+	var tag model.Tag
+	err := r.db.Where("slug = ?", slug).First(&tag).Error
+	if err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+// ===========================================
+// 创建方法
+// ===========================================
+
+// Create 创建标签
+func (r *TagRepository) Create(tag *model.Tag) error {
+	if tag.Slug == "" {
+		tag.Slug = utils.GenerateSlug(tag.Name)
+	}
+	return r.db.Create(tag).Error
+}
+
+// FindOrCreate 按名称查找标签，不存在时创建
+// 返回的 bool 表示是否新建
+func (r *TagRepository) FindOrCreate(name string) (*model.Tag, bool, error) {
+	if tag, err := r.FindByName(name); err == nil {
+		return tag, false, nil
+	}
+
+	// 名称不同但 slug 相同（如 "Go" 与 "go!"）时追加随机后缀
+	tag := model.Tag{Name: name, Slug: utils.GenerateSlug(name)}
+	if tag.Slug == "" {
+		tag.Slug = utils.GenerateRandomString(8)
+	} else if _, err := r.FindBySlug(tag.Slug); err == nil {
+		tag.Slug = tag.Slug + "-" + utils.GenerateRandomString(4)
+	}
+
+	if err := r.Create(&tag); err != nil {
+		return nil, false, err
+	}
+	return &tag, true, nil
+}
@@ -0,0 +1,113 @@
+// Package repository 文章翻译数据访问层
+package repository
+
+import (
+	"kuaiyu/internal/model"
+)
+
+// ===========================================
+// 文章翻译仓库
+// ===========================================
+
+// TranslationRepository 文章翻译仓库
+type TranslationRepository struct {
+	*BaseRepository
+}
+
+// NewTranslationRepository 创建文章翻译仓库
+func NewTranslationRepository() *TranslationRepository {
+	return &TranslationRepository{
+		BaseRepository: NewBaseRepository(),
+	}
+}
+
+// ===========================================
+// 查询方法
+// ===========================================
+
+// FindByPost 查找文章的全部翻译
+func (r *TranslationRepository) FindByPost(postID uint) ([]model.PostTranslation, error) {
+	var translations []model.PostTranslation
+	err := r.db.Where("post_id = ?", postID).
+		Order("locale ASC").
+		Find(&translations).Error
+	return translations, err
+}
+
+// FindByPostAndLocale 查找文章指定语言的翻译
+func (r *TranslationRepository) FindByPostAndLocale(postID uint, locale string) (*model.PostTranslation, error) {
+	var translation model.PostTranslation
+	err := r.db.Where("post_id = ? AND locale = ?", postID, locale).First(&translation).Error
+	if err != nil {
+		return nil, err
+	}
+	return &translation, nil
+}
+
+// FindBySlug 根据 slug 查找翻译
+func (r *TranslationRepository) FindBySlug(slug string) (*model.PostTranslation, error) {
+	var translation model.PostTranslation
+	err := r.db.Where("slug = ?", slug).First(&translation).Error
+	if err != nil {
+		return nil, err
+	}
+	return &translation, nil
+}
+
+// FindSummaries 批量查找多篇文章的翻译（不含正文），按文章 ID 分组
+func (r *TranslationRepository) FindSummaries(postIDs []uint) (map[uint][]model.PostTranslation, error) {
+	result := make(map[uint][]model.PostTranslation)
+	if len(postIDs) == 0 {
+		return result, nil
+	}
+
+	var translations []model.PostTranslation
+	err := r.db.Select("id", "post_id", "locale", "title", "slug", "excerpt", "created_at", "updated_at").
+		Where("post_id IN ?", postIDs).
+		Order("locale ASC").
+		Find(&translations).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, t := range translations {
+		result[t.PostID] = append(result[t.PostID], t)
+	}
+	return result, nil
+}
+
+// SlugExists 检查 slug 是否已被其他翻译或文章使用（文章和翻译共用 slug 空间）
+func (r *TranslationRepository) SlugExists(slug string, excludeID uint) bool {
+	var count int64
+	query := r.db.Model(&model.PostTranslation{}).Where("slug = ?", slug)
+	if excludeID > 0 {
+		query = query.Where("id != ?", excludeID)
+	}
+	query.Count(&count)
+	if count > 0 {
+		return true
+	}
+
+	r.db.Model(&model.Post{}).Where("slug = ?", slug).Count(&count)
+	return count > 0
+}
+
+// ===========================================
+// 修改方法
+// ===========================================
+
+// Save 创建或更新翻译
+func (r *TranslationRepository) Save(translation *model.PostTranslation) error {
+	return r.db.Save(translation).Error
+}
+
+// Delete 删除文章指定语言的翻译
+func (r *TranslationRepository) Delete(postID uint, locale string) error {
+	return r.db.Where("post_id = ? AND locale = ?", postID, locale).
+		Delete(&model.PostTranslation{}).Error
+}
+
+// DeleteByPost 删除文章的全部翻译（文章删除时调用）
+func (r *TranslationRepository) DeleteByPost(postID uint) error {
+	return r.db.Where("post_id = ?", postID).Delete(&model.PostTranslation{}).Error
+}
@@ -43,13 +43,16 @@ func Setup(r *gin.Engine) {
 func setupPublicRoutes(api *gin.RouterGroup) {
 	// 文章
 	postHandler := handler.NewPostHandler()
+	relatedHandler := handler.NewRelatedHandler()
 	posts := api.Group("/posts")
 	posts.Use(middleware.PublicRateLimit())
 	{
 		posts.GET("", postHandler.List)
 		posts.GET("/featured", postHandler.Featured)
 		posts.GET("/:slug", postHandler.GetBySlug)
+		posts.GET("/:slug/related", relatedHandler.List)
 		posts.POST("/:id/views", postHandler.IncrementViews)
+		posts.POST("/:id/unlock", middleware.UnlockRateLimit(), postHandler.Unlock)
 	}
 
 	// 归档
@@ -88,18 +91,67 @@ func setupPublicRoutes(api *gin.RouterGroup) {
 	{
 		comments.GET("", commentHandler.List)
 		comments.POST("", middleware.CommentRateLimit(), commentHandler.Create)
+		comments.GET("/challenge", middleware.PublicRateLimit(), commentHandler.Challenge)
+		comments.GET("/:id/replies", commentHandler.Replies)
+		comments.PUT("/:id", middleware.CommentRateLimit(), commentHandler.Edit)
+		comments.DELETE("/:id", middleware.CommentRateLimit(), commentHandler.Withdraw)
 	}
 
-	// RSS
+	// 读者表态
+	reactionHandler := handler.NewReactionHandler()
+	reactions := api.Group("/reactions")
+	{
+		reactions.GET("", middleware.PublicRateLimit(), reactionHandler.Summary)
+		reactions.POST("", middleware.ReactionRateLimit(), reactionHandler.Toggle)
+	}
+
+	// 全文检索
+	searchHandler := handler.NewSearchHandler()
+	api.GET("/search", middleware.PublicRateLimit(), searchHandler.Search)
+
+	// 系列
+	seriesHandler := handler.NewSeriesHandler()
+	series := api.Group("/series")
+	{
+		series.GET("", seriesHandler.List)
+		series.GET("/:slug", seriesHandler.GetBySlug)
+	}
+
+	// 草稿预览
+	previewHandler := handler.NewPreviewHandler()
+	api.GET("/preview/:token", middleware.PublicRateLimit(), previewHandler.Get)
+
+	// 自定义重定向
+	redirectHandler := handler.NewRedirectHandler()
+	api.GET("/redirects/resolve", middleware.PublicRateLimit(), redirectHandler.Resolve)
+
+	// 订阅源（RSS / Atom / JSON Feed）
 	rssHandler := handler.NewRSSHandler()
 	api.GET("/rss", rssHandler.Feed)
+	api.GET("/atom", rssHandler.AtomFeed)
+	api.GET("/feed.json", rssHandler.JSONFeed)
 	api.GET("/rss/posts", rssHandler.PostsFeed)
 	api.GET("/rss/life", rssHandler.LifeFeed)
+	api.GET("/rss/all", rssHandler.AllFeed)
+	api.GET("/rss/series/:slug", rssHandler.SeriesFeed)
+	api.GET("/tags/:slug/rss", rssHandler.TagFeed)
 
 	// SEO
 	seoHandler := handler.NewSEOHandler()
 	api.GET("/sitemap.xml", seoHandler.Sitemap)
+	api.GET("/sitemap/:file", seoHandler.SitemapSection)
 	api.GET("/robots.txt", seoHandler.Robots)
+	api.GET("/indexnow-key.txt", seoHandler.IndexNowKey)
+
+	// 邮件通知退订
+	notificationHandler := handler.NewNotificationHandler()
+	api.GET("/notifications/unsubscribe", middleware.PublicRateLimit(), notificationHandler.Unsubscribe)
+	api.POST("/notifications/unsubscribe", middleware.PublicRateLimit(), notificationHandler.Unsubscribe)
+	api.GET("/seo/meta", middleware.PublicRateLimit(), seoHandler.Meta)
+
+	// 文章分享图
+	ogHandler := handler.NewOGHandler()
+	api.GET("/og/:file", middleware.PublicRateLimit(), ogHandler.Image)
 
 	// 埋点
 	analyticsHandler := handler.NewAnalyticsHandler()
@@ -137,6 +189,10 @@ func setupAdminRoutes(api *gin.RouterGroup) {
 
 		// 文章管理
 		postHandler := handler.NewPostHandler()
+		revisionHandler := handler.NewRevisionHandler()
+		previewHandler := handler.NewPreviewHandler()
+		relatedHandler := handler.NewRelatedHandler()
+		translationHandler := handler.NewTranslationHandler()
 		posts := auth.Group("/posts")
 		{
 			posts.GET("", postHandler.AdminList)
@@ -144,6 +200,34 @@ func setupAdminRoutes(api *gin.RouterGroup) {
 			posts.POST("", postHandler.Create)
 			posts.PUT("/:id", postHandler.Update)
 			posts.DELETE("/:id", postHandler.Delete)
+
+			// 修订历史
+			posts.GET("/:id/revisions", revisionHandler.ListPost)
+			posts.GET("/:id/revisions/diff", revisionHandler.DiffPost)
+			posts.GET("/:id/revisions/:rid", revisionHandler.GetPost)
+			posts.POST("/:id/revisions/:rid/restore", revisionHandler.RestorePost)
+
+			// 草稿预览链接
+			posts.GET("/:id/previews", previewHandler.ListPost)
+			posts.POST("/:id/previews", previewHandler.CreatePost)
+
+			// 相关文章
+			posts.GET("/:id/related", relatedHandler.AdminList)
+			posts.PUT("/:id/related/:rid", relatedHandler.SetOverride)
+			posts.DELETE("/:id/related/:rid", relatedHandler.DeleteOverride)
+			posts.POST("/related/rebuild", relatedHandler.Rebuild)
+
+			// 置顶与精选
+			posts.GET("/pinned", postHandler.AdminPinned)
+			posts.PUT("/:id/pin", postHandler.Pin)
+			posts.DELETE("/:id/pin", postHandler.Unpin)
+			posts.GET("/featured", postHandler.AdminFeatured)
+			posts.PUT("/featured", postHandler.SetFeatured)
+
+			// 翻译
+			posts.GET("/:id/translations", translationHandler.List)
+			posts.PUT("/:id/translations/:locale", translationHandler.Save)
+			posts.DELETE("/:id/translations/:locale", translationHandler.Delete)
 		}
 
 		// 生活记录管理
@@ -155,6 +239,41 @@ func setupAdminRoutes(api *gin.RouterGroup) {
 			life.POST("", lifeHandler.Create)
 			life.PUT("/:id", lifeHandler.Update)
 			life.DELETE("/:id", lifeHandler.Delete)
+
+			// 修订历史
+			life.GET("/:id/revisions", revisionHandler.ListLife)
+			life.GET("/:id/revisions/diff", revisionHandler.DiffLife)
+			life.GET("/:id/revisions/:rid", revisionHandler.GetLife)
+			life.POST("/:id/revisions/:rid/restore", revisionHandler.RestoreLife)
+
+			// 草稿预览链接
+			life.GET("/:id/previews", previewHandler.ListLife)
+			life.POST("/:id/previews", previewHandler.CreateLife)
+		}
+
+		// 系列管理
+		seriesHandler := handler.NewSeriesHandler()
+		series := auth.Group("/series")
+		{
+			series.GET("", seriesHandler.AdminList)
+			series.GET("/:id", seriesHandler.AdminGet)
+			series.POST("", seriesHandler.Create)
+			series.PUT("/:id", seriesHandler.Update)
+			series.PUT("/:id/posts", seriesHandler.SetPosts)
+			series.DELETE("/:id", seriesHandler.Delete)
+		}
+
+		// 撤销预览链接
+		auth.POST("/previews/:id/revoke", previewHandler.Revoke)
+
+		// 重定向管理
+		redirectHandler := handler.NewRedirectHandler()
+		redirects := auth.Group("/redirects")
+		{
+			redirects.GET("", redirectHandler.List)
+			redirects.POST("", redirectHandler.Create)
+			redirects.PUT("/:id", redirectHandler.Update)
+			redirects.DELETE("/:id", redirectHandler.Delete)
 		}
 
 		// 标签管理
@@ -172,12 +291,35 @@ func setupAdminRoutes(api *gin.RouterGroup) {
 		comments := auth.Group("/comments")
 		{
 			comments.GET("", commentHandler.AdminList)
+			comments.GET("/:id/edits", commentHandler.Edits)
 			comments.POST("/:id/toggle-pin", commentHandler.TogglePin)
 			comments.POST("/:id/reply", commentHandler.AdminReply)
 			comments.PUT("/:id", commentHandler.UpdateStatus)
 			comments.DELETE("/:id", commentHandler.Delete)
 		}
 
+		// 检索索引
+		searchHandler := handler.NewSearchHandler()
+		auth.POST("/search/rebuild", searchHandler.Rebuild)
+
+		// 搜索引擎推送
+		pingHandler := handler.NewPingHandler()
+		pings := auth.Group("/pings")
+		{
+			pings.GET("", pingHandler.List)
+			pings.POST("", pingHandler.Submit)
+			pings.POST("/:id/retry", pingHandler.Retry)
+		}
+
+		// 文章导入
+		importHandler := handler.NewImportHandler()
+		auth.POST("/import/posts", importHandler.ImportPosts)
+
+		// 全站备份
+		archiveHandler := handler.NewArchiveHandler()
+		auth.GET("/archive/export", archiveHandler.Export)
+		auth.POST("/archive/restore", archiveHandler.Restore)
+
 		// 文件上传
 		uploadHandler := handler.NewUploadHandler()
 		auth.POST("/upload", middleware.UploadRateLimit(), uploadHandler.Upload)
@@ -194,6 +336,10 @@ func setupAdminRoutes(api *gin.RouterGroup) {
 			analytics.GET("/charts", analyticsHandler.Charts)
 		}
 
+		// 读者表态趋势
+		reactionHandler := handler.NewReactionHandler()
+		auth.GET("/reactions/trends", reactionHandler.Trends)
+
 		// 账单管理
 		billHandler := handler.NewBillHandler()
 		bills := auth.Group("/bills")
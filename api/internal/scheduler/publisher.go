@@ -0,0 +1,126 @@
+// Package scheduler 后台调度任务
+// 提供在 API 进程内运行的周期性任务
+package scheduler
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"kuaiyu/internal/database"
+	"kuaiyu/internal/model"
+	"kuaiyu/pkg/constants"
+)
+
+// ===========================================
+// 定时发布调度器
+// ===========================================
+
+// publishBatchSize 每轮每种内容最多发布的数量
+const publishBatchSize = 100
+
+// PublishHook 内容发布后的回调
+// 多副本部署时只有抢到发布权的实例会触发回调
+type PublishHook func(targetType string, id uint)
+
+// Publisher 定时发布调度器
+// 周期性地将到期的 scheduled 内容切换为 published
+type Publisher struct {
+	db       *gorm.DB
+	interval time.Duration
+	hooks    []PublishHook
+	stop     chan struct{}
+	once     sync.Once
+}
+
+// NewPublisher 创建定时发布调度器
+func NewPublisher(interval time.Duration) *Publisher {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	return &Publisher{
+		db:       database.Get(),
+		interval: interval,
+		stop:     make(chan struct{}),
+	}
+}
+
+// OnPublish 注册发布回调
+func (p *Publisher) OnPublish(hook PublishHook) {
+	p.hooks = append(p.hooks, hook)
+}
+
+// Start 启动调度协程
+func (p *Publisher) Start() {
+	go func() {
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+
+		p.RunOnce()
+		for {
+			select {
+			case <-ticker.C:
+				p.RunOnce()
+			case <-p.stop:
+				return
+			}
+		}
+	}()
+
+	log.Printf("Scheduled publisher started (interval: %s)", p.interval)
+}
+
+// Stop 停止调度协程
+func (p *Publisher) Stop() {
+	p.once.Do(func() {
+		close(p.stop)
+	})
+}
+
+// RunOnce 执行一轮发布，返回本实例发布的内容数量
+func (p *Publisher) RunOnce() int {
+	published := 0
+	published += p.publishDue("post", &model.Post{})
+	published += p.publishDue("life", &model.LifeRecord{})
+	return published
+}
+
+// publishDue 发布某种内容中已到期的记录
+func (p *Publisher) publishDue(targetType string, m interface{}) int {
+	var ids []uint
+	err := p.db.Model(m).
+		Where("status = ? AND published_at <= ?", constants.PostStatusScheduled, time.Now()).
+		Order("published_at ASC").
+		Limit(publishBatchSize).
+		Pluck("id", &ids).Error
+	if err != nil {
+		log.Printf("Failed to query scheduled %s: %v", targetType, err)
+		return 0
+	}
+
+	published := 0
+	for _, id := range ids {
+		// 条件更新保证多副本同时扫描时只有一个实例生效
+		result := p.db.Model(m).
+			Where("id = ? AND status = ?", id, constants.PostStatusScheduled).
+			Update("status", constants.PostStatusPublished)
+		if result.Error != nil {
+			log.Printf("Failed to publish scheduled %s %d: %v", targetType, id, result.Error)
+			continue
+		}
+		if result.RowsAffected == 0 {
+			// 已被其他实例发布或已被取消
+			continue
+		}
+
+		published++
+		log.Printf("Scheduled %s %d published", targetType, id)
+
+		for _, hook := range p.hooks {
+			hook(targetType, id)
+		}
+	}
+
+	return published
+}
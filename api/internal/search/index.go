@@ -0,0 +1,277 @@
+// Package search 站内全文检索
+// 基于 MySQL 存储的倒排索引，支持中英文混合分词、BM25 打分和关键词高亮
+package search
+
+import (
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+	"kuaiyu/internal/database"
+	"kuaiyu/internal/model"
+	"kuaiyu/internal/repository"
+	"kuaiyu/pkg/constants"
+	"kuaiyu/pkg/tokenizer"
+	"kuaiyu/pkg/utils"
+)
+
+// ===========================================
+// 文档类型
+// ===========================================
+
+const (
+	DocTypePost    = "post"
+	DocTypeLife    = "life"
+	DocTypeComment = "comment"
+)
+
+// titleBoost 标题词项的权重
+const titleBoost = 3
+
+// ===========================================
+// 增量索引
+// ===========================================
+
+// SyncPost 同步文章索引：公开则写入索引，否则移除
+// 不公开列出和加密的文章不进入索引，避免通过检索暴露链接或正文
+func SyncPost(p *model.Post) {
+	if !isPublic(p.Status, p.PublishedAt) || p.GetVisibility() != string(constants.PostVisibilityPublic) {
+		Remove(DocTypePost, p.ID)
+		return
+	}
+	logError(index(postDocument(p)), DocTypePost, p.ID)
+}
+
+// SyncLife 同步生活记录索引
+func SyncLife(l *model.LifeRecord) {
+	if !isPublic(l.Status, l.PublishedAt) {
+		Remove(DocTypeLife, l.ID)
+		return
+	}
+	logError(index(lifeDocument(l)), DocTypeLife, l.ID)
+}
+
+// SyncComment 同步评论索引：只索引已审核通过的评论
+func SyncComment(c *model.Comment) {
+	if c.Status != string(constants.CommentStatusApproved) {
+		Remove(DocTypeComment, c.ID)
+		return
+	}
+	logError(index(commentDocument(c)), DocTypeComment, c.ID)
+}
+
+// Remove 从索引中移除文档
+func Remove(docType string, id uint) {
+	err := database.Get().Transaction(func(tx *gorm.DB) error {
+		return removeTx(tx, docType, id)
+	})
+	if err != nil {
+		log.Printf("Failed to remove %s %d from search index: %v", docType, id, err)
+	}
+}
+
+// OnPublish 定时发布后的回调，将新发布的内容加入索引
+func OnPublish(targetType string, id uint) {
+	db := database.Get()
+	switch targetType {
+	case DocTypePost:
+		var post model.Post
+		if err := db.First(&post, id).Error; err == nil {
+			SyncPost(&post)
+		}
+	case DocTypeLife:
+		var record model.LifeRecord
+		if err := db.First(&record, id).Error; err == nil {
+			SyncLife(&record)
+		}
+	}
+}
+
+// ===========================================
+// 全量重建
+// ===========================================
+
+// EnsureIndex 索引为空时执行全量重建（用于首次部署）
+func EnsureIndex() {
+	var count int64
+	if err := database.Get().Model(&model.SearchDocument{}).Count(&count).Error; err != nil {
+		log.Printf("Failed to check search index: %v", err)
+		return
+	}
+	if count > 0 {
+		return
+	}
+	if _, err := Rebuild(); err != nil {
+		log.Printf("Failed to build search index: %v", err)
+	}
+}
+
+// Rebuild 清空并重建全部索引，返回索引的文档数
+func Rebuild() (int, error) {
+	db := database.Get()
+
+	if err := db.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(&model.SearchTerm{}).Error; err != nil {
+		return 0, err
+	}
+	if err := db.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(&model.SearchDocument{}).Error; err != nil {
+		return 0, err
+	}
+
+	total := 0
+
+	var posts []model.Post
+	if err := db.Scopes(repository.PublishedScope("posts")).Find(&posts).Error; err != nil {
+		return total, err
+	}
+	for i := range posts {
+		if posts[i].GetVisibility() != string(constants.PostVisibilityPublic) {
+			continue
+		}
+		if err := index(postDocument(&posts[i])); err != nil {
+			return total, err
+		}
+		total++
+	}
+
+	var records []model.LifeRecord
+	if err := db.Scopes(repository.PublishedScope("life_records")).Find(&records).Error; err != nil {
+		return total, err
+	}
+	for i := range records {
+		if err := index(lifeDocument(&records[i])); err != nil {
+			return total, err
+		}
+		total++
+	}
+
+	var comments []model.Comment
+	if err := db.Where("status = ?", constants.CommentStatusApproved).Find(&comments).Error; err != nil {
+		return total, err
+	}
+	for i := range comments {
+		if err := index(commentDocument(&comments[i])); err != nil {
+			return total, err
+		}
+		total++
+	}
+
+	log.Printf("Search index rebuilt: %d documents", total)
+
+	return total, nil
+}
+
+// ===========================================
+// 内部实现
+// ===========================================
+
+// isPublic 是否为公开可见的内容
+func isPublic(status string, publishedAt *time.Time) bool {
+	if status != string(constants.PostStatusPublished) {
+		return false
+	}
+	return publishedAt == nil || !publishedAt.After(time.Now())
+}
+
+// postDocument 构建文章检索文档
+func postDocument(p *model.Post) *model.SearchDocument {
+	return &model.SearchDocument{
+		DocType:     DocTypePost,
+		DocID:       p.ID,
+		Title:       p.Title,
+		Body:        utils.StripMarkdown(p.Content),
+		Slug:        p.Slug,
+		PublishedAt: p.PublishedAt,
+	}
+}
+
+// lifeDocument 构建生活记录检索文档
+func lifeDocument(l *model.LifeRecord) *model.SearchDocument {
+	return &model.SearchDocument{
+		DocType:     DocTypeLife,
+		DocID:       l.ID,
+		Title:       l.Title,
+		Body:        utils.StripMarkdown(l.Content),
+		PublishedAt: l.PublishedAt,
+	}
+}
+
+// commentDocument 构建评论检索文档
+func commentDocument(c *model.Comment) *model.SearchDocument {
+	createdAt := c.CreatedAt
+	return &model.SearchDocument{
+		DocType:     DocTypeComment,
+		DocID:       c.ID,
+		Title:       c.Nickname,
+		Body:        c.Content,
+		TargetType:  c.CommentType,
+		TargetID:    c.TargetID,
+		PublishedAt: &createdAt,
+	}
+}
+
+// index 写入单个文档的索引（先删后写）
+func index(doc *model.SearchDocument) error {
+	titleTF := tokenizer.Count(tokenizer.Tokenize(doc.Title))
+	bodyTF := tokenizer.Count(tokenizer.Tokenize(doc.Body))
+
+	length := 0
+	for _, n := range titleTF {
+		length += n * titleBoost
+	}
+	for _, n := range bodyTF {
+		length += n
+	}
+	doc.Length = length
+
+	terms := make([]model.SearchTerm, 0, len(titleTF)+len(bodyTF))
+	for term, n := range titleTF {
+		terms = append(terms, model.SearchTerm{
+			Term:    term,
+			DocType: doc.DocType,
+			DocID:   doc.DocID,
+			TitleTF: n,
+			BodyTF:  bodyTF[term],
+			Length:  length,
+		})
+	}
+	for term, n := range bodyTF {
+		if _, ok := titleTF[term]; ok {
+			continue
+		}
+		terms = append(terms, model.SearchTerm{
+			Term:    term,
+			DocType: doc.DocType,
+			DocID:   doc.DocID,
+			BodyTF:  n,
+			Length:  length,
+		})
+	}
+
+	return database.Get().Transaction(func(tx *gorm.DB) error {
+		if err := removeTx(tx, doc.DocType, doc.DocID); err != nil {
+			return err
+		}
+		if err := tx.Create(doc).Error; err != nil {
+			return err
+		}
+		if len(terms) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(terms, 500).Error
+	})
+}
+
+// removeTx 在事务中删除文档及其词项
+func removeTx(tx *gorm.DB, docType string, id uint) error {
+	if err := tx.Where("doc_type = ? AND doc_id = ?", docType, id).Delete(&model.SearchTerm{}).Error; err != nil {
+		return err
+	}
+	return tx.Where("doc_type = ? AND doc_id = ?", docType, id).Delete(&model.SearchDocument{}).Error
+}
+
+// logError 记录索引错误，索引失败不影响主流程
+func logError(err error, docType string, id uint) {
+	if err != nil {
+		log.Printf("Failed to index %s %d: %v", docType, id, err)
+	}
+}
@@ -0,0 +1,211 @@
+package search
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"kuaiyu/internal/database"
+	"kuaiyu/internal/model"
+	"kuaiyu/pkg/tokenizer"
+)
+
+// ===========================================
+// 检索
+// ===========================================
+
+const (
+	// bm25K1 词频饱和参数
+	bm25K1 = 1.2
+	// bm25B 文档长度归一化参数
+	bm25B = 0.75
+	// snippetLength 摘要片段长度
+	snippetLength = 160
+)
+
+// Hit 命中的文档
+type Hit struct {
+	DocType string
+	DocID   uint
+	Score   float64
+}
+
+// Result 检索结果
+type Result struct {
+	Hits  []Hit
+	Total int64
+}
+
+// docKey 文档唯一键
+type docKey struct {
+	docType string
+	docID   uint
+}
+
+// posting 单个文档的打分中间结果
+type posting struct {
+	hit     Hit
+	matched int
+}
+
+// Query 检索文档，docType 为空时检索全部类型
+// 优先返回包含全部查询词项的文档，没有时退化为包含任一词项
+func Query(q, docType string, page, limit int) (*Result, error) {
+	terms := tokenizer.QueryTerms(q)
+	if len(terms) == 0 {
+		return &Result{}, nil
+	}
+
+	db := database.Get()
+
+	// 读取倒排列表
+	var rows []model.SearchTerm
+	query := db.Model(&model.SearchTerm{}).
+		Select("term, doc_type, doc_id, title_tf, body_tf, length").
+		Where("term IN ?", terms)
+	if docType != "" {
+		query = query.Where("doc_type = ?", docType)
+	}
+	if err := query.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	// 文档总数和平均长度
+	var stats struct {
+		Count  int64
+		AvgLen float64
+	}
+	statsQuery := db.Model(&model.SearchDocument{}).Select("COUNT(*) AS count, COALESCE(AVG(length), 0) AS avg_len")
+	if docType != "" {
+		statsQuery = statsQuery.Where("doc_type = ?", docType)
+	}
+	if err := statsQuery.Scan(&stats).Error; err != nil {
+		return nil, err
+	}
+	if stats.Count == 0 {
+		return &Result{}, nil
+	}
+	if stats.AvgLen <= 0 {
+		stats.AvgLen = 1
+	}
+
+	// 文档频率
+	df := make(map[string]int)
+	for _, row := range rows {
+		df[row.Term]++
+	}
+
+	// BM25 打分（标题词频加权）
+	postings := make(map[docKey]*posting)
+	for _, row := range rows {
+		key := docKey{row.DocType, row.DocID}
+		p, ok := postings[key]
+		if !ok {
+			p = &posting{hit: Hit{DocType: row.DocType, DocID: row.DocID}}
+			postings[key] = p
+		}
+
+		n := float64(stats.Count)
+		idf := math.Log(1 + (n-float64(df[row.Term])+0.5)/(float64(df[row.Term])+0.5))
+		tf := float64(row.TitleTF*titleBoost + row.BodyTF)
+		norm := bm25K1 * (1 - bm25B + bm25B*float64(row.Length)/stats.AvgLen)
+
+		p.hit.Score += idf * tf * (bm25K1 + 1) / (tf + norm)
+		p.matched++
+	}
+
+	hits := make([]Hit, 0, len(postings))
+	for _, p := range postings {
+		if p.matched == len(terms) {
+			hits = append(hits, p.hit)
+		}
+	}
+	if len(hits) == 0 {
+		for _, p := range postings {
+			hits = append(hits, p.hit)
+		}
+	}
+
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].DocID > hits[j].DocID
+	})
+
+	result := &Result{Total: int64(len(hits))}
+
+	offset := (page - 1) * limit
+	if offset >= len(hits) {
+		return result, nil
+	}
+	end := offset + limit
+	if end > len(hits) {
+		end = len(hits)
+	}
+	result.Hits = hits[offset:end]
+
+	return result, nil
+}
+
+// Search 检索并生成带高亮的结果视图
+func Search(q, docType string, page, limit int) ([]model.SearchHitVO, int64, error) {
+	result, err := Query(q, docType, page, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	items := make([]model.SearchHitVO, 0, len(result.Hits))
+	if len(result.Hits) == 0 {
+		return items, result.Total, nil
+	}
+
+	// 加载当前页文档
+	db := database.Get()
+	docs := make(map[docKey]model.SearchDocument)
+	for _, t := range []string{DocTypePost, DocTypeLife, DocTypeComment} {
+		var ids []uint
+		for _, hit := range result.Hits {
+			if hit.DocType == t {
+				ids = append(ids, hit.DocID)
+			}
+		}
+		if len(ids) == 0 {
+			continue
+		}
+
+		var rows []model.SearchDocument
+		if err := db.Where("doc_type = ? AND doc_id IN ?", t, ids).Find(&rows).Error; err != nil {
+			return nil, 0, err
+		}
+		for _, row := range rows {
+			docs[docKey{row.DocType, row.DocID}] = row
+		}
+	}
+
+	now := time.Now()
+	for _, hit := range result.Hits {
+		doc, ok := docs[docKey{hit.DocType, hit.DocID}]
+		if !ok {
+			continue
+		}
+		// 防御：索引中意外残留的未来内容不返回
+		if doc.PublishedAt != nil && doc.PublishedAt.After(now) {
+			continue
+		}
+
+		items = append(items, model.SearchHitVO{
+			Type:        doc.DocType,
+			ID:          doc.DocID,
+			Title:       tokenizer.Highlight(doc.Title, q, 0),
+			Snippet:     tokenizer.Highlight(doc.Body, q, snippetLength),
+			Slug:        doc.Slug,
+			TargetType:  doc.TargetType,
+			TargetID:    doc.TargetID,
+			Score:       math.Round(hit.Score*1000) / 1000,
+			PublishedAt: doc.PublishedAt,
+		})
+	}
+
+	return items, result.Total, nil
+}
@@ -0,0 +1,380 @@
+package sitemap
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+	"kuaiyu/internal/database"
+	"kuaiyu/internal/model"
+	"kuaiyu/internal/repository"
+	"kuaiyu/pkg/constants"
+	"kuaiyu/pkg/utils"
+)
+
+// maxURLs 单个子 sitemap 的最大 URL 数
+const maxURLs = int64(constants.SitemapMaxURLs)
+
+// ===========================================
+// XML 结构
+// ===========================================
+
+type sitemapIndex struct {
+	XMLName  xml.Name       `xml:"sitemapindex"`
+	Xmlns    string         `xml:"xmlns,attr"`
+	Sitemaps []sitemapEntry `xml:"sitemap"`
+}
+
+type sitemapEntry struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+type urlSet struct {
+	XMLName    xml.Name   `xml:"urlset"`
+	Xmlns      string     `xml:"xmlns,attr"`
+	XmlnsXhtml string     `xml:"xmlns:xhtml,attr"`
+	XmlnsImage string     `xml:"xmlns:image,attr"`
+	URLs       []urlEntry `xml:"url"`
+}
+
+type urlEntry struct {
+	Loc        string          `xml:"loc"`
+	LastMod    string          `xml:"lastmod,omitempty"`
+	ChangeFreq string          `xml:"changefreq,omitempty"`
+	Priority   string          `xml:"priority,omitempty"`
+	Links      []alternateLink `xml:"xhtml:link"`
+	Images     []imageEntry    `xml:"image:image"`
+}
+
+type alternateLink struct {
+	Rel      string `xml:"rel,attr"`
+	Hreflang string `xml:"hreflang,attr"`
+	Href     string `xml:"href,attr"`
+}
+
+type imageEntry struct {
+	Loc string `xml:"image:loc"`
+}
+
+// ===========================================
+// 索引
+// ===========================================
+
+// buildIndex 生成 sitemap 索引，没有内容的类型不列出
+func buildIndex(s *stats) (*Document, error) {
+	index := sitemapIndex{Xmlns: "http://www.sitemaps.org/schemas/sitemap/0.9"}
+	for _, name := range sections {
+		lastMod := formatLastMod(s.lastMod(name))
+		for page := 1; page <= s.pages(name); page++ {
+			index.Sitemaps = append(index.Sitemaps, sitemapEntry{
+				Loc:     fmt.Sprintf("%s/api/sitemap/%s-%d.xml", s.baseURL, name, page),
+				LastMod: lastMod,
+			})
+		}
+	}
+
+	var lastModified time.Time
+	for _, name := range sections {
+		lastModified = latest(lastModified, s.lastMod(name))
+	}
+	return render(index, lastModified)
+}
+
+// ===========================================
+// 子 sitemap
+// ===========================================
+
+// buildSection 生成子 sitemap
+func buildSection(s *stats, name string, page int) (*Document, error) {
+	db := database.Get()
+	offset := int(int64(page-1) * maxURLs)
+
+	var urls []urlEntry
+	var err error
+	switch name {
+	case SectionPages:
+		urls, err = pageURLs(db, s)
+	case SectionPosts:
+		urls, err = postURLs(db, s.baseURL, offset)
+	case SectionTranslations:
+		urls, err = translationURLs(db, s.baseURL, offset)
+	case SectionLife:
+		urls, err = lifeURLs(db, s.baseURL, offset)
+	case SectionTags:
+		urls, err = tagURLs(db, s.baseURL, offset)
+	default:
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	set := urlSet{
+		Xmlns:      "http://www.sitemaps.org/schemas/sitemap/0.9",
+		XmlnsXhtml: "http://www.w3.org/1999/xhtml",
+		XmlnsImage: "http://www.google.com/schemas/sitemap-image/1.1",
+		URLs:       urls,
+	}
+	return render(set, s.lastMod(name))
+}
+
+// pageURLs 首页、列表页和系列页，列表页的 lastmod 取其中内容的最后更新时间
+func pageURLs(db *gorm.DB, s *stats) ([]urlEntry, error) {
+	posts, life := s.posts.lastMod(), s.life.lastMod()
+
+	urls := []urlEntry{
+		{Loc: s.baseURL, LastMod: formatLastMod(latest(posts, life)), ChangeFreq: "daily", Priority: "1.0"},
+		{Loc: s.baseURL + "/blog", LastMod: formatLastMod(posts), ChangeFreq: "daily", Priority: "0.9"},
+		{Loc: s.baseURL + "/life", LastMod: formatLastMod(life), ChangeFreq: "weekly", Priority: "0.8"},
+		{Loc: s.baseURL + "/archive", LastMod: formatLastMod(posts), ChangeFreq: "weekly", Priority: "0.7"},
+		{Loc: s.baseURL + "/category", LastMod: formatLastMod(s.tags.lastMod()), ChangeFreq: "weekly", Priority: "0.7"},
+		{Loc: s.baseURL + "/guestbook", LastMod: formatLastMod(s.guestbook.lastMod()), ChangeFreq: "weekly", Priority: "0.6"},
+	}
+
+	// 系列页面（只收录包含公开文章的系列）
+	var rows []struct {
+		Slug       string
+		CoverImage string
+		LastMod    time.Time
+	}
+	if err := seriesQuery(db).
+		Select("series.slug, series.cover_image, GREATEST(series.updated_at, MAX(posts.updated_at)) AS last_mod").
+		Group("series.id, series.slug, series.cover_image, series.updated_at").
+		Order("series.id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		urls = append(urls, urlEntry{
+			Loc:        s.baseURL + "/series/" + row.Slug,
+			LastMod:    formatLastMod(row.LastMod),
+			ChangeFreq: "weekly",
+			Priority:   "0.7",
+			Images:     images(s.baseURL, row.CoverImage),
+		})
+	}
+	return urls, nil
+}
+
+// postURLs 文章页面，有翻译时互相标注 hreflang
+func postURLs(db *gorm.DB, baseURL string, offset int) ([]urlEntry, error) {
+	var posts []model.Post
+	if err := postsQuery(db).
+		Select("posts.id", "posts.slug", "posts.cover_image", "posts.updated_at").
+		Order("posts.id").
+		Offset(offset).Limit(int(maxURLs)).
+		Find(&posts).Error; err != nil {
+		return nil, err
+	}
+
+	postIDs := make([]uint, len(posts))
+	for i, post := range posts {
+		postIDs[i] = post.ID
+	}
+	translations, err := repository.NewTranslationRepository().FindSummaries(postIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	urls := make([]urlEntry, 0, len(posts))
+	for _, post := range posts {
+		urls = append(urls, urlEntry{
+			Loc:        baseURL + "/blog/" + post.Slug,
+			LastMod:    formatLastMod(post.UpdatedAt),
+			ChangeFreq: "monthly",
+			Priority:   "0.8",
+			Links:      alternates(baseURL, post.Slug, translations[post.ID]),
+			Images:     images(baseURL, post.CoverImage),
+		})
+	}
+	return urls, nil
+}
+
+// translationURLs 文章的其他语言版本
+func translationURLs(db *gorm.DB, baseURL string, offset int) ([]urlEntry, error) {
+	var rows []struct {
+		PostID     uint
+		Slug       string
+		UpdatedAt  time.Time
+		PostSlug   string
+		CoverImage string
+	}
+	if err := translationsQuery(db).
+		Select("post_translations.post_id, post_translations.slug, post_translations.updated_at, " +
+			"posts.slug AS post_slug, posts.cover_image").
+		Order("post_translations.id").
+		Offset(offset).Limit(int(maxURLs)).
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	postIDs := make([]uint, len(rows))
+	for i, row := range rows {
+		postIDs[i] = row.PostID
+	}
+	translations, err := repository.NewTranslationRepository().FindSummaries(postIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	urls := make([]urlEntry, 0, len(rows))
+	for _, row := range rows {
+		urls = append(urls, urlEntry{
+			Loc:        baseURL + "/blog/" + row.Slug,
+			LastMod:    formatLastMod(row.UpdatedAt),
+			ChangeFreq: "monthly",
+			Priority:   "0.8",
+			Links:      alternates(baseURL, row.PostSlug, translations[row.PostID]),
+			Images:     images(baseURL, row.CoverImage),
+		})
+	}
+	return urls, nil
+}
+
+// lifeURLs 生活记录页面
+func lifeURLs(db *gorm.DB, baseURL string, offset int) ([]urlEntry, error) {
+	var records []model.LifeRecord
+	if err := lifeQuery(db).
+		Select("life_records.id", "life_records.cover_image", "life_records.updated_at").
+		Order("life_records.id").
+		Offset(offset).Limit(int(maxURLs)).
+		Find(&records).Error; err != nil {
+		return nil, err
+	}
+
+	urls := make([]urlEntry, 0, len(records))
+	for _, record := range records {
+		urls = append(urls, urlEntry{
+			Loc:        baseURL + "/life/" + strconv.FormatUint(uint64(record.ID), 10),
+			LastMod:    formatLastMod(record.UpdatedAt),
+			ChangeFreq: "monthly",
+			Priority:   "0.6",
+			Images:     images(baseURL, record.CoverImage),
+		})
+	}
+	return urls, nil
+}
+
+// tagURLs 标签页面（只收录包含公开文章的标签），lastmod 取其中文章的最后更新时间
+func tagURLs(db *gorm.DB, baseURL string, offset int) ([]urlEntry, error) {
+	var rows []struct {
+		Slug    string
+		LastMod time.Time
+	}
+	if err := tagsQuery(db).
+		Select("tags.slug, MAX(posts.updated_at) AS last_mod").
+		Group("tags.id, tags.slug").
+		Order("tags.id").
+		Offset(offset).Limit(int(maxURLs)).
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	urls := make([]urlEntry, 0, len(rows))
+	for _, row := range rows {
+		urls = append(urls, urlEntry{
+			Loc:        baseURL + "/category/" + row.Slug,
+			LastMod:    formatLastMod(row.LastMod),
+			ChangeFreq: "weekly",
+			Priority:   "0.5",
+		})
+	}
+	return urls, nil
+}
+
+// ===========================================
+// 查询条件
+// ===========================================
+
+// postsQuery 公开列出的已发布文章
+func postsQuery(db *gorm.DB) *gorm.DB {
+	return db.Model(&model.Post{}).
+		Scopes(repository.PublishedScope("posts"), repository.ListedScope("posts"))
+}
+
+// translationsQuery 公开文章的翻译
+func translationsQuery(db *gorm.DB) *gorm.DB {
+	return db.Table("post_translations").
+		Joins("JOIN posts ON posts.id = post_translations.post_id AND posts.deleted_at IS NULL").
+		Scopes(repository.PublishedScope("posts"), repository.ListedScope("posts"))
+}
+
+// lifeQuery 已发布的生活记录
+func lifeQuery(db *gorm.DB) *gorm.DB {
+	return db.Model(&model.LifeRecord{}).
+		Scopes(repository.PublishedScope("life_records"))
+}
+
+// tagsQuery 标签与其公开文章的关联
+func tagsQuery(db *gorm.DB) *gorm.DB {
+	return db.Table("tags").
+		Joins("JOIN post_tags ON post_tags.tag_id = tags.id").
+		Joins("JOIN posts ON posts.id = post_tags.post_id AND posts.deleted_at IS NULL").
+		Scopes(repository.PublishedScope("posts"), repository.ListedScope("posts"))
+}
+
+// seriesQuery 系列与其公开文章的关联
+func seriesQuery(db *gorm.DB) *gorm.DB {
+	return db.Table("series").
+		Joins("JOIN series_posts ON series_posts.series_id = series.id").
+		Joins("JOIN posts ON posts.id = series_posts.post_id AND posts.deleted_at IS NULL").
+		Scopes(repository.PublishedScope("posts"), repository.ListedScope("posts"))
+}
+
+// guestbookQuery 已通过的留言
+func guestbookQuery(db *gorm.DB) *gorm.DB {
+	return db.Model(&model.Comment{}).
+		Where("comment_type = ? AND status = ?", "guestbook", constants.CommentStatusApproved)
+}
+
+// ===========================================
+// 辅助函数
+// ===========================================
+
+// alternates 文章各语言版本的 hreflang 链接，没有翻译时返回 nil
+func alternates(baseURL, slug string, translations []model.PostTranslation) []alternateLink {
+	if len(translations) == 0 {
+		return nil
+	}
+
+	links := []alternateLink{{Rel: "alternate", Hreflang: constants.DefaultLocale, Href: baseURL + "/blog/" + slug}}
+	for _, t := range translations {
+		links = append(links, alternateLink{Rel: "alternate", Hreflang: t.Locale, Href: baseURL + "/blog/" + t.Slug})
+	}
+	return append(links, alternateLink{Rel: "alternate", Hreflang: "x-default", Href: baseURL + "/blog/" + slug})
+}
+
+// images 封面图条目
+func images(baseURL, cover string) []imageEntry {
+	if cover == "" {
+		return nil
+	}
+	return []imageEntry{{Loc: utils.AbsoluteURL(baseURL, cover)}}
+}
+
+// formatLastMod 格式化 lastmod（W3C Datetime），零值返回空字符串
+func formatLastMod(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// render 输出 XML 文档
+func render(v interface{}, lastModified time.Time) (*Document, error) {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+
+	enc := xml.NewEncoder(&buf)
+	enc.Indent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	buf.WriteByte('\n')
+
+	return &Document{Data: buf.Bytes(), LastModified: lastModified}, nil
+}
@@ -0,0 +1,244 @@
+// Package sitemap 站点地图生成
+// 输出 sitemap 索引和按类型分页的子 sitemap（每个文件最多 50000 条 URL），
+// 结果缓存在进程内并以内容版本为键：文章、生活记录、标签等变化后下次请求自动重建
+package sitemap
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"kuaiyu/internal/config"
+	"kuaiyu/internal/database"
+)
+
+// ===========================================
+// 子 sitemap 类型
+// ===========================================
+
+// 子 sitemap 类型
+const (
+	SectionPages        = "pages"        // 首页、列表页和系列页
+	SectionPosts        = "posts"        // 文章
+	SectionTranslations = "translations" // 文章的其他语言版本
+	SectionLife         = "life"         // 生活记录
+	SectionTags         = "tags"         // 标签页
+)
+
+// sections 索引中的子 sitemap 顺序
+var sections = []string{SectionPages, SectionPosts, SectionTranslations, SectionLife, SectionTags}
+
+// ErrNotFound 子 sitemap 不存在（类型未知或页码超出范围）
+var ErrNotFound = errors.New("sitemap not found")
+
+// Document 生成好的 sitemap 文件
+type Document struct {
+	Data         []byte
+	LastModified time.Time
+}
+
+// ===========================================
+// 入口
+// ===========================================
+
+// Index 输出 sitemap 索引
+func Index() (*Document, error) {
+	return cached("index", func(s *stats) (*Document, error) {
+		return buildIndex(s)
+	})
+}
+
+// Section 输出指定类型的第 page 页子 sitemap（从 1 开始）
+func Section(name string, page int) (*Document, error) {
+	return cached(fmt.Sprintf("%s-%d", name, page), func(s *stats) (*Document, error) {
+		if page < 1 || page > s.pages(name) {
+			return nil, ErrNotFound
+		}
+		return buildSection(s, name, page)
+	})
+}
+
+// ParseFileName 解析子 sitemap 文件名，如 "posts-2.xml" -> ("posts", 2)
+func ParseFileName(file string) (string, int, bool) {
+	base, ok := strings.CutSuffix(file, ".xml")
+	if !ok {
+		return "", 0, false
+	}
+	i := strings.LastIndexByte(base, '-')
+	if i <= 0 {
+		return "", 0, false
+	}
+	var page int
+	if _, err := fmt.Sscanf(base[i+1:], "%d", &page); err != nil {
+		return "", 0, false
+	}
+	return base[:i], page, true
+}
+
+// Invalidate 清空缓存
+// 内容版本无法感知的变更（如标签改名，标签表没有更新时间）由调用方主动清空
+func Invalidate() {
+	mu.Lock()
+	defer mu.Unlock()
+
+	version = ""
+	documents = make(map[string]*Document)
+}
+
+// ===========================================
+// 缓存
+// ===========================================
+
+var (
+	mu        sync.Mutex
+	version   string
+	documents = make(map[string]*Document)
+)
+
+// cached 读取缓存，内容版本变化时清空后重新生成
+// 版本由各类内容的数量和最后更新时间组成，只需几条聚合查询
+func cached(key string, build func(s *stats) (*Document, error)) (*Document, error) {
+	s, err := loadStats(database.Get())
+	if err != nil {
+		return nil, err
+	}
+	current := s.version()
+
+	mu.Lock()
+	if version != current {
+		version = current
+		documents = make(map[string]*Document)
+	}
+	doc, ok := documents[key]
+	mu.Unlock()
+	if ok {
+		return doc, nil
+	}
+
+	doc, err = build(s)
+	if err != nil {
+		return nil, err
+	}
+
+	mu.Lock()
+	if version == current {
+		documents[key] = doc
+	}
+	mu.Unlock()
+	return doc, nil
+}
+
+// ===========================================
+// 内容统计
+// ===========================================
+
+// stat 一类内容的数量和最后更新时间
+type stat struct {
+	Count   int64
+	LastMod *time.Time
+}
+
+// lastMod 最后更新时间，没有内容时为零值
+func (s stat) lastMod() time.Time {
+	if s.LastMod == nil {
+		return time.Time{}
+	}
+	return *s.LastMod
+}
+
+// stats 站点地图涉及的全部内容统计
+type stats struct {
+	baseURL      string
+	posts        stat
+	translations stat
+	life         stat
+	tags         stat
+	series       stat
+	guestbook    stat
+}
+
+// loadStats 查询各类内容的统计
+func loadStats(db *gorm.DB) (*stats, error) {
+	s := &stats{baseURL: config.Get().Site.URL}
+
+	queries := []struct {
+		target *stat
+		query  *gorm.DB
+	}{
+		{&s.posts, postsQuery(db).Select("COUNT(*) AS count, MAX(posts.updated_at) AS last_mod")},
+		{&s.translations, translationsQuery(db).Select("COUNT(*) AS count, MAX(post_translations.updated_at) AS last_mod")},
+		{&s.life, lifeQuery(db).Select("COUNT(*) AS count, MAX(life_records.updated_at) AS last_mod")},
+		{&s.tags, tagsQuery(db).Select("COUNT(DISTINCT tags.id) AS count, MAX(posts.updated_at) AS last_mod")},
+		{&s.series, seriesQuery(db).Select("COUNT(*) AS count, MAX(series.updated_at) AS last_mod")},
+		{&s.guestbook, guestbookQuery(db).Select("COUNT(*) AS count, MAX(created_at) AS last_mod")},
+	}
+	for _, q := range queries {
+		if err := q.query.Scan(q.target).Error; err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// version 内容版本
+func (s *stats) version() string {
+	var b strings.Builder
+	b.WriteString(s.baseURL)
+	for _, st := range []stat{s.posts, s.translations, s.life, s.tags, s.series, s.guestbook} {
+		fmt.Fprintf(&b, "|%d:%d", st.Count, st.lastMod().UnixNano())
+	}
+	return b.String()
+}
+
+// count 子 sitemap 的 URL 数量
+func (s *stats) count(name string) int64 {
+	switch name {
+	case SectionPages:
+		return 1
+	case SectionPosts:
+		return s.posts.Count
+	case SectionTranslations:
+		return s.translations.Count
+	case SectionLife:
+		return s.life.Count
+	case SectionTags:
+		return s.tags.Count
+	}
+	return 0
+}
+
+// pages 子 sitemap 的分页数
+func (s *stats) pages(name string) int {
+	return int((s.count(name) + maxURLs - 1) / maxURLs)
+}
+
+// lastMod 子 sitemap 的最后更新时间
+func (s *stats) lastMod(name string) time.Time {
+	switch name {
+	case SectionPages:
+		return latest(s.posts.lastMod(), s.life.lastMod(), s.series.lastMod(), s.guestbook.lastMod())
+	case SectionPosts:
+		return s.posts.lastMod()
+	case SectionTranslations:
+		return s.translations.lastMod()
+	case SectionLife:
+		return s.life.lastMod()
+	case SectionTags:
+		return s.tags.lastMod()
+	}
+	return time.Time{}
+}
+
+// latest 取最晚的时间
+func latest(times ...time.Time) time.Time {
+	var result time.Time
+	for _, t := range times {
+		if t.After(result) {
+			result = t
+		}
+	}
+	return result
+}
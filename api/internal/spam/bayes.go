@@ -0,0 +1,252 @@
+package spam
+
+import (
+	"errors"
+	"log"
+	"math"
+	"net/url"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"kuaiyu/internal/database"
+	"kuaiyu/internal/model"
+	"kuaiyu/pkg/constants"
+	"kuaiyu/pkg/tokenizer"
+)
+
+// ===========================================
+// 朴素贝叶斯分类
+// ===========================================
+
+const (
+	// maxFeatures 每条评论最多使用的词项数
+	maxFeatures = 200
+	// maxTokenRunes 词项的最大长度，与 spam_tokens.token 列宽一致
+	maxTokenRunes = 64
+	// interestingTokens 分类时只使用倾向最明显的词项，避免长评论的大量普通词项稀释结果
+	interestingTokens = 15
+)
+
+// Features 提取评论的词项（去重）
+// 正文按中英文分词，另外加入网址和正文链接的域名
+func Features(comment *model.Comment) []string {
+	seen := make(map[string]bool)
+	var tokens []string
+	add := func(token string) {
+		if runes := []rune(token); len(runes) > maxTokenRunes {
+			token = string(runes[:maxTokenRunes])
+		}
+		if token == "" || seen[token] || len(tokens) >= maxFeatures {
+			return
+		}
+		seen[token] = true
+		tokens = append(tokens, token)
+	}
+
+	if host := hostOf(comment.Website); host != "" {
+		add("site:" + host)
+	}
+	for _, link := range findLinks(comment.Content) {
+		if host := hostOf(link); host != "" {
+			add("link:" + host)
+		}
+	}
+	for _, token := range tokenizer.Tokenize(comment.Content) {
+		add(token)
+	}
+	return tokens
+}
+
+// hostOf 链接的域名（小写，去掉 www.）
+func hostOf(link string) string {
+	link = strings.TrimSpace(link)
+	if link == "" {
+		return ""
+	}
+	if !strings.Contains(link, "://") {
+		link = "http://" + link
+	}
+	u, err := url.Parse(link)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+}
+
+// Classify 计算词项属于垃圾评论的概率
+// 两类训练样本都不少于 minSamples 条且至少有一个已知词项时 ok 为 true；
+// 两类先验概率视为相等，词项概率使用拉普拉斯平滑
+func Classify(tokens []string, minSamples int) (p float64, ok bool) {
+	if len(tokens) == 0 {
+		return 0, false
+	}
+
+	db := database.Get()
+	var counts []struct {
+		Label string
+		Total int64
+	}
+	if err := db.Model(&model.SpamTraining{}).
+		Select("label, COUNT(*) AS total").
+		Group("label").
+		Scan(&counts).Error; err != nil {
+		log.Printf("Failed to count spam trainings: %v", err)
+		return 0, false
+	}
+
+	var spamDocs, hamDocs float64
+	for _, c := range counts {
+		switch c.Label {
+		case model.SpamLabelSpam:
+			spamDocs = float64(c.Total)
+		case model.SpamLabelHam:
+			hamDocs = float64(c.Total)
+		}
+	}
+	if spamDocs < float64(minSamples) || hamDocs < float64(minSamples) || spamDocs == 0 || hamDocs == 0 {
+		return 0, false
+	}
+
+	var rows []model.SpamToken
+	if err := db.Where("token IN ?", tokens).Find(&rows).Error; err != nil {
+		log.Printf("Failed to query spam tokens: %v", err)
+		return 0, false
+	}
+	if len(rows) == 0 {
+		return 0, false
+	}
+
+	evidence := make([]float64, len(rows))
+	for i, row := range rows {
+		pSpam := (float64(row.Spam) + 1) / (spamDocs + 2)
+		pHam := (float64(row.Ham) + 1) / (hamDocs + 2)
+		evidence[i] = math.Log(pSpam / pHam)
+	}
+	sort.Slice(evidence, func(i, j int) bool {
+		return math.Abs(evidence[i]) > math.Abs(evidence[j])
+	})
+
+	logOdds := 0.0
+	for _, e := range evidence[:min(len(evidence), interestingTokens)] {
+		logOdds += e
+	}
+	return 1 / (1 + math.Exp(-logOdds)), true
+}
+
+// ===========================================
+// 训练
+// ===========================================
+
+// Learn 根据管理员的审核结果训练分类器
+// 标记为垃圾评论或通过审核时作为对应类别的样本，改回待审核时撤销训练
+func Learn(comment *model.Comment) {
+	var err error
+	switch comment.Status {
+	case string(constants.CommentStatusSpam):
+		err = Train(comment, model.SpamLabelSpam)
+	case string(constants.CommentStatusApproved):
+		err = Train(comment, model.SpamLabelHam)
+	default:
+		err = Forget(comment.ID)
+	}
+	if err != nil {
+		log.Printf("Failed to train spam filter with comment %d: %v", comment.ID, err)
+	}
+}
+
+// Train 将评论作为 label 类别的样本训练
+// 评论已按其他类别训练过时先撤销原来的训练，保证每条评论只计入一次
+func Train(comment *model.Comment, label string) error {
+	tokens := Features(comment)
+
+	return database.Get().Transaction(func(tx *gorm.DB) error {
+		var training model.SpamTraining
+		err := tx.Where("comment_id = ?", comment.ID).First(&training).Error
+		switch {
+		case err == nil:
+			if training.Label == label {
+				return nil
+			}
+			if err := untrain(tx, training.Label, splitTokens(training.Tokens)); err != nil {
+				return err
+			}
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			training = model.SpamTraining{CommentID: comment.ID}
+		default:
+			return err
+		}
+
+		if len(tokens) > 0 {
+			rows := make([]model.SpamToken, len(tokens))
+			for i, token := range tokens {
+				rows[i] = model.SpamToken{Token: token}
+				if label == model.SpamLabelSpam {
+					rows[i].Spam = 1
+				} else {
+					rows[i].Ham = 1
+				}
+			}
+			column := labelColumn(label)
+			if err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "token"}},
+				DoUpdates: clause.Assignments(map[string]interface{}{column: gorm.Expr(column+" + ?", 1)}),
+			}).Create(&rows).Error; err != nil {
+				return err
+			}
+		}
+
+		training.Label = label
+		training.Tokens = strings.Join(tokens, "\n")
+		return tx.Save(&training).Error
+	})
+}
+
+// Forget 撤销评论的训练
+func Forget(commentID uint) error {
+	return database.Get().Transaction(func(tx *gorm.DB) error {
+		var training model.SpamTraining
+		err := tx.Where("comment_id = ?", commentID).First(&training).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := untrain(tx, training.Label, splitTokens(training.Tokens)); err != nil {
+			return err
+		}
+		return tx.Delete(&training).Error
+	})
+}
+
+// untrain 词项在 label 类别中的计数减一，并删除两类计数都为零的词项
+func untrain(tx *gorm.DB, label string, tokens []string) error {
+	if len(tokens) == 0 {
+		return nil
+	}
+	column := labelColumn(label)
+	if err := tx.Model(&model.SpamToken{}).
+		Where("token IN ? AND "+column+" > 0", tokens).
+		UpdateColumn(column, gorm.Expr(column+" - ?", 1)).Error; err != nil {
+		return err
+	}
+	return tx.Where("token IN ? AND spam = 0 AND ham = 0", tokens).Delete(&model.SpamToken{}).Error
+}
+
+// labelColumn 类别对应的计数列
+func labelColumn(label string) string {
+	if label == model.SpamLabelSpam {
+		return "spam"
+	}
+	return "ham"
+}
+
+func splitTokens(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "\n")
+}
@@ -0,0 +1,162 @@
+package spam
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"kuaiyu/internal/repository"
+)
+
+// ===========================================
+// 检查项
+// ===========================================
+
+// FirstCommentCheck 首次评论：邮箱还没有通过审核的评论
+type FirstCommentCheck struct {
+	Score float64
+}
+
+func (c *FirstCommentCheck) Name() string { return "first_comment" }
+
+func (c *FirstCommentCheck) Check(s *Submission) (float64, string) {
+	if !repository.NewCommentRepository().IsFirstComment(s.Comment.Email) {
+		return 0, ""
+	}
+	return c.Score, "该邮箱首次评论"
+}
+
+// KeywordCheck 敏感词，每个命中的词计一次分
+type KeywordCheck struct {
+	Matcher *Matcher
+	Score   float64
+}
+
+func (c *KeywordCheck) Name() string { return "keyword" }
+
+func (c *KeywordCheck) Check(s *Submission) (float64, string) {
+	text := strings.Join([]string{s.Comment.Nickname, s.Comment.Website, s.Comment.Content}, "\n")
+	found := c.Matcher.Find(text)
+	if len(found) == 0 {
+		return 0, ""
+	}
+	return float64(len(found)) * c.Score, "命中敏感词：" + strings.Join(found, "、")
+}
+
+// linkPattern 正文中的链接
+var linkPattern = regexp.MustCompile(`(?i)(?:https?://|www\.)[^\s<>"'()（）]+`)
+
+// findLinks 找出正文中的链接
+func findLinks(content string) []string {
+	return linkPattern.FindAllString(content, -1)
+}
+
+// LinkCheck 链接数，超出 Max 的部分每个链接计一次分
+type LinkCheck struct {
+	Max   int
+	Score float64
+}
+
+func (c *LinkCheck) Name() string { return "links" }
+
+func (c *LinkCheck) Check(s *Submission) (float64, string) {
+	count := len(findLinks(s.Comment.Content))
+	if count <= c.Max {
+		return 0, ""
+	}
+	return float64(count-c.Max) * c.Score, fmt.Sprintf("包含 %d 个链接（允许 %d 个）", count, c.Max)
+}
+
+// HoneypotCheck 隐藏字段：表单中对用户不可见的输入框，只有机器人会填写
+type HoneypotCheck struct {
+	Score float64
+}
+
+func (c *HoneypotCheck) Name() string { return "honeypot" }
+
+func (c *HoneypotCheck) Check(s *Submission) (float64, string) {
+	if strings.TrimSpace(s.Honeypot) == "" {
+		return 0, ""
+	}
+	return c.Score, "填写了隐藏字段"
+}
+
+// clockSkew 允许的客户端时钟偏差
+const clockSkew = time.Minute
+
+// TimingCheck 提交耗时：从表单渲染到提交的时间过短
+// 前端未提供渲染时间时不计分
+type TimingCheck struct {
+	Min   time.Duration
+	Score float64
+}
+
+func (c *TimingCheck) Name() string { return "timing" }
+
+func (c *TimingCheck) Check(s *Submission) (float64, string) {
+	if s.RenderedAt.IsZero() {
+		return 0, ""
+	}
+	elapsed := s.ReceivedAt.Sub(s.RenderedAt)
+	if elapsed < -clockSkew {
+		return c.Score, "表单渲染时间无效"
+	}
+	if elapsed < c.Min {
+		return c.Score, fmt.Sprintf("打开表单 %.1f 秒后即提交（至少 %s）", max(elapsed.Seconds(), 0), c.Min)
+	}
+	return 0, ""
+}
+
+// BayesCheck 本地训练的朴素贝叶斯分类
+// 判为垃圾评论时加分，判为正常评论时减分，分数绝对值不超过 Weight
+type BayesCheck struct {
+	MinSamples int
+	Weight     float64
+}
+
+func (c *BayesCheck) Name() string { return "bayes" }
+
+func (c *BayesCheck) Check(s *Submission) (float64, string) {
+	p, ok := Classify(Features(s.Comment), c.MinSamples)
+	if !ok {
+		return 0, ""
+	}
+	return c.Weight * (2*p - 1), fmt.Sprintf("垃圾评论概率 %.0f%%", p*100)
+}
+
+// ===========================================
+// 敏感词加载
+// ===========================================
+
+// loadKeywords 合并配置的敏感词和敏感词文件
+// 文件每行一个词，空行和 # 开头的行忽略
+func loadKeywords(words []string, file string) []string {
+	if file == "" {
+		return words
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		log.Printf("Failed to open spam keywords file: %v", err)
+		return words
+	}
+	defer f.Close()
+
+	keywords := append([]string(nil), words...)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		keywords = append(keywords, line)
+	}
+	if err := scanner.Err(); err != nil {
+		log.Printf("Failed to read spam keywords file: %v", err)
+	}
+	return keywords
+}
@@ -0,0 +1,114 @@
+package spam
+
+import (
+	"unicode"
+)
+
+// ===========================================
+// 敏感词匹配（DFA）
+// ===========================================
+
+// Matcher 敏感词匹配器
+// 敏感词构建为按字符转移的状态机，一次扫描找出全部命中；
+// 匹配时忽略大小写、全角半角差异以及词中间插入的空格和标点（如“加 微-信”）
+type Matcher struct {
+	root *state
+}
+
+// state 状态机节点
+type state struct {
+	next map[rune]*state
+	word string // 到达此节点时完整匹配的敏感词
+}
+
+// NewMatcher 创建敏感词匹配器
+func NewMatcher(words []string) *Matcher {
+	m := &Matcher{root: &state{}}
+	for _, word := range words {
+		m.add(word)
+	}
+	return m
+}
+
+// add 添加敏感词，空格和标点不参与匹配
+func (m *Matcher) add(word string) {
+	current := m.root
+	added := false
+	for _, r := range word {
+		r = normalizeRune(r)
+		if isNoise(r) {
+			continue
+		}
+		if current.next == nil {
+			current.next = make(map[rune]*state)
+		}
+		next, ok := current.next[r]
+		if !ok {
+			next = &state{}
+			current.next[r] = next
+		}
+		current = next
+		added = true
+	}
+	if added {
+		current.word = word
+	}
+}
+
+// Find 找出文本中命中的敏感词（去重，按首次出现顺序）
+// 同一位置优先取最长的敏感词
+func (m *Matcher) Find(text string) []string {
+	runes := []rune(text)
+	for i, r := range runes {
+		runes[i] = normalizeRune(r)
+	}
+
+	var found []string
+	seen := make(map[string]bool)
+	for i := 0; i < len(runes); i++ {
+		if isNoise(runes[i]) {
+			continue
+		}
+
+		current := m.root
+		matched, end := "", i
+		for j := i; j < len(runes); j++ {
+			if isNoise(runes[j]) {
+				continue
+			}
+			next, ok := current.next[runes[j]]
+			if !ok {
+				break
+			}
+			current = next
+			if current.word != "" {
+				matched, end = current.word, j
+			}
+		}
+
+		if matched != "" {
+			if !seen[matched] {
+				seen[matched] = true
+				found = append(found, matched)
+			}
+			i = end
+		}
+	}
+	return found
+}
+
+// normalizeRune 转为小写半角字符
+func normalizeRune(r rune) rune {
+	switch {
+	case r == '　':
+		r = ' '
+	case r >= '！' && r <= '～':
+		r -= 0xfee0
+	}
+	return unicode.ToLower(r)
+}
+
+// isNoise 是否为匹配时忽略的字符
+func isNoise(r rune) bool {
+	return unicode.IsSpace(r) || unicode.IsPunct(r) || unicode.IsSymbol(r)
+}
@@ -0,0 +1,125 @@
+// Package spam 评论反垃圾
+// 评论提交时依次执行各项检查：首次评论、敏感词、链接数、隐藏字段、提交耗时和本地训练的朴素贝叶斯分类。
+// 每项给出分数和原因，总分决定评论直接通过、进入待审核还是标记为垃圾评论；
+// 检查原因随评论保存，供管理员审核时查看。管理员的审核结果用于训练贝叶斯分类器
+package spam
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"kuaiyu/internal/config"
+	"kuaiyu/internal/model"
+	"kuaiyu/pkg/constants"
+)
+
+// ===========================================
+// 检查流程
+// ===========================================
+
+// Submission 待检查的评论提交
+type Submission struct {
+	Comment    *model.Comment
+	Honeypot   string    // 隐藏字段的值，正常用户不会填写
+	RenderedAt time.Time // 评论表单的渲染时间，未知时为零值
+	ReceivedAt time.Time // 服务端收到提交的时间
+}
+
+// Check 反垃圾检查项
+type Check interface {
+	// Name 检查项名称，记录在检查原因中
+	Name() string
+	// Check 返回该项的分数和说明；分数为 0 且说明为空表示未命中
+	Check(s *Submission) (float64, string)
+}
+
+// Verdict 检查结论
+type Verdict struct {
+	Status  string // approved、pending 或 spam
+	Score   float64
+	Reasons []model.SpamReason
+}
+
+// Pipeline 检查流程
+type Pipeline struct {
+	checks       []Check
+	pendingScore float64
+	spamScore    float64
+}
+
+// NewPipeline 创建检查流程
+func NewPipeline(pendingScore, spamScore float64, checks ...Check) *Pipeline {
+	return &Pipeline{
+		checks:       checks,
+		pendingScore: pendingScore,
+		spamScore:    spamScore,
+	}
+}
+
+// Evaluate 执行全部检查并汇总分数
+func (p *Pipeline) Evaluate(s *Submission) Verdict {
+	if s.ReceivedAt.IsZero() {
+		s.ReceivedAt = time.Now()
+	}
+
+	var verdict Verdict
+	for _, check := range p.checks {
+		score, detail := check.Check(s)
+		if score == 0 && detail == "" {
+			continue
+		}
+		score = math.Round(score*100) / 100
+		verdict.Score += score
+		verdict.Reasons = append(verdict.Reasons, model.SpamReason{
+			Check:  check.Name(),
+			Score:  score,
+			Detail: detail,
+		})
+	}
+	verdict.Score = math.Round(verdict.Score*100) / 100
+
+	switch {
+	case verdict.Score >= p.spamScore:
+		verdict.Status = string(constants.CommentStatusSpam)
+	case verdict.Score >= p.pendingScore:
+		verdict.Status = string(constants.CommentStatusPending)
+	default:
+		verdict.Status = string(constants.CommentStatusApproved)
+	}
+	return verdict
+}
+
+var (
+	defaultPipeline *Pipeline
+	defaultOnce     sync.Once
+)
+
+// Default 按配置创建的检查流程
+// 反垃圾关闭时只保留首次评论需审核的规则
+func Default() *Pipeline {
+	defaultOnce.Do(func() {
+		cfg := config.Get().Spam
+		if !cfg.Enabled {
+			defaultPipeline = NewPipeline(cfg.PendingScore, math.Inf(1),
+				&FirstCommentCheck{Score: cfg.PendingScore},
+			)
+			return
+		}
+
+		defaultPipeline = NewPipeline(cfg.PendingScore, cfg.SpamScore,
+			&FirstCommentCheck{Score: cfg.FirstCommentScore},
+			&KeywordCheck{Matcher: NewMatcher(loadKeywords(cfg.Keywords, cfg.KeywordsFile)), Score: cfg.KeywordScore},
+			&LinkCheck{Max: cfg.MaxLinks, Score: cfg.LinkScore},
+			&HoneypotCheck{Score: cfg.HoneypotScore},
+			&TimingCheck{Min: cfg.MinSubmitTime, Score: cfg.TimingScore},
+			&BayesCheck{MinSamples: cfg.BayesMinSamples, Weight: cfg.BayesWeight},
+		)
+	})
+	return defaultPipeline
+}
+
+// Evaluate 使用默认检查流程检查评论
+func Evaluate(s *Submission) Verdict {
+	return Default().Evaluate(s)
+}
@@ -87,6 +87,10 @@ const (
 	RateLimitLogin = 5
 	// RateLimitUpload 上传接口限流 (每分钟)
 	RateLimitUpload = 10
+	// RateLimitReaction 读者表态接口限流 (每分钟)
+	RateLimitReaction = 30
+	// RateLimitUnlock 加密文章解锁限流 (每15分钟，按 IP 和文章计)
+	RateLimitUnlock = 10
 )
 
 // ===========================================
@@ -102,6 +106,32 @@ const (
 	LifeContentFullThreshold = 500
 	// DefaultReplyLimit 默认回复显示数量
 	DefaultReplyLimit = 3
+	// RevisionDiffContext 修订对比的上下文行数
+	RevisionDiffContext = 3
+	// PreviewDefaultExpiry 草稿预览链接默认有效期
+	PreviewDefaultExpiry = 72 * time.Hour
+	// PostUnlockExpiry 加密文章解锁令牌有效期
+	PostUnlockExpiry = 2 * time.Hour
+	// DefaultLocale 文章原文的语言，翻译版本使用其他语言
+	DefaultLocale = "zh-CN"
+	// FeaturedPostsLimit 推荐文章返回数量
+	FeaturedPostsLimit = 5
+	// FeedDefaultLimit 订阅源默认条目数
+	FeedDefaultLimit = 20
+	// FeedMaxLimit 订阅源最大条目数
+	FeedMaxLimit = 100
+	// SEODescriptionMaxLength SEO 描述的最大长度
+	SEODescriptionMaxLength = 160
+	// SitemapMaxURLs 单个 sitemap 文件的最大 URL 数（协议上限）
+	SitemapMaxURLs = 50000
+	// RelatedDefaultLimit 相关文章默认返回数量
+	RelatedDefaultLimit = 5
+	// RelatedMaxLimit 相关文章最大返回数量
+	RelatedMaxLimit = 20
+	// ImportMaxSize 导入文件上传的最大大小 (32MB)
+	ImportMaxSize = 32 << 20
+	// ArchiveMaxSize 备份归档上传的最大大小 (512MB)
+	ArchiveMaxSize = 512 << 20
 )
 
 // ===========================================
@@ -114,6 +144,16 @@ type PostStatus string
 const (
 	PostStatusDraft     PostStatus = "draft"
 	PostStatusPublished PostStatus = "published"
+	PostStatusScheduled PostStatus = "scheduled" // 定时发布，到达 published_at 后由调度器发布
+)
+
+// PostVisibility 文章可见性
+type PostVisibility string
+
+const (
+	PostVisibilityPublic    PostVisibility = "public"
+	PostVisibilityUnlisted  PostVisibility = "unlisted"  // 不出现在列表、归档、RSS 和站点地图中，持有链接可访问
+	PostVisibilityProtected PostVisibility = "protected" // 需输入密码解锁正文
 )
 
 // CommentStatus 评论状态
@@ -125,6 +165,16 @@ const (
 	CommentStatusSpam     CommentStatus = "spam"
 )
 
+// RevisionReason 修订原因
+type RevisionReason string
+
+const (
+	RevisionReasonCreate  RevisionReason = "create"
+	RevisionReasonUpdate  RevisionReason = "update"
+	RevisionReasonRestore RevisionReason = "restore"
+	RevisionReasonImport  RevisionReason = "import"
+)
+
 // PageType 页面类型
 type PageType string
 
@@ -188,6 +238,17 @@ const (
 	MsgFileTooLarge      = "文件大小超出限制"
 	MsgEmailExists       = "邮箱已被使用"
 	MsgSlugExists        = "URL 标识已存在"
+	MsgPublishAtRequired = "定时发布需要指定发布时间"
+	MsgPublishAtInPast   = "定时发布时间必须晚于当前时间"
+	MsgPreviewInvalid    = "预览链接无效或已过期"
+	MsgMovedPermanently  = "地址已变更"
+	MsgRedirectExists    = "该来源路径已存在重定向"
+	MsgPasswordRequired  = "加密文章需要设置访问密码"
+	MsgUnlockFailed      = "密码错误"
+	MsgChallengeRequired = "请先完成人机验证"
+	MsgChallengeFailed   = "人机验证失败，请刷新后重试"
+	MsgEditTokenInvalid  = "编辑令牌无效"
+	MsgEditExpired       = "已超过可编辑时间"
 	MsgOperationFailed   = "操作失败"
 	MsgOperationSuccess  = "操作成功"
 	MsgCreateSuccess     = "创建成功"
@@ -0,0 +1,250 @@
+// Package diff 文本差异比较
+// 基于 Myers 算法提供行级别的文本差异计算
+package diff
+
+import (
+	"strings"
+)
+
+// ===========================================
+// 差异结构
+// ===========================================
+
+// Op 差异操作类型
+type Op string
+
+const (
+	OpEqual  Op = "equal"
+	OpInsert Op = "insert"
+	OpDelete Op = "delete"
+)
+
+// Line 差异行
+type Line struct {
+	Op      Op     `json:"op"`
+	Text    string `json:"text"`
+	OldLine int    `json:"old_line,omitempty"` // 旧文本中的行号（从 1 开始，插入行为 0）
+	NewLine int    `json:"new_line,omitempty"` // 新文本中的行号（从 1 开始，删除行为 0）
+}
+
+// Stats 差异统计
+type Stats struct {
+	Insertions int `json:"insertions"`
+	Deletions  int `json:"deletions"`
+	Unchanged  int `json:"unchanged"`
+}
+
+// Result 差异结果
+type Result struct {
+	Lines []Line `json:"lines"`
+	Stats Stats  `json:"stats"`
+}
+
+// ===========================================
+// 差异计算
+// ===========================================
+
+// Lines 按行比较两段文本
+// 相同的首尾行直接作为未变更行，只对中间部分计算最短编辑脚本
+func Lines(oldText, newText string) Result {
+	a := splitLines(oldText)
+	b := splitLines(newText)
+
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	lines := make([]Line, 0, max(len(a), len(b)))
+	for i := 0; i < prefix; i++ {
+		lines = append(lines, Line{Op: OpEqual, Text: a[i], OldLine: i + 1, NewLine: i + 1})
+	}
+	for _, l := range compare(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]) {
+		if l.OldLine > 0 {
+			l.OldLine += prefix
+		}
+		if l.NewLine > 0 {
+			l.NewLine += prefix
+		}
+		lines = append(lines, l)
+	}
+	for i := suffix; i > 0; i-- {
+		lines = append(lines, Line{Op: OpEqual, Text: a[len(a)-i], OldLine: len(a) - i + 1, NewLine: len(b) - i + 1})
+	}
+
+	var stats Stats
+	for _, l := range lines {
+		switch l.Op {
+		case OpInsert:
+			stats.Insertions++
+		case OpDelete:
+			stats.Deletions++
+		default:
+			stats.Unchanged++
+		}
+	}
+
+	return Result{Lines: lines, Stats: stats}
+}
+
+// Unified 生成统一格式（unified diff）的文本，context 为上下文行数
+func (r Result) Unified(context int) string {
+	if r.Stats.Insertions == 0 && r.Stats.Deletions == 0 {
+		return ""
+	}
+
+	// 标记需要输出的行（变更行及其上下文）
+	keep := make([]bool, len(r.Lines))
+	for i, l := range r.Lines {
+		if l.Op == OpEqual {
+			continue
+		}
+		for j := i - context; j <= i+context; j++ {
+			if j >= 0 && j < len(r.Lines) {
+				keep[j] = true
+			}
+		}
+	}
+
+	var sb strings.Builder
+	for i, l := range r.Lines {
+		if !keep[i] {
+			if i > 0 && keep[i-1] {
+				sb.WriteString("...\n")
+			}
+			continue
+		}
+		switch l.Op {
+		case OpInsert:
+			sb.WriteString("+ ")
+		case OpDelete:
+			sb.WriteString("- ")
+		default:
+			sb.WriteString("  ")
+		}
+		sb.WriteString(l.Text)
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
+
+// splitLines 拆分文本行（统一换行符）
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	s = strings.TrimSuffix(s, "\n")
+	return strings.Split(s, "\n")
+}
+
+const (
+	// MaxLines 逐行比较的最大总行数（去掉相同的首尾行之后），超出时整体视为删除后插入
+	MaxLines = 20000
+	// MaxEdits 最大编辑距离，超出时整体视为删除后插入；回溯记录占用 O(MaxEdits²) 内存
+	MaxEdits = 1000
+)
+
+// compare 计算两组行的编辑脚本，行号从 1 开始
+// 超出比较规模时退化为全部删除后全部插入
+func compare(a, b []string) []Line {
+	if len(a)+len(b) <= MaxLines {
+		if lines, ok := myers(a, b, MaxEdits); ok {
+			return lines
+		}
+	}
+
+	lines := make([]Line, 0, len(a)+len(b))
+	for i, text := range a {
+		lines = append(lines, Line{Op: OpDelete, Text: text, OldLine: i + 1})
+	}
+	for i, text := range b {
+		lines = append(lines, Line{Op: OpInsert, Text: text, NewLine: i + 1})
+	}
+	return lines
+}
+
+// myers Myers O(ND) 差异算法，编辑距离超过 maxEdits 时返回 false
+// 第 d 步只会用到对角线 -d..d 上的值，回溯记录只保存这一段
+func myers(a, b []string, maxEdits int) ([]Line, bool) {
+	n, m := len(a), len(b)
+	limit := min(n+m, maxEdits)
+	if n+m == 0 {
+		return []Line{}, true
+	}
+
+	offset := limit + 1
+	v := make([]int, 2*limit+3)
+	var trace [][]int
+
+	// 正向搜索最短编辑路径，记录每一步开始前的 V 数组用于回溯
+	found := false
+	for d := 0; d <= limit && !found; d++ {
+		trace = append(trace, append([]int(nil), v[offset-d:offset+d+1]...))
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				found = true
+				break
+			}
+		}
+	}
+	if !found {
+		return nil, false
+	}
+
+	// 回溯生成编辑脚本，trace[d][k+d] 为第 d 步开始前对角线 k 上的 x
+	lines := make([]Line, 0, n+m)
+	x, y := n, m
+	for d := len(trace) - 1; d >= 0; d-- {
+		prevX, prevY := 0, 0
+		if d > 0 {
+			vd := trace[d]
+			k := x - y
+			prevK := k - 1
+			if k == -d || (k != d && vd[k-1+d] < vd[k+1+d]) {
+				prevK = k + 1
+			}
+			prevX = vd[prevK+d]
+			prevY = prevX - prevK
+		}
+
+		for x > prevX && y > prevY {
+			lines = append(lines, Line{Op: OpEqual, Text: a[x-1], OldLine: x, NewLine: y})
+			x--
+			y--
+		}
+		if d > 0 {
+			if x == prevX {
+				lines = append(lines, Line{Op: OpInsert, Text: b[y-1], NewLine: y})
+			} else {
+				lines = append(lines, Line{Op: OpDelete, Text: a[x-1], OldLine: x})
+			}
+		}
+		x, y = prevX, prevY
+	}
+
+	// 反转为正序
+	for i, j := 0, len(lines)-1; i < j; i, j = i+1, j-1 {
+		lines[i], lines[j] = lines[j], lines[i]
+	}
+
+	return lines, true
+}
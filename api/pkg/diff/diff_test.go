@@ -0,0 +1,242 @@
+package diff
+
+import (
+	"math/rand"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestLines(t *testing.T) {
+	tests := []struct {
+		name     string
+		old, new string
+		want     []Line
+		stats    Stats
+	}{
+		{
+			name:  "both empty",
+			want:  []Line{},
+			stats: Stats{},
+		},
+		{
+			name: "unchanged",
+			old:  "a\nb\n",
+			new:  "a\nb",
+			want: []Line{
+				{Op: OpEqual, Text: "a", OldLine: 1, NewLine: 1},
+				{Op: OpEqual, Text: "b", OldLine: 2, NewLine: 2},
+			},
+			stats: Stats{Unchanged: 2},
+		},
+		{
+			name: "insert into empty",
+			new:  "a\nb",
+			want: []Line{
+				{Op: OpInsert, Text: "a", NewLine: 1},
+				{Op: OpInsert, Text: "b", NewLine: 2},
+			},
+			stats: Stats{Insertions: 2},
+		},
+		{
+			name: "delete everything",
+			old:  "a\nb",
+			want: []Line{
+				{Op: OpDelete, Text: "a", OldLine: 1},
+				{Op: OpDelete, Text: "b", OldLine: 2},
+			},
+			stats: Stats{Deletions: 2},
+		},
+		{
+			name: "change in the middle",
+			old:  "a\nb\nc\nd",
+			new:  "a\nx\nc\nd\ne",
+			want: []Line{
+				{Op: OpEqual, Text: "a", OldLine: 1, NewLine: 1},
+				{Op: OpDelete, Text: "b", OldLine: 2},
+				{Op: OpInsert, Text: "x", NewLine: 2},
+				{Op: OpEqual, Text: "c", OldLine: 3, NewLine: 3},
+				{Op: OpEqual, Text: "d", OldLine: 4, NewLine: 4},
+				{Op: OpInsert, Text: "e", NewLine: 5},
+			},
+			stats: Stats{Insertions: 2, Deletions: 1, Unchanged: 3},
+		},
+		{
+			name: "crlf line endings",
+			old:  "a\r\nb\r\n",
+			new:  "a\nc\n",
+			want: []Line{
+				{Op: OpEqual, Text: "a", OldLine: 1, NewLine: 1},
+				{Op: OpDelete, Text: "b", OldLine: 2},
+				{Op: OpInsert, Text: "c", NewLine: 2},
+			},
+			stats: Stats{Insertions: 1, Deletions: 1, Unchanged: 1},
+		},
+		{
+			name: "repeated lines around the change",
+			old:  "x\nx\nx",
+			new:  "x\nx\nx\nx",
+			want: []Line{
+				{Op: OpEqual, Text: "x", OldLine: 1, NewLine: 1},
+				{Op: OpEqual, Text: "x", OldLine: 2, NewLine: 2},
+				{Op: OpEqual, Text: "x", OldLine: 3, NewLine: 3},
+				{Op: OpInsert, Text: "x", NewLine: 4},
+			},
+			stats: Stats{Insertions: 1, Unchanged: 3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Lines(tt.old, tt.new)
+			if !equalLines(got.Lines, tt.want) {
+				t.Errorf("lines = %+v, want %+v", got.Lines, tt.want)
+			}
+			if got.Stats != tt.stats {
+				t.Errorf("stats = %+v, want %+v", got.Stats, tt.stats)
+			}
+		})
+	}
+}
+
+// TestLinesMinimal 随机文本的编辑脚本能还原两段文本，且编辑数等于最短编辑距离
+func TestLinesMinimal(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 500; i++ {
+		a := randomLines(rng, rng.Intn(30))
+		b := randomLines(rng, rng.Intn(30))
+		got := Lines(strings.Join(a, "\n"), strings.Join(b, "\n"))
+
+		checkScript(t, a, b, got.Lines)
+		edits := got.Stats.Insertions + got.Stats.Deletions
+		if want := len(a) + len(b) - 2*lcs(a, b); edits != want {
+			t.Fatalf("case %d: %d edits, want %d\nold %q\nnew %q", i, edits, want, a, b)
+		}
+	}
+}
+
+// TestLinesLimits 超出比较规模时退化为整体替换，首尾相同的行仍保留
+func TestLinesLimits(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b []string
+	}{
+		{
+			name: "too many edits",
+			a:    numberedLines("old", MaxEdits),
+			b:    numberedLines("new", MaxEdits),
+		},
+		{
+			name: "too many lines",
+			a:    numberedLines("line", MaxLines),
+			b:    append(numberedLines("line", MaxLines)[1:MaxLines-1], "changed"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := append(append([]string{"head"}, tt.a...), "tail")
+			b := append(append([]string{"head"}, tt.b...), "tail")
+			got := Lines(strings.Join(a, "\n"), strings.Join(b, "\n"))
+
+			checkScript(t, a, b, got.Lines)
+			if got.Lines[0].Op != OpEqual || got.Lines[len(got.Lines)-1].Op != OpEqual {
+				t.Errorf("common head and tail should stay unchanged")
+			}
+			middle := Stats{Insertions: len(tt.b), Deletions: len(tt.a), Unchanged: 2}
+			if got.Stats != middle {
+				t.Errorf("stats = %+v, want %+v", got.Stats, middle)
+			}
+		})
+	}
+}
+
+func TestUnified(t *testing.T) {
+	old := "1\n2\n3\n4\n5\n6\n7\n8"
+	new := "1\n2\n3\nfour\n5\n6\n7\n8"
+
+	want := "  3\n- 4\n+ four\n  5\n...\n"
+	if got := Lines(old, new).Unified(1); got != want {
+		t.Errorf("unified = %q, want %q", got, want)
+	}
+	if got := Lines(old, old).Unified(3); got != "" {
+		t.Errorf("unified of identical texts = %q, want empty", got)
+	}
+}
+
+func BenchmarkLinesLargeEdit(b *testing.B) {
+	old := strings.Join(numberedLines("old", 5000), "\n")
+	new := strings.Join(numberedLines("new", 5000), "\n")
+	for i := 0; i < b.N; i++ {
+		Lines(old, new)
+	}
+}
+
+func equalLines(a, b []Line) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// checkScript 校验编辑脚本：按顺序取出旧文本和新文本的行，行号连续且与内容一致
+func checkScript(t *testing.T, a, b []string, lines []Line) {
+	t.Helper()
+	i, j := 0, 0
+	for _, l := range lines {
+		if l.Op != OpInsert {
+			if i >= len(a) || a[i] != l.Text || l.OldLine != i+1 {
+				t.Fatalf("line %+v does not match old line %d", l, i+1)
+			}
+			i++
+		}
+		if l.Op != OpDelete {
+			if j >= len(b) || b[j] != l.Text || l.NewLine != j+1 {
+				t.Fatalf("line %+v does not match new line %d", l, j+1)
+			}
+			j++
+		}
+	}
+	if i != len(a) || j != len(b) {
+		t.Fatalf("script covers %d/%d old and %d/%d new lines", i, len(a), j, len(b))
+	}
+}
+
+// lcs 最长公共子序列的长度
+func lcs(a, b []string) int {
+	dp := make([][]int, len(a)+1)
+	for i := range dp {
+		dp[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				dp[i][j] = dp[i+1][j+1] + 1
+			} else {
+				dp[i][j] = max(dp[i+1][j], dp[i][j+1])
+			}
+		}
+	}
+	return dp[0][0]
+}
+
+func randomLines(rng *rand.Rand, n int) []string {
+	lines := make([]string, n)
+	for i := range lines {
+		lines[i] = string(rune('a' + rng.Intn(4)))
+	}
+	return lines
+}
+
+func numberedLines(prefix string, n int) []string {
+	lines := make([]string, n)
+	for i := range lines {
+		lines[i] = prefix + strconv.Itoa(i)
+	}
+	return lines
+}
@@ -0,0 +1,142 @@
+// Package locale 语言标识处理
+// 规范化 BCP 47 语言标签（如 zh-CN、en），解析 Accept-Language 并在可用语言中选择最佳匹配
+package locale
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ===========================================
+// 规范化
+// ===========================================
+
+// Normalize 规范化语言标签：语言小写、地区大写、文字首字母大写
+// 例如 "zh_cn" -> "zh-CN"、"zh-hans" -> "zh-Hans"、"EN" -> "en"；无效标签返回空字符串
+func Normalize(tag string) string {
+	tag = strings.TrimSpace(strings.ReplaceAll(tag, "_", "-"))
+	if tag == "" {
+		return ""
+	}
+
+	parts := strings.Split(tag, "-")
+	for i, part := range parts {
+		if part == "" || len(part) > 8 || !isAlnum(part) {
+			return ""
+		}
+		switch {
+		case i == 0:
+			if len(part) < 2 || len(part) > 3 || !isAlpha(part) {
+				return ""
+			}
+			parts[i] = strings.ToLower(part)
+		case len(part) == 2 && isAlpha(part):
+			parts[i] = strings.ToUpper(part)
+		case len(part) == 4 && isAlpha(part):
+			parts[i] = strings.ToUpper(part[:1]) + strings.ToLower(part[1:])
+		default:
+			parts[i] = strings.ToLower(part)
+		}
+	}
+	return strings.Join(parts, "-")
+}
+
+// Base 语言标签的主语言部分，例如 "zh-CN" -> "zh"
+func Base(tag string) string {
+	if i := strings.IndexByte(tag, '-'); i >= 0 {
+		return tag[:i]
+	}
+	return tag
+}
+
+// ===========================================
+// Accept-Language
+// ===========================================
+
+// ParseAcceptLanguage 解析 Accept-Language 请求头，按权重从高到低返回规范化后的语言标签
+// 忽略通配符 * 和权重为 0 的语言
+func ParseAcceptLanguage(header string) []string {
+	type weighted struct {
+		tag string
+		q   float64
+	}
+
+	var items []weighted
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(part, ";")
+		tag := Normalize(fields[0])
+		if tag == "" {
+			continue
+		}
+
+		q := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if v, ok := strings.CutPrefix(param, "q="); ok {
+				if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+		if q <= 0 {
+			continue
+		}
+		items = append(items, weighted{tag: tag, q: q})
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].q > items[j].q
+	})
+
+	tags := make([]string, len(items))
+	for i, item := range items {
+		tags[i] = item.tag
+	}
+	return tags
+}
+
+// ===========================================
+// 匹配
+// ===========================================
+
+// Match 按偏好顺序在可用语言中选择最佳匹配，没有匹配时返回 fallback
+// 每个偏好语言先精确匹配，再按主语言匹配（en-US 可匹配 en 或 en-GB）
+func Match(preferred, available []string, fallback string) string {
+	for _, want := range preferred {
+		for _, have := range available {
+			if strings.EqualFold(want, have) {
+				return have
+			}
+		}
+		base := Base(want)
+		for _, have := range available {
+			if strings.EqualFold(base, Base(have)) {
+				return have
+			}
+		}
+	}
+	return fallback
+}
+
+// ===========================================
+// 内部实现
+// ===========================================
+
+func isAlpha(s string) bool {
+	for _, r := range s {
+		if (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') {
+			return false
+		}
+	}
+	return true
+}
+
+func isAlnum(s string) bool {
+	for _, r := range s {
+		if (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') && (r < '0' || r > '9') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,566 @@
+package markdown
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ===========================================
+// 块级语法
+// ===========================================
+
+var (
+	reFenceOpen  = regexp.MustCompile("^( {0,3})(`{3,}|~{3,})[ \t]*(.*)$")
+	reATX        = regexp.MustCompile(`^ {0,3}(#{1,6})(?:[ \t]+(.*?))?(?:[ \t]+#+)?[ \t]*$`)
+	reHR         = regexp.MustCompile(`^ {0,3}(?:(?:\*[ \t]*){3,}|(?:-[ \t]*){3,}|(?:_[ \t]*){3,})$`)
+	reSetext1    = regexp.MustCompile(`^ {0,3}=+[ \t]*$`)
+	reSetext2    = regexp.MustCompile(`^ {0,3}-+[ \t]*$`)
+	reQuote      = regexp.MustCompile(`^ {0,3}> ?`)
+	reListItem   = regexp.MustCompile(`^( {0,3})([-*+]|\d{1,9}[.)])([ \t]+|$)`)
+	reTableDelim = regexp.MustCompile(`^ {0,3}\|?[ \t]*:?-+:?[ \t]*(?:\|[ \t]*:?-+:?[ \t]*)*\|?[ \t]*$`)
+	reRefDef     = regexp.MustCompile(`^ {0,3}\[([^\]]+)\]:[ \t]*<?([^\s>]+)>?(?:[ \t]+(?:"([^"]*)"|'([^']*)'|\(([^)]*)\)))?[ \t]*$`)
+	reComment    = regexp.MustCompile(`^[ \t]*<!--.*-->[ \t]*$`)
+	reTask       = regexp.MustCompile(`^\[([ xX])\][ \t]+`)
+)
+
+// collectRefs 收集引用式链接定义并从正文中移除，同时展开代码块以外的行首制表符
+func (r *renderer) collectRefs(lines []string) []string {
+	result := make([]string, 0, len(lines))
+	fence := ""
+
+	for _, line := range lines {
+		// 代码块内容保留原始制表符
+		if fence != "" {
+			if isFenceClose(line, fence) {
+				fence = ""
+			}
+			result = append(result, line)
+			continue
+		}
+
+		line = expandTabs(line)
+		if m := reFenceOpen.FindStringSubmatch(line); m != nil && validFence(m) {
+			fence = m[2]
+			result = append(result, line)
+			continue
+		}
+
+		if m := reRefDef.FindStringSubmatch(line); m != nil {
+			label := normalizeLabel(m[1])
+			if _, exists := r.refs[label]; !exists {
+				r.refs[label] = linkRef{
+					url:   unescapeBackslash(m[2]),
+					title: m[3] + m[4] + m[5],
+				}
+			}
+			continue
+		}
+
+		result = append(result, line)
+	}
+
+	return result
+}
+
+// blocks 渲染块级元素，tight 为 true 时段落不包裹 <p>（紧凑列表）
+func (r *renderer) blocks(lines []string, out *strings.Builder, tight bool) {
+	for i := 0; i < len(lines); {
+		line := lines[i]
+
+		switch {
+		case isBlank(line):
+			i++
+		case reComment.MatchString(line):
+			// HTML 注释（如 <!-- more -->）直接忽略
+			i++
+		case isFenceOpen(line):
+			i = r.fencedCode(lines, i, out)
+		case reATX.MatchString(line):
+			m := reATX.FindStringSubmatch(line)
+			r.heading(len(m[1]), m[2], out)
+			i++
+		case reHR.MatchString(line):
+			out.WriteString("<hr>\n")
+			i++
+		case reQuote.MatchString(line):
+			i = r.blockquote(lines, i, out)
+		case reListItem.MatchString(line):
+			i = r.list(lines, i, out)
+		case indentOf(line) >= 4:
+			i = r.indentedCode(lines, i, out)
+		default:
+			if next, ok := r.table(lines, i, out); ok {
+				i = next
+				continue
+			}
+			i = r.paragraph(lines, i, out, tight)
+		}
+	}
+}
+
+// heading 渲染标题并记录目录项
+func (r *renderer) heading(level int, raw string, out *strings.Builder) {
+	html, text := r.inline(strings.TrimSpace(raw))
+	text = strings.TrimSpace(text)
+	id := r.headingID(text)
+
+	r.headings = append(r.headings, heading{level: level, text: text, id: id})
+	r.plain.WriteString(text)
+	r.plain.WriteByte('\n')
+
+	tag := "h" + strconv.Itoa(level)
+	out.WriteString("<" + tag + ` id="` + escapeHTML(id) + `">`)
+	out.WriteString(html)
+	out.WriteString("</" + tag + ">\n")
+}
+
+// paragraph 渲染段落（含 Setext 标题）
+func (r *renderer) paragraph(lines []string, start int, out *strings.Builder, tight bool) int {
+	var para []string
+	i := start
+	for ; i < len(lines); i++ {
+		line := lines[i]
+		if isBlank(line) {
+			break
+		}
+		if len(para) > 0 {
+			if reSetext1.MatchString(line) {
+				r.heading(1, strings.Join(para, "\n"), out)
+				return i + 1
+			}
+			if reSetext2.MatchString(line) {
+				r.heading(2, strings.Join(para, "\n"), out)
+				return i + 1
+			}
+			if interruptsParagraph(line) {
+				break
+			}
+		}
+		para = append(para, strings.TrimLeft(line, " "))
+	}
+
+	html, text := r.inline(strings.TrimRight(strings.Join(para, "\n"), " "))
+	r.plain.WriteString(text)
+	r.plain.WriteByte('\n')
+
+	if tight {
+		out.WriteString(html)
+		out.WriteByte('\n')
+	} else {
+		out.WriteString("<p>")
+		out.WriteString(html)
+		out.WriteString("</p>\n")
+	}
+	return i
+}
+
+// fencedCode 渲染围栏代码块
+func (r *renderer) fencedCode(lines []string, start int, out *strings.Builder) int {
+	m := reFenceOpen.FindStringSubmatch(lines[start])
+	indent, fence := len(m[1]), m[2]
+
+	// 信息串的第一个单词为语言（兼容 ```js title="a.js" 和 ```{python} 写法）
+	lang := ""
+	if fields := strings.Fields(m[3]); len(fields) > 0 {
+		lang = normalizeLang(fields[0])
+	}
+
+	var code []string
+	i := start + 1
+	for ; i < len(lines); i++ {
+		if isFenceClose(lines[i], fence) {
+			i++
+			break
+		}
+		code = append(code, trimIndent(lines[i], indent))
+	}
+
+	r.code(lang, code, out)
+	return i
+}
+
+// indentedCode 渲染缩进代码块
+func (r *renderer) indentedCode(lines []string, start int, out *strings.Builder) int {
+	var code []string
+	i := start
+	for ; i < len(lines); i++ {
+		if !isBlank(lines[i]) && indentOf(lines[i]) < 4 {
+			break
+		}
+		code = append(code, trimIndent(lines[i], 4))
+	}
+	for len(code) > 0 && isBlank(code[len(code)-1]) {
+		code = code[:len(code)-1]
+	}
+
+	r.code("", code, out)
+	return i
+}
+
+// code 输出代码块并记录语言信息
+func (r *renderer) code(lang string, code []string, out *strings.Builder) {
+	r.codes = append(r.codes, CodeBlock{Language: lang, Lines: len(code)})
+
+	out.WriteString("<pre><code")
+	if lang != "" {
+		out.WriteString(` class="language-` + escapeHTML(lang) + `"`)
+	}
+	out.WriteString(">")
+	for _, line := range code {
+		out.WriteString(escapeHTML(line))
+		out.WriteByte('\n')
+	}
+	out.WriteString("</code></pre>\n")
+}
+
+// blockquote 渲染引用块
+func (r *renderer) blockquote(lines []string, start int, out *strings.Builder) int {
+	var inner []string
+	i := start
+	for ; i < len(lines); i++ {
+		line := lines[i]
+		if loc := reQuote.FindStringIndex(line); loc != nil {
+			inner = append(inner, line[loc[1]:])
+			continue
+		}
+		// 惰性续行：紧跟段落的非空行仍属于引用
+		if isBlank(line) || isBlank(inner[len(inner)-1]) || interruptsParagraph(line) {
+			break
+		}
+		inner = append(inner, line)
+	}
+
+	out.WriteString("<blockquote>\n")
+	r.blocks(inner, out, false)
+	out.WriteString("</blockquote>\n")
+	return i
+}
+
+// list 渲染有序/无序列表（支持嵌套和任务列表）
+func (r *renderer) list(lines []string, start int, out *strings.Builder) int {
+	first := reListItem.FindStringSubmatch(lines[start])
+	marker := first[2]
+	ordered := isDigit(marker[0])
+	delim := marker[len(marker)-1]
+
+	var items [][]string
+	var cur []string
+	contentIndent := 0
+	loose := false
+	blank := false
+
+	i := start
+	for ; i < len(lines); i++ {
+		line := lines[i]
+
+		if isBlank(line) {
+			if cur != nil {
+				cur = append(cur, "")
+			}
+			blank = true
+			continue
+		}
+
+		indent := indentOf(line)
+		if cur != nil && indent < contentIndent && reHR.MatchString(line) {
+			break
+		}
+
+		// 同类型的新列表项
+		if m := reListItem.FindStringSubmatch(line); m != nil && (cur == nil || indent < contentIndent) {
+			if isDigit(m[2][0]) != ordered || m[2][len(m[2])-1] != delim {
+				break
+			}
+			if cur != nil {
+				items = append(items, cur)
+				if blank {
+					loose = true
+				}
+			}
+
+			// 标记后超过 4 个空格时内容按缩进代码处理，只算 1 个空格
+			width := len(m[1]) + len(m[2])
+			content := line[len(m[0]):]
+			spaces := len(m[3])
+			if spaces == 0 || spaces > 4 {
+				spaces = 1
+				content = strings.TrimPrefix(line[width:], " ")
+			}
+			contentIndent = width + spaces
+			cur = []string{content}
+			blank = false
+			continue
+		}
+
+		// 列表项的后续内容
+		if indent >= contentIndent {
+			if blank && hasContent(cur) {
+				loose = true
+			}
+			cur = append(cur, trimIndent(line, contentIndent))
+			blank = false
+			continue
+		}
+
+		// 惰性续行
+		if !blank && !interruptsParagraph(line) {
+			cur = append(cur, strings.TrimLeft(line, " "))
+			continue
+		}
+
+		break
+	}
+	items = append(items, cur)
+
+	tag := "ul"
+	if ordered {
+		tag = "ol"
+	}
+	out.WriteString("<" + tag)
+	if ordered {
+		if n, _ := strconv.Atoi(marker[:len(marker)-1]); n != 1 {
+			out.WriteString(` start="` + strconv.Itoa(n) + `"`)
+		}
+	}
+	out.WriteString(">\n")
+
+	for _, item := range items {
+		for len(item) > 0 && isBlank(item[len(item)-1]) {
+			item = item[:len(item)-1]
+		}
+
+		if len(item) > 0 {
+			if m := reTask.FindStringSubmatch(item[0]); m != nil {
+				item[0] = item[0][len(m[0]):]
+				out.WriteString(`<li class="task-list-item"><input type="checkbox" disabled`)
+				if m[1] != " " {
+					out.WriteString(" checked")
+				}
+				out.WriteString("> ")
+			} else {
+				out.WriteString("<li>")
+			}
+		} else {
+			out.WriteString("<li>")
+		}
+
+		var inner strings.Builder
+		r.blocks(item, &inner, !loose)
+		html := inner.String()
+		if !loose {
+			html = strings.TrimSuffix(html, "\n")
+		}
+		out.WriteString(html)
+		out.WriteString("</li>\n")
+	}
+
+	out.WriteString("</" + tag + ">\n")
+	return i
+}
+
+// table 渲染 GFM 表格，不是表格时返回 false
+func (r *renderer) table(lines []string, start int, out *strings.Builder) (int, bool) {
+	if start+1 >= len(lines) || !strings.Contains(lines[start], "|") || !reTableDelim.MatchString(lines[start+1]) {
+		return start, false
+	}
+
+	header := splitRow(lines[start])
+	delims := splitRow(lines[start+1])
+	if len(header) != len(delims) {
+		return start, false
+	}
+
+	aligns := make([]string, len(delims))
+	for i, d := range delims {
+		left, right := strings.HasPrefix(d, ":"), strings.HasSuffix(d, ":")
+		switch {
+		case left && right:
+			aligns[i] = "center"
+		case right:
+			aligns[i] = "right"
+		case left:
+			aligns[i] = "left"
+		}
+	}
+
+	out.WriteString("<table>\n<thead>\n")
+	r.tableRow(header, aligns, "th", out)
+	out.WriteString("</thead>\n")
+
+	i := start + 2
+	if i < len(lines) && !isBlank(lines[i]) && !interruptsParagraph(lines[i]) {
+		out.WriteString("<tbody>\n")
+		for ; i < len(lines) && !isBlank(lines[i]) && !interruptsParagraph(lines[i]); i++ {
+			r.tableRow(splitRow(lines[i]), aligns, "td", out)
+		}
+		out.WriteString("</tbody>\n")
+	}
+
+	out.WriteString("</table>\n")
+	return i, true
+}
+
+// tableRow 渲染表格行，单元格数量与表头对齐
+func (r *renderer) tableRow(cells, aligns []string, tag string, out *strings.Builder) {
+	out.WriteString("<tr>\n")
+	for i, align := range aligns {
+		cell := ""
+		if i < len(cells) {
+			cell = cells[i]
+		}
+		html, text := r.inline(cell)
+		r.plain.WriteString(text)
+		r.plain.WriteByte(' ')
+
+		out.WriteString("<" + tag)
+		if align != "" {
+			out.WriteString(` align="` + align + `"`)
+		}
+		out.WriteString(">")
+		out.WriteString(html)
+		out.WriteString("</" + tag + ">\n")
+	}
+	out.WriteString("</tr>\n")
+}
+
+// ===========================================
+// 辅助函数
+// ===========================================
+
+// isBlank 是否为空行
+func isBlank(line string) bool {
+	return strings.TrimSpace(line) == ""
+}
+
+// isDigit 是否为数字
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// indentOf 行首空格数
+func indentOf(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+// trimIndent 去除最多 n 个行首空格
+func trimIndent(line string, n int) string {
+	i := 0
+	for i < n && i < len(line) && line[i] == ' ' {
+		i++
+	}
+	return line[i:]
+}
+
+// expandTabs 将行首制表符展开为空格（制表位为 4）
+func expandTabs(line string) string {
+	if !strings.HasPrefix(strings.TrimLeft(line, " "), "\t") {
+		return line
+	}
+	var b strings.Builder
+	col := 0
+	i := 0
+	for ; i < len(line) && (line[i] == ' ' || line[i] == '\t'); i++ {
+		if line[i] == '\t' {
+			n := 4 - col%4
+			b.WriteString(strings.Repeat(" ", n))
+			col += n
+		} else {
+			b.WriteByte(' ')
+			col++
+		}
+	}
+	b.WriteString(line[i:])
+	return b.String()
+}
+
+// hasContent 列表项是否已有非空内容
+func hasContent(lines []string) bool {
+	for _, line := range lines {
+		if !isBlank(line) {
+			return true
+		}
+	}
+	return false
+}
+
+// validFence 反引号围栏的信息串中不能包含反引号
+func validFence(m []string) bool {
+	return m[2][0] != '`' || !strings.Contains(m[3], "`")
+}
+
+// isFenceOpen 是否为围栏代码块起始行
+func isFenceOpen(line string) bool {
+	m := reFenceOpen.FindStringSubmatch(line)
+	return m != nil && validFence(m)
+}
+
+// isFenceClose 是否为与 fence 匹配的围栏结束行
+func isFenceClose(line, fence string) bool {
+	if indentOf(line) > 3 {
+		return false
+	}
+	trimmed := strings.TrimSpace(line)
+	return len(trimmed) >= len(fence) && strings.Trim(trimmed, fence[:1]) == ""
+}
+
+// interruptsParagraph 该行是否会打断段落，开始新的块
+func interruptsParagraph(line string) bool {
+	if isFenceOpen(line) || reATX.MatchString(line) || reHR.MatchString(line) ||
+		reQuote.MatchString(line) || reComment.MatchString(line) {
+		return true
+	}
+	// 列表项需要有内容，有序列表只有从 1 开始才能打断段落
+	if m := reListItem.FindStringSubmatch(line); m != nil {
+		if isBlank(line[len(m[0]):]) {
+			return false
+		}
+		if isDigit(m[2][0]) {
+			return m[2][:len(m[2])-1] == "1"
+		}
+		return true
+	}
+	return false
+}
+
+// splitRow 拆分表格行的单元格（支持 \| 转义）
+func splitRow(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	if strings.HasSuffix(line, "|") && !strings.HasSuffix(line, `\|`) {
+		line = line[:len(line)-1]
+	}
+
+	var cells []string
+	var cell strings.Builder
+	for i := 0; i < len(line); i++ {
+		switch {
+		case line[i] == '\\' && i+1 < len(line) && line[i+1] == '|':
+			cell.WriteByte('|')
+			i++
+		case line[i] == '|':
+			cells = append(cells, strings.TrimSpace(cell.String()))
+			cell.Reset()
+		default:
+			cell.WriteByte(line[i])
+		}
+	}
+	return append(cells, strings.TrimSpace(cell.String()))
+}
+
+// normalizeLabel 规范化链接引用标签（不区分大小写，合并空白）
+func normalizeLabel(label string) string {
+	return strings.ToLower(strings.Join(strings.Fields(label), " "))
+}
+
+// normalizeLang 规范化代码块语言标识，只保留安全字符
+func normalizeLang(lang string) string {
+	lang = strings.ToLower(strings.Trim(lang, "{}."))
+	var b strings.Builder
+	for _, ch := range lang {
+		if (ch >= 'a' && ch <= 'z') || (ch >= '0' && ch <= '9') || strings.ContainsRune("+#-_", ch) {
+			b.WriteRune(ch)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,555 @@
+package markdown
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ===========================================
+// 行内语法
+// ===========================================
+
+var (
+	reEntity    = regexp.MustCompile(`^&(?:#[0-9]{1,7}|#[xX][0-9a-fA-F]{1,6}|[a-zA-Z][a-zA-Z0-9]{1,31});`)
+	reAutolink  = regexp.MustCompile(`^<([a-zA-Z][a-zA-Z0-9+.-]{1,31}:[^\s<>]*)>`)
+	reEmailLink = regexp.MustCompile(`^<([a-zA-Z0-9.!#$%&'*+/=?^_{|}~-]+@[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(?:\.[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*)>`)
+	reBareURL   = regexp.MustCompile(`^(?:https?://|www\.)[^\s<]*[^\s<?!.,:*_~'"]`)
+	reDataImage = regexp.MustCompile(`^data:image/(?:png|gif|jpeg|jpg|webp);base64,[a-z0-9+/=]+$`)
+)
+
+// inlineSpecial 需要特殊处理的行内字符
+const inlineSpecial = "\\`*_~![<& \nhw"
+
+// inline 渲染行内元素，返回 HTML 和纯文本
+func (r *renderer) inline(s string) (string, string) {
+	p := &inlineParser{r: r, src: s}
+	p.parse()
+	return p.out.String(), p.plain.String()
+}
+
+// inlineParser 行内解析器
+type inlineParser struct {
+	r       *renderer
+	src     string
+	pos     int
+	noLinks bool // 链接文本中不再解析链接
+	out     strings.Builder
+	plain   strings.Builder
+}
+
+// parse 解析全部行内内容
+func (p *inlineParser) parse() {
+	for p.pos < len(p.src) {
+		switch c := p.src[p.pos]; c {
+		case '\\':
+			p.escape()
+		case '`':
+			p.codeSpan()
+		case '*', '_':
+			p.emphasis(c)
+		case '~':
+			p.strikethrough()
+		case '!':
+			if p.pos+1 < len(p.src) && p.src[p.pos+1] == '[' && p.link(true) {
+				continue
+			}
+			p.literal("!")
+			p.pos++
+		case '[':
+			if !p.noLinks && p.link(false) {
+				continue
+			}
+			p.literal("[")
+			p.pos++
+		case '<':
+			p.angle()
+		case '&':
+			p.entity()
+		case ' ':
+			p.spaces()
+		case '\n':
+			p.out.WriteByte('\n')
+			p.plain.WriteByte('\n')
+			p.pos++
+		case 'h', 'w':
+			if !p.noLinks && p.bareURL() {
+				continue
+			}
+			p.text()
+		default:
+			p.text()
+		}
+	}
+}
+
+// nested 解析嵌套内容并写入当前输出
+func (p *inlineParser) nested(s string, noLinks bool) (string, string) {
+	child := &inlineParser{r: p.r, src: s, noLinks: noLinks}
+	child.parse()
+	return child.out.String(), child.plain.String()
+}
+
+// literal 输出普通文本
+func (p *inlineParser) literal(s string) {
+	p.out.WriteString(escapeHTML(s))
+	p.plain.WriteString(s)
+}
+
+// text 输出到下一个特殊字符之前的普通文本
+func (p *inlineParser) text() {
+	end := p.pos + 1
+	for end < len(p.src) && !strings.ContainsRune(inlineSpecial, rune(p.src[end])) {
+		end++
+	}
+	p.literal(p.src[p.pos:end])
+	p.pos = end
+}
+
+// escape 反斜杠转义和行尾硬换行
+func (p *inlineParser) escape() {
+	if p.pos+1 < len(p.src) {
+		next := p.src[p.pos+1]
+		if next == '\n' {
+			p.out.WriteString("<br>\n")
+			p.plain.WriteByte('\n')
+			p.pos += 2
+			return
+		}
+		if isASCIIPunct(next) {
+			p.literal(string(next))
+			p.pos += 2
+			return
+		}
+	}
+	p.literal("\\")
+	p.pos++
+}
+
+// spaces 空格，行尾两个以上空格为硬换行
+func (p *inlineParser) spaces() {
+	n := runLength(p.src, p.pos, ' ')
+	end := p.pos + n
+	if end < len(p.src) && p.src[end] == '\n' {
+		if n >= 2 {
+			p.out.WriteString("<br>\n")
+		} else {
+			p.out.WriteByte('\n')
+		}
+		p.plain.WriteByte('\n')
+		p.pos = end + 1
+		return
+	}
+	p.literal(p.src[p.pos:end])
+	p.pos = end
+}
+
+// codeSpan 行内代码
+func (p *inlineParser) codeSpan() {
+	n := runLength(p.src, p.pos, '`')
+	end := findCodeSpanEnd(p.src, p.pos+n, n)
+	if end < 0 {
+		p.literal(p.src[p.pos : p.pos+n])
+		p.pos += n
+		return
+	}
+
+	code := strings.ReplaceAll(p.src[p.pos+n:end], "\n", " ")
+	if len(code) >= 2 && code[0] == ' ' && code[len(code)-1] == ' ' && strings.TrimSpace(code) != "" {
+		code = code[1 : len(code)-1]
+	}
+
+	p.out.WriteString("<code>" + escapeHTML(code) + "</code>")
+	p.plain.WriteString(code)
+	p.pos = end + n
+}
+
+// emphasis 强调和加粗
+func (p *inlineParser) emphasis(c byte) {
+	n := runLength(p.src, p.pos, c)
+	prev, next := p.around(p.pos, n)
+
+	// 左侧定界：后面不是空白；下划线不能在单词内部开启
+	canOpen := !unicode.IsSpace(next) && !(isPunctRune(next) && !unicode.IsSpace(prev) && !isPunctRune(prev))
+	if c == '_' && isAlnum(prev) {
+		canOpen = false
+	}
+
+	if canOpen {
+		if end, m := p.findClose(p.pos+n, c, n); end > p.pos+n {
+			html, text := p.nested(p.src[p.pos+n:end], p.noLinks)
+			switch {
+			case n == 1:
+				p.out.WriteString("<em>" + html + "</em>")
+			case n == 2:
+				p.out.WriteString("<strong>" + html + "</strong>")
+			default:
+				p.out.WriteString("<em><strong>" + html + "</strong></em>")
+			}
+			p.plain.WriteString(text)
+			p.pos = end + m
+			return
+		}
+	}
+
+	p.literal(p.src[p.pos : p.pos+n])
+	p.pos += n
+}
+
+// strikethrough 删除线 ~~text~~
+func (p *inlineParser) strikethrough() {
+	n := runLength(p.src, p.pos, '~')
+	_, next := p.around(p.pos, n)
+	if n == 2 && !unicode.IsSpace(next) {
+		if end, m := p.findClose(p.pos+n, '~', n); end > p.pos+n {
+			html, text := p.nested(p.src[p.pos+n:end], p.noLinks)
+			p.out.WriteString("<del>" + html + "</del>")
+			p.plain.WriteString(text)
+			p.pos = end + m
+			return
+		}
+	}
+	p.literal(p.src[p.pos : p.pos+n])
+	p.pos += n
+}
+
+// findClose 查找与开启定界符匹配的结束定界符，返回位置和长度
+func (p *inlineParser) findClose(from int, c byte, n int) (int, int) {
+	for j := from; j < len(p.src); {
+		switch p.src[j] {
+		case '\\':
+			j += 2
+			continue
+		case '`':
+			m := runLength(p.src, j, '`')
+			if end := findCodeSpanEnd(p.src, j+m, m); end >= 0 {
+				j = end + m
+			} else {
+				j += m
+			}
+			continue
+		case c:
+			m := runLength(p.src, j, c)
+			prev, next := p.around(j, m)
+
+			// 右侧定界：前面不是空白；下划线不能在单词内部结束
+			canClose := !unicode.IsSpace(prev) && !(isPunctRune(prev) && !unicode.IsSpace(next) && !isPunctRune(next))
+			if c == '_' && isAlnum(next) {
+				canClose = false
+			}
+			if canClose && (m == n || (n >= 3 && m >= 3)) {
+				return j, m
+			}
+			j += m
+			continue
+		}
+		j++
+	}
+	return -1, 0
+}
+
+// link 链接和图片：[text](url "title")、[text][ref]、[ref]
+func (p *inlineParser) link(image bool) bool {
+	open := p.pos
+	if image {
+		open++
+	}
+	close := findBracketEnd(p.src, open)
+	if close < 0 {
+		return false
+	}
+	label := p.src[open+1 : close]
+
+	var dest, title string
+	found := false
+	end := close + 1
+
+	switch {
+	case end < len(p.src) && p.src[end] == '(':
+		dest, title, end, found = parseDestination(p.src, end)
+	case end < len(p.src) && p.src[end] == '[':
+		if k := strings.IndexByte(p.src[end+1:], ']'); k >= 0 {
+			key := p.src[end+1 : end+1+k]
+			if key == "" {
+				key = label
+			}
+			if ref, ok := p.r.refs[normalizeLabel(key)]; ok {
+				dest, title, found = ref.url, ref.title, true
+				end = end + 1 + k + 1
+			}
+		}
+	}
+	if !found {
+		ref, ok := p.r.refs[normalizeLabel(label)]
+		if !ok {
+			return false
+		}
+		dest, title, found = ref.url, ref.title, true
+		end = close + 1
+	}
+
+	if image {
+		_, alt := p.nested(label, true)
+		if url, ok := safeURL(dest, true); ok {
+			p.out.WriteString(`<img src="` + escapeHTML(url) + `" alt="` + escapeHTML(alt) + `"`)
+			if title != "" {
+				p.out.WriteString(` title="` + escapeHTML(title) + `"`)
+			}
+			p.out.WriteString(` loading="lazy">`)
+		} else {
+			p.literal(alt)
+		}
+	} else {
+		html, text := p.nested(label, true)
+		if url, ok := safeURL(dest, false); ok {
+			p.out.WriteString(`<a href="` + escapeHTML(url) + `"`)
+			if title != "" {
+				p.out.WriteString(` title="` + escapeHTML(title) + `"`)
+			}
+			p.out.WriteString(">" + html + "</a>")
+		} else {
+			p.out.WriteString(html)
+		}
+		p.plain.WriteString(text)
+	}
+
+	p.pos = end
+	return true
+}
+
+// angle 尖括号：自动链接、HTML 注释，其余原始 HTML 转义输出
+func (p *inlineParser) angle() {
+	rest := p.src[p.pos:]
+
+	if strings.HasPrefix(rest, "<!--") {
+		if end := strings.Index(rest[4:], "-->"); end >= 0 {
+			p.pos += 4 + end + 3
+			return
+		}
+	}
+
+	if !p.noLinks {
+		if m := reAutolink.FindStringSubmatch(rest); m != nil {
+			if url, ok := safeURL(m[1], false); ok {
+				p.out.WriteString(`<a href="` + escapeHTML(url) + `">` + escapeHTML(m[1]) + "</a>")
+				p.plain.WriteString(m[1])
+				p.pos += len(m[0])
+				return
+			}
+		}
+		if m := reEmailLink.FindStringSubmatch(rest); m != nil {
+			p.out.WriteString(`<a href="mailto:` + escapeHTML(m[1]) + `">` + escapeHTML(m[1]) + "</a>")
+			p.plain.WriteString(m[1])
+			p.pos += len(m[0])
+			return
+		}
+	}
+
+	p.literal("<")
+	p.pos++
+}
+
+// entity HTML 实体原样保留，其余 & 转义
+func (p *inlineParser) entity() {
+	if m := reEntity.FindString(p.src[p.pos:]); m != "" {
+		p.out.WriteString(m)
+		p.plain.WriteString(html.UnescapeString(m))
+		p.pos += len(m)
+		return
+	}
+	p.literal("&")
+	p.pos++
+}
+
+// bareURL 裸链接自动识别（http://、https://、www.）
+func (p *inlineParser) bareURL() bool {
+	if p.pos > 0 {
+		prev, _ := utf8.DecodeLastRuneInString(p.src[:p.pos])
+		if isAlnum(prev) || prev == '/' || prev == '@' {
+			return false
+		}
+	}
+
+	m := reBareURL.FindString(p.src[p.pos:])
+	if m == "" {
+		return false
+	}
+	// 去除不成对的结尾右括号
+	for strings.HasSuffix(m, ")") && strings.Count(m, ")") > strings.Count(m, "(") {
+		m = m[:len(m)-1]
+	}
+
+	href := m
+	if strings.HasPrefix(m, "www.") {
+		href = "http://" + m
+	}
+	p.out.WriteString(`<a href="` + escapeHTML(href) + `">` + escapeHTML(m) + "</a>")
+	p.plain.WriteString(m)
+	p.pos += len(m)
+	return true
+}
+
+// around 返回定界符前后的字符（行首行尾视为空白）
+func (p *inlineParser) around(pos, n int) (rune, rune) {
+	prev, next := ' ', ' '
+	if pos > 0 {
+		prev, _ = utf8.DecodeLastRuneInString(p.src[:pos])
+	}
+	if pos+n < len(p.src) {
+		next, _ = utf8.DecodeRuneInString(p.src[pos+n:])
+	}
+	return prev, next
+}
+
+// ===========================================
+// 辅助函数
+// ===========================================
+
+// runLength 从 pos 开始连续字符 c 的数量
+func runLength(s string, pos int, c byte) int {
+	n := 0
+	for pos+n < len(s) && s[pos+n] == c {
+		n++
+	}
+	return n
+}
+
+// findCodeSpanEnd 查找长度恰好为 n 的反引号串
+func findCodeSpanEnd(s string, from, n int) int {
+	for j := from; j < len(s); {
+		if s[j] != '`' {
+			j++
+			continue
+		}
+		m := runLength(s, j, '`')
+		if m == n {
+			return j
+		}
+		j += m
+	}
+	return -1
+}
+
+// findBracketEnd 查找与 open 处 [ 匹配的 ]
+func findBracketEnd(s string, open int) int {
+	depth := 0
+	for j := open; j < len(s); j++ {
+		switch s[j] {
+		case '\\':
+			j++
+		case '`':
+			m := runLength(s, j, '`')
+			if end := findCodeSpanEnd(s, j+m, m); end >= 0 {
+				j = end + m - 1
+			} else {
+				j += m - 1
+			}
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				return j
+			}
+		}
+	}
+	return -1
+}
+
+// parseDestination 解析 (url "title")，返回地址、标题、结束位置
+func parseDestination(s string, open int) (string, string, int, bool) {
+	i := skipSpaces(s, open+1)
+
+	var dest string
+	if i < len(s) && s[i] == '<' {
+		end := strings.IndexAny(s[i+1:], ">\n")
+		if end < 0 || s[i+1+end] != '>' {
+			return "", "", 0, false
+		}
+		dest = s[i+1 : i+1+end]
+		i += end + 2
+	} else {
+		start, depth := i, 0
+	loop:
+		for i < len(s) {
+			switch s[i] {
+			case '\\':
+				i++
+			case ' ', '\n', '\t':
+				break loop
+			case '(':
+				depth++
+			case ')':
+				if depth == 0 {
+					break loop
+				}
+				depth--
+			}
+			i++
+		}
+		if i > len(s) {
+			i = len(s)
+		}
+		dest = s[start:i]
+	}
+
+	i = skipSpaces(s, i)
+	title := ""
+	if i < len(s) && (s[i] == '"' || s[i] == '\'' || s[i] == '(') {
+		closer := s[i]
+		if closer == '(' {
+			closer = ')'
+		}
+		end := strings.IndexByte(s[i+1:], closer)
+		if end < 0 {
+			return "", "", 0, false
+		}
+		title = unescapeBackslash(s[i+1 : i+1+end])
+		i = skipSpaces(s, i+end+2)
+	}
+
+	if i >= len(s) || s[i] != ')' {
+		return "", "", 0, false
+	}
+	return unescapeBackslash(dest), title, i + 1, true
+}
+
+// skipSpaces 跳过空白
+func skipSpaces(s string, i int) int {
+	for i < len(s) && (s[i] == ' ' || s[i] == '\n' || s[i] == '\t') {
+		i++
+	}
+	return i
+}
+
+// unescapeBackslash 去除反斜杠转义
+func unescapeBackslash(s string) string {
+	if !strings.Contains(s, "\\") {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) && isASCIIPunct(s[i+1]) {
+			i++
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// isASCIIPunct 是否为 ASCII 标点
+func isASCIIPunct(c byte) bool {
+	return strings.IndexByte("!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", c) >= 0
+}
+
+// isPunctRune 是否为标点符号
+func isPunctRune(r rune) bool {
+	return unicode.IsPunct(r) || unicode.IsSymbol(r)
+}
+
+// isAlnum 是否为字母或数字
+func isAlnum(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,164 @@
+// Package markdown Markdown 渲染
+// 将 Markdown 渲染为安全的 HTML（原始 HTML 一律转义，链接协议白名单），
+// 同时生成目录、代码块语言信息、字数和阅读时长
+package markdown
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Version 渲染器版本，渲染规则变化时递增以使缓存失效
+const Version = "1"
+
+// ===========================================
+// 渲染结果
+// ===========================================
+
+// TOCItem 目录项
+type TOCItem struct {
+	Level    int        `json:"level"`
+	Text     string     `json:"text"`
+	ID       string     `json:"id"` // 标题锚点
+	Children []*TOCItem `json:"children,omitempty"`
+}
+
+// CodeBlock 代码块信息
+type CodeBlock struct {
+	Language string `json:"language"`
+	Lines    int    `json:"lines"`
+}
+
+// Result 渲染结果
+type Result struct {
+	HTML           string
+	TOC            []*TOCItem
+	CodeBlocks     []CodeBlock
+	WordCount      int
+	ReadingMinutes int
+}
+
+// Languages 代码块中出现的语言（去重，保持出现顺序）
+func (r *Result) Languages() []string {
+	seen := make(map[string]bool)
+	var langs []string
+	for _, block := range r.CodeBlocks {
+		if block.Language == "" || seen[block.Language] {
+			continue
+		}
+		seen[block.Language] = true
+		langs = append(langs, block.Language)
+	}
+	return langs
+}
+
+// ===========================================
+// 渲染入口
+// ===========================================
+
+// Render 渲染 Markdown
+func Render(src string) *Result {
+	src = strings.ReplaceAll(src, "\r\n", "\n")
+	src = strings.ReplaceAll(src, "\r", "\n")
+
+	r := &renderer{
+		refs: make(map[string]linkRef),
+		ids:  make(map[string]bool),
+	}
+
+	lines := r.collectRefs(strings.Split(src, "\n"))
+
+	var out strings.Builder
+	r.blocks(lines, &out, false)
+
+	words := Count(r.plain.String())
+
+	return &Result{
+		HTML:           out.String(),
+		TOC:            buildTOC(r.headings),
+		CodeBlocks:     r.codes,
+		WordCount:      words.Total(),
+		ReadingMinutes: words.ReadingMinutes(),
+	}
+}
+
+// ===========================================
+// 渲染状态
+// ===========================================
+
+// heading 已渲染的标题
+type heading struct {
+	level int
+	text  string
+	id    string
+}
+
+// linkRef 引用式链接定义
+type linkRef struct {
+	url   string
+	title string
+}
+
+// renderer 单次渲染的状态
+type renderer struct {
+	refs     map[string]linkRef
+	ids      map[string]bool // 已使用的标题锚点
+	headings []heading
+	codes    []CodeBlock
+	plain    strings.Builder // 正文纯文本（不含代码块），用于统计字数
+}
+
+// headingID 根据标题文本生成稳定且唯一的锚点
+func (r *renderer) headingID(text string) string {
+	var b strings.Builder
+	dash := false
+	for _, ch := range strings.ToLower(text) {
+		switch {
+		case unicode.IsLetter(ch) || unicode.IsDigit(ch):
+			b.WriteRune(ch)
+			dash = false
+		case ch == ' ' || ch == '-' || ch == '_':
+			if b.Len() > 0 && !dash {
+				b.WriteByte('-')
+				dash = true
+			}
+		}
+	}
+
+	base := strings.TrimRight(b.String(), "-")
+	if base == "" {
+		base = "section"
+	}
+
+	// 重复的标题追加序号
+	id := base
+	for n := 1; r.ids[id]; n++ {
+		id = base + "-" + strconv.Itoa(n)
+	}
+	r.ids[id] = true
+	return id
+}
+
+// buildTOC 根据标题层级构建嵌套目录
+func buildTOC(headings []heading) []*TOCItem {
+	var roots []*TOCItem
+	var stack []*TOCItem
+
+	for _, h := range headings {
+		item := &TOCItem{Level: h.level, Text: h.text, ID: h.id}
+
+		for len(stack) > 0 && stack[len(stack)-1].Level >= h.level {
+			stack = stack[:len(stack)-1]
+		}
+		if len(stack) == 0 {
+			roots = append(roots, item)
+		} else {
+			parent := stack[len(stack)-1]
+			parent.Children = append(parent.Children, item)
+		}
+		stack = append(stack, item)
+	}
+
+	return roots
+}
@@ -0,0 +1,126 @@
+package markdown
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRenderTOC(t *testing.T) {
+	src := "# Hello World\n" +
+		"## Hello World\n" +
+		"### 你好，世界！\n" +
+		"## !!!\n" +
+		"# C++ & Go_lang\n" +
+		"## Hello   World --- again\n" +
+		"## Hello World\n"
+
+	want := []*TOCItem{
+		{Level: 1, Text: "Hello World", ID: "hello-world", Children: []*TOCItem{
+			{Level: 2, Text: "Hello World", ID: "hello-world-1", Children: []*TOCItem{
+				{Level: 3, Text: "你好，世界！", ID: "你好世界"},
+			}},
+			{Level: 2, Text: "!!!", ID: "section"},
+		}},
+		{Level: 1, Text: "C++ & Go_lang", ID: "c-go-lang", Children: []*TOCItem{
+			{Level: 2, Text: "Hello   World --- again", ID: "hello-world-again"},
+			{Level: 2, Text: "Hello World", ID: "hello-world-2"},
+		}},
+	}
+
+	result := Render(src)
+	if !reflect.DeepEqual(result.TOC, want) {
+		t.Errorf("TOC = %s, want %s", dumpTOC(result.TOC), dumpTOC(want))
+	}
+}
+
+func TestHeadingID(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"Hello World", "hello-world"},
+		{"  Trim  Spaces  ", "trim-spaces"},
+		{"snake_case and kebab-case", "snake-case-and-kebab-case"},
+		{"Go 1.25 发布说明", "go-125-发布说明"},
+		{"中文标题", "中文标题"},
+		{"日本語の見出し", "日本語の見出し"},
+		{"Ünïcödé", "ünïcödé"},
+		{"<script>", "script"},
+		{`"quoted" & 'single'`, "quoted-single"},
+		{"!!!", "section"},
+		{"", "section"},
+	}
+
+	for _, tt := range tests {
+		r := &renderer{ids: make(map[string]bool)}
+		if got := r.headingID(tt.text); got != tt.want {
+			t.Errorf("headingID(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestCount(t *testing.T) {
+	tests := []struct {
+		text    string
+		want    WordStats
+		minutes int
+	}{
+		{text: "", want: WordStats{}, minutes: 0},
+		{text: "hello world", want: WordStats{Words: 2}, minutes: 1},
+		{text: "it's don’t rock-n-roll 123", want: WordStats{Words: 6}, minutes: 1},
+		{text: "中文字数统计", want: WordStats{CJK: 6}, minutes: 1},
+		{text: "中文字数统计 hello world, it's fine 123", want: WordStats{CJK: 6, Words: 5}, minutes: 1},
+		{text: "用Go写博客", want: WordStats{CJK: 4, Words: 1}, minutes: 1},
+		{text: "ひらがなとカタカナ", want: WordStats{CJK: 9}, minutes: 1},
+		{text: "한국어 문장", want: WordStats{CJK: 5}, minutes: 1},
+		{text: "，。！？", want: WordStats{}, minutes: 0},
+	}
+
+	for _, tt := range tests {
+		got := Count(tt.text)
+		if got != tt.want {
+			t.Errorf("Count(%q) = %+v, want %+v", tt.text, got, tt.want)
+		}
+		if m := got.ReadingMinutes(); m != tt.minutes {
+			t.Errorf("Count(%q).ReadingMinutes() = %d, want %d", tt.text, m, tt.minutes)
+		}
+	}
+}
+
+func TestRenderWordCount(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     string
+		words   int
+		minutes int
+	}{
+		{name: "plain", src: "中文 and English", words: 4, minutes: 1},
+		{name: "markup is not counted", src: "# 标题\n\n**加粗** [链接](https://example.com) `code`", words: 7, minutes: 1},
+		{name: "code blocks are skipped", src: "正文\n\n```go\nfunc main() {}\n```", words: 2, minutes: 1},
+		{name: "escaped html counts as text", src: "a &amp; b", words: 2, minutes: 1},
+		{name: "long cjk text", src: strings.Repeat("字", 801), words: 801, minutes: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Render(tt.src)
+			if result.WordCount != tt.words || result.ReadingMinutes != tt.minutes {
+				t.Errorf("Render(%q) words = %d, minutes = %d, want %d, %d",
+					tt.src, result.WordCount, result.ReadingMinutes, tt.words, tt.minutes)
+			}
+		})
+	}
+}
+
+func dumpTOC(items []*TOCItem) string {
+	s := "["
+	for _, item := range items {
+		s += item.ID
+		if len(item.Children) > 0 {
+			s += dumpTOC(item.Children)
+		}
+		s += " "
+	}
+	return s + "]"
+}
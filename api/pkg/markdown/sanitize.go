@@ -0,0 +1,52 @@
+package markdown
+
+import (
+	"html"
+	"strings"
+)
+
+// ===========================================
+// 安全处理
+// ===========================================
+
+// allowedSchemes 允许的链接协议
+var allowedSchemes = map[string]bool{
+	"http":   true,
+	"https":  true,
+	"mailto": true,
+	"tel":    true,
+	"ftp":    true,
+}
+
+// escapeHTML 转义 HTML 特殊字符
+func escapeHTML(s string) string {
+	return html.EscapeString(s)
+}
+
+// safeURL 校验链接地址，拒绝 javascript: 等危险协议
+// 相对地址和锚点直接放行；图片额外允许 base64 编码的 data URI
+func safeURL(raw string, image bool) (string, bool) {
+	url := strings.TrimSpace(raw)
+
+	// 去除空白和控制字符后判断协议，防止 "java\tscript:" 之类的绕过
+	cleaned := strings.Map(func(r rune) rune {
+		if r <= ' ' || r == 0x7f {
+			return -1
+		}
+		return r
+	}, strings.ToLower(url))
+
+	i := strings.IndexAny(cleaned, ":/?#")
+	if i < 0 || cleaned[i] != ':' {
+		return url, true
+	}
+
+	scheme := cleaned[:i]
+	if allowedSchemes[scheme] {
+		return url, true
+	}
+	if image && scheme == "data" && reDataImage.MatchString(cleaned) {
+		return url, true
+	}
+	return "", false
+}
@@ -0,0 +1,217 @@
+package markdown
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestSafeURL(t *testing.T) {
+	tests := []struct {
+		url   string
+		image bool
+		ok    bool
+	}{
+		{url: "https://example.com", ok: true},
+		{url: "http://example.com/a?b=1", ok: true},
+		{url: "mailto:me@example.com", ok: true},
+		{url: "/posts/hello", ok: true},
+		{url: "#section", ok: true},
+		{url: "relative/path:with-colon", ok: true},
+		{url: "javascript:alert(1)", ok: false},
+		{url: "JavaScript:alert(1)", ok: false},
+		{url: "  javascript:alert(1)", ok: false},
+		{url: "java\tscript:alert(1)", ok: false},
+		{url: "java\nscript:alert(1)", ok: false},
+		{url: "java\x00script:alert(1)", ok: false},
+		{url: "vbscript:msgbox(1)", ok: false},
+		{url: "data:text/html;base64,PHNjcmlwdD4=", ok: false},
+		{url: "data:text/html;base64,PHNjcmlwdD4=", image: true, ok: false},
+		{url: "data:image/png;base64,iVBORw0KGgo=", ok: false},
+		{url: "data:image/png;base64,iVBORw0KGgo=", image: true, ok: true},
+		{url: "DATA:IMAGE/PNG;BASE64,IVBORW0KGGO=", image: true, ok: true},
+		{url: "data:image/svg+xml;base64,PHN2Zz4=", image: true, ok: false},
+		{url: "data:image/png,<svg onload=alert(1)>", image: true, ok: false},
+	}
+
+	for _, tt := range tests {
+		_, ok := safeURL(tt.url, tt.image)
+		if ok != tt.ok {
+			t.Errorf("safeURL(%q, image=%v) ok = %v, want %v", tt.url, tt.image, ok, tt.ok)
+		}
+	}
+}
+
+func TestRenderSanitizes(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{
+			name: "javascript link",
+			src:  "[x](javascript:alert(1))",
+			want: "<p>x</p>\n",
+		},
+		{
+			name: "javascript link with leading spaces",
+			src:  "[x](  javascript:alert(1))",
+			want: "<p>x</p>\n",
+		},
+		{
+			name: "javascript reference link",
+			src:  "[x][r]\n\n[r]: javascript:alert(1)",
+			want: "<p>x</p>\n",
+		},
+		{
+			name: "javascript image",
+			src:  "![x](javascript:alert(1))",
+			want: "<p>x</p>\n",
+		},
+		{
+			name: "javascript autolink",
+			src:  "<javascript:alert(1)>",
+			want: "<p>&lt;javascript:alert(1)&gt;</p>\n",
+		},
+		{
+			name: "data link",
+			src:  "[x](data:text/html;base64,PHNjcmlwdD4=)",
+			want: "<p>x</p>\n",
+		},
+		{
+			name: "svg data image",
+			src:  "![x](data:image/svg+xml;base64,PHN2Zz4=)",
+			want: "<p>x</p>\n",
+		},
+		{
+			name: "raster data image",
+			src:  "![x](data:image/png;base64,iVBORw0KGgo=)",
+			want: `<p><img src="data:image/png;base64,iVBORw0KGgo=" alt="x" loading="lazy"></p>` + "\n",
+		},
+		{
+			name: "entity encoded scheme stays literal",
+			src:  "[x](&#106;avascript:alert(1))",
+			want: `<p><a href="&amp;#106;avascript:alert(1)">x</a></p>` + "\n",
+		},
+		{
+			name: "script tag",
+			src:  "<script>alert(1)</script>",
+			want: "<p>&lt;script&gt;alert(1)&lt;/script&gt;</p>\n",
+		},
+		{
+			name: "inline html with event handler",
+			src:  "a <img src=x onerror=alert(1)> b",
+			want: "<p>a &lt;img src=x onerror=alert(1)&gt; b</p>\n",
+		},
+		{
+			name: "html in blockquote",
+			src:  "> <i>q</i>",
+			want: "<blockquote>\n<p>&lt;i&gt;q&lt;/i&gt;</p>\n</blockquote>\n",
+		},
+		{
+			name: "html in table cell",
+			src:  "| a |\n|---|\n| <b> |",
+			want: "<table>\n<thead>\n<tr>\n<th>a</th>\n</tr>\n</thead>\n<tbody>\n<tr>\n<td>&lt;b&gt;</td>\n</tr>\n</tbody>\n</table>\n",
+		},
+		{
+			name: "html comment is dropped",
+			src:  "<!-- more -->",
+			want: "",
+		},
+		{
+			name: "html in code span",
+			src:  "`<b>`",
+			want: "<p><code>&lt;b&gt;</code></p>\n",
+		},
+		{
+			name: "html in code block",
+			src:  "```html\n<script>\n```",
+			want: `<pre><code class="language-html">&lt;script&gt;` + "\n</code></pre>\n",
+		},
+		{
+			name: "quote in link destination",
+			src:  `[x](http://a.com/"onmouseover="alert(1) "t")`,
+			want: `<p><a href="http://a.com/&#34;onmouseover=&#34;alert(1)" title="t">x</a></p>` + "\n",
+		},
+		{
+			name: "quotes in link title and image alt",
+			src:  `![a"b](/x.png) [y](/y 'say "hi"')`,
+			want: `<p><img src="/x.png" alt="a&#34;b" loading="lazy"> <a href="/y" title="say &#34;hi&#34;">y</a></p>` + "\n",
+		},
+		{
+			name: "ampersand in autolink",
+			src:  "<https://example.com/a?b=1&c=2>",
+			want: `<p><a href="https://example.com/a?b=1&amp;c=2">https://example.com/a?b=1&amp;c=2</a></p>` + "\n",
+		},
+		{
+			name: "bare url stops at angle bracket",
+			src:  "https://example.com/x<y",
+			want: `<p><a href="https://example.com/x">https://example.com/x</a>&lt;y</p>` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Render(tt.src).HTML
+			if got != tt.want {
+				t.Errorf("Render(%q)\n got %q\nwant %q", tt.src, got, tt.want)
+			}
+		})
+	}
+}
+
+var (
+	// reTag 输出中的标签
+	reTag = regexp.MustCompile(`<(/?)([^\s/>]+)([^>]*)>`)
+	// reAttr 标签中的属性，值可省略
+	reAttr = regexp.MustCompile(`\s+([^\s=/>]+)(?:="[^"]*")?`)
+	// allowedTags 渲染器生成的标签
+	allowedTags = map[string]bool{
+		"p": true, "h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+		"a": true, "img": true, "code": true, "pre": true, "blockquote": true, "hr": true, "br": true,
+		"ul": true, "ol": true, "li": true, "input": true, "em": true, "strong": true, "del": true,
+		"table": true, "thead": true, "tbody": true, "tr": true, "th": true, "td": true,
+	}
+	// allowedAttrs 渲染器生成的属性
+	allowedAttrs = map[string]bool{
+		"id": true, "href": true, "src": true, "alt": true, "title": true, "loading": true,
+		"class": true, "start": true, "align": true, "type": true, "checked": true, "disabled": true,
+	}
+)
+
+// TestRenderNoRawTags 任意输入渲染后只包含渲染器自身生成的标签和属性
+func TestRenderNoRawTags(t *testing.T) {
+	inputs := []string{
+		"<iframe src=//evil>",
+		"<svg/onload=alert(1)>",
+		"[<img src=x onerror=alert(1)>](https://example.com)",
+		"![<script>](/x.png)",
+		"**<script>**",
+		"- <script>alert(1)</script>",
+		"- [ ] <b>task</b>",
+		"# <script>alert(1)</script>",
+		"```\"><script>\nx\n```",
+		"[x](https://example.com \"a\" onclick=\"alert(1)\")",
+		"[x](https://example.com 'a\" onclick=\"alert(1)')",
+		"<a href=\"javascript:alert(1)\">x</a>",
+		"| <b onclick=alert(1)> |\n|---|\n| x |",
+	}
+
+	for _, src := range inputs {
+		out := Render(src).HTML
+		for _, tag := range reTag.FindAllStringSubmatch(out, -1) {
+			if !allowedTags[strings.ToLower(tag[2])] {
+				t.Errorf("Render(%q) = %q contains tag <%s>", src, out, tag[2])
+				continue
+			}
+			for _, attr := range reAttr.FindAllStringSubmatch(tag[3], -1) {
+				if !allowedAttrs[strings.ToLower(attr[1])] {
+					t.Errorf("Render(%q) = %q contains attribute %s", src, out, attr[1])
+				}
+			}
+			if rest := reAttr.ReplaceAllString(tag[3], ""); strings.TrimSpace(rest) != "" {
+				t.Errorf("Render(%q) = %q has malformed tag %q", src, out, tag[0])
+			}
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package markdown
+
+import (
+	"math"
+	"unicode"
+
+	"kuaiyu/pkg/tokenizer"
+)
+
+// ===========================================
+// 字数与阅读时长
+// ===========================================
+
+const (
+	// cjkPerMinute 中日韩文字每分钟阅读字数
+	cjkPerMinute = 400
+	// wordsPerMinute 英文每分钟阅读单词数
+	wordsPerMinute = 220
+)
+
+// WordStats 字数统计
+type WordStats struct {
+	CJK   int // 中日韩文字数（每个字计一次）
+	Words int // 其他语言的单词数
+}
+
+// Total 总字数
+func (w WordStats) Total() int {
+	return w.CJK + w.Words
+}
+
+// ReadingMinutes 预计阅读分钟数，非空内容至少 1 分钟
+func (w WordStats) ReadingMinutes() int {
+	if w.Total() == 0 {
+		return 0
+	}
+	minutes := float64(w.CJK)/cjkPerMinute + float64(w.Words)/wordsPerMinute
+	return int(math.Max(1, math.Ceil(minutes)))
+}
+
+// Count 统计纯文本字数：中日韩文字按字计，其他文字按单词计
+func Count(text string) WordStats {
+	var stats WordStats
+	inWord := false
+	for _, r := range text {
+		switch {
+		case tokenizer.IsCJK(r):
+			stats.CJK++
+			inWord = false
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || (inWord && (r == '\'' || r == '’')):
+			if !inWord {
+				stats.Words++
+				inWord = true
+			}
+		default:
+			inWord = false
+		}
+	}
+	return stats
+}
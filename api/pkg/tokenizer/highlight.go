@@ -0,0 +1,163 @@
+package tokenizer
+
+import (
+	"html"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// ===========================================
+// 关键词高亮
+// ===========================================
+
+const (
+	// HighlightOpen 高亮开始标签
+	HighlightOpen = "<mark>"
+	// HighlightClose 高亮结束标签
+	HighlightClose = "</mark>"
+)
+
+// needle 待高亮的关键词
+type needle struct {
+	text []rune
+	word bool // 英文单词需要匹配完整单词
+}
+
+// span 高亮区间 [start, end)
+type span struct {
+	start, end int
+}
+
+// Highlight 在文本中高亮查询关键词，返回 HTML 转义后的片段
+// maxRunes > 0 时截取第一个命中位置附近的片段
+func Highlight(text, query string, maxRunes int) string {
+	runes := []rune(text)
+	lower := make([]rune, len(runes))
+	for i, r := range runes {
+		lower[i] = unicode.ToLower(r)
+	}
+
+	spans := findSpans(lower, queryNeedles(query))
+
+	// 截取片段
+	start, end := 0, len(runes)
+	if maxRunes > 0 && len(runes) > maxRunes {
+		if len(spans) > 0 {
+			start = spans[0].start - maxRunes/4
+			if start < 0 {
+				start = 0
+			}
+		}
+		end = start + maxRunes
+		if end > len(runes) {
+			end = len(runes)
+			start = end - maxRunes
+		}
+	}
+
+	var sb strings.Builder
+	if start > 0 {
+		sb.WriteString("…")
+	}
+
+	pos := start
+	for _, sp := range spans {
+		if sp.end <= start || sp.start >= end {
+			continue
+		}
+		s, e := sp.start, sp.end
+		if s < start {
+			s = start
+		}
+		if e > end {
+			e = end
+		}
+		sb.WriteString(html.EscapeString(string(runes[pos:s])))
+		sb.WriteString(HighlightOpen)
+		sb.WriteString(html.EscapeString(string(runes[s:e])))
+		sb.WriteString(HighlightClose)
+		pos = e
+	}
+	sb.WriteString(html.EscapeString(string(runes[pos:end])))
+
+	if end < len(runes) {
+		sb.WriteString("…")
+	}
+
+	return sb.String()
+}
+
+// queryNeedles 从查询中提取高亮关键词：完整片段以及中文 bigram
+func queryNeedles(query string) []needle {
+	var needles []needle
+	for _, seg := range Segments(query) {
+		if !seg.CJK {
+			if wordTerm(seg.Text) != "" {
+				needles = append(needles, needle{text: seg.Text, word: true})
+			}
+			continue
+		}
+		needles = append(needles, needle{text: seg.Text})
+		for i := 0; i+1 < len(seg.Text); i++ {
+			needles = append(needles, needle{text: seg.Text[i : i+2]})
+		}
+	}
+	return needles
+}
+
+// findSpans 查找所有命中区间并合并重叠部分
+func findSpans(text []rune, needles []needle) []span {
+	var spans []span
+	for _, n := range needles {
+		for i := 0; i+len(n.text) <= len(text); i++ {
+			if !runesEqual(text[i:i+len(n.text)], n.text) {
+				continue
+			}
+			if n.word {
+				if i > 0 && isWordRune(text[i-1]) && !IsCJK(text[i-1]) {
+					continue
+				}
+				if j := i + len(n.text); j < len(text) && isWordRune(text[j]) && !IsCJK(text[j]) {
+					continue
+				}
+			}
+			spans = append(spans, span{start: i, end: i + len(n.text)})
+		}
+	}
+
+	if len(spans) == 0 {
+		return nil
+	}
+
+	sort.Slice(spans, func(i, j int) bool {
+		return spans[i].start < spans[j].start
+	})
+
+	merged := []span{spans[0]}
+	for _, sp := range spans[1:] {
+		last := &merged[len(merged)-1]
+		if sp.start <= last.end {
+			if sp.end > last.end {
+				last.end = sp.end
+			}
+			continue
+		}
+		merged = append(merged, sp)
+	}
+
+	return merged
+}
+
+// runesEqual 比较两个 rune 切片
+func runesEqual(a, b []rune) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,149 @@
+// Package tokenizer 中英文混合分词
+// 英文/数字按单词切分，中日韩文字按 unigram + bigram 切分，用于全文检索和相似度计算
+package tokenizer
+
+import (
+	"unicode"
+)
+
+// maxTermRunes 单个词项的最大长度
+const maxTermRunes = 32
+
+// ===========================================
+// 文本片段
+// ===========================================
+
+// Segment 连续的同类文本片段
+type Segment struct {
+	Text []rune // 已转小写
+	CJK  bool   // 是否为中日韩文字
+}
+
+// IsCJK 判断是否为中日韩文字
+func IsCJK(r rune) bool {
+	return unicode.Is(unicode.Han, r) ||
+		unicode.Is(unicode.Hiragana, r) ||
+		unicode.Is(unicode.Katakana, r) ||
+		unicode.Is(unicode.Hangul, r)
+}
+
+// isWordRune 判断是否为单词字符
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
+
+// Segments 将文本拆分为单词片段和中日韩文字片段，其余字符视为分隔符
+func Segments(text string) []Segment {
+	var segments []Segment
+	var current []rune
+	currentCJK := false
+
+	flush := func() {
+		if len(current) > 0 {
+			segments = append(segments, Segment{Text: current, CJK: currentCJK})
+			current = nil
+		}
+	}
+
+	for _, r := range text {
+		r = unicode.ToLower(r)
+		switch {
+		case IsCJK(r):
+			if !currentCJK {
+				flush()
+			}
+			currentCJK = true
+			current = append(current, r)
+		case isWordRune(r):
+			if currentCJK {
+				flush()
+			}
+			currentCJK = false
+			current = append(current, r)
+		default:
+			flush()
+		}
+	}
+	flush()
+
+	return segments
+}
+
+// ===========================================
+// 分词
+// ===========================================
+
+// Tokenize 生成索引词项（保留重复，便于统计词频）
+// 英文单词整体作为词项，中日韩文字同时生成 unigram 和 bigram
+func Tokenize(text string) []string {
+	var terms []string
+
+	for _, seg := range Segments(text) {
+		if !seg.CJK {
+			if term := wordTerm(seg.Text); term != "" {
+				terms = append(terms, term)
+			}
+			continue
+		}
+
+		for i := range seg.Text {
+			terms = append(terms, string(seg.Text[i]))
+			if i+1 < len(seg.Text) {
+				terms = append(terms, string(seg.Text[i:i+2]))
+			}
+		}
+	}
+
+	return terms
+}
+
+// QueryTerms 生成查询词项（去重）
+// 单个汉字使用 unigram，多个连续汉字只使用 bigram 以提高准确度
+func QueryTerms(query string) []string {
+	seen := make(map[string]bool)
+	var terms []string
+
+	add := func(term string) {
+		if term != "" && !seen[term] {
+			seen[term] = true
+			terms = append(terms, term)
+		}
+	}
+
+	for _, seg := range Segments(query) {
+		if !seg.CJK {
+			add(wordTerm(seg.Text))
+			continue
+		}
+
+		if len(seg.Text) == 1 {
+			add(string(seg.Text))
+			continue
+		}
+		for i := 0; i+1 < len(seg.Text); i++ {
+			add(string(seg.Text[i : i+2]))
+		}
+	}
+
+	return terms
+}
+
+// Count 统计词频
+func Count(terms []string) map[string]int {
+	counts := make(map[string]int, len(terms))
+	for _, t := range terms {
+		counts[t]++
+	}
+	return counts
+}
+
+// wordTerm 英文单词词项，忽略单个字母
+func wordTerm(word []rune) string {
+	if len(word) == 1 && unicode.IsLetter(word[0]) {
+		return ""
+	}
+	if len(word) > maxTermRunes {
+		word = word[:maxTermRunes]
+	}
+	return string(word)
+}
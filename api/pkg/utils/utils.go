@@ -141,6 +141,20 @@ func IsValidURL(url string) bool {
 	return reg.MatchString(url)
 }
 
+// AbsoluteURL 将站内相对地址转换为绝对地址，已是绝对地址时原样返回
+func AbsoluteURL(baseURL, u string) string {
+	if u == "" || strings.HasPrefix(u, "http://") || strings.HasPrefix(u, "https://") {
+		return u
+	}
+	if strings.HasPrefix(u, "//") {
+		return "https:" + u
+	}
+	if !strings.HasPrefix(u, "/") {
+		u = "/" + u
+	}
+	return strings.TrimRight(baseURL, "/") + u
+}
+
 // IsValidSlug 验证 slug 格式
 func IsValidSlug(slug string) bool {
 	reg := regexp.MustCompile(`^[a-z0-9\-\p{Han}]+$`)
@@ -7,11 +7,11 @@ import MarkdownPreview from '@/components/post/MarkdownPreview';
 import PostMeta from '@/components/post/PostMeta';
 import PostViewCounter from '@/components/post/PostViewCounter';
 import { BackButton, Tag } from '@/components/ui';
-import { Post, publicApi } from '@/lib/api';
+import { getRedirect, Post, publicApi } from '@/lib/api';
 import { Metadata } from 'next';
 import { getTranslations, setRequestLocale } from 'next-intl/server';
 import Link from 'next/link';
-import { notFound } from 'next/navigation';
+import { notFound, permanentRedirect, redirect } from 'next/navigation';
 
 export async function generateMetadata({
   params,
@@ -51,6 +51,15 @@ export default async function BlogDetailPage({
     const res = await publicApi.posts.get(slug);
     post = res.data;
   } catch (error) {
+    // 旧 slug 跳转到文章的当前地址
+    const target = getRedirect(error);
+    if (target) {
+      const location = `/${locale}${target.location}`;
+      if (target.permanent) {
+        permanentRedirect(location);
+      }
+      redirect(location);
+    }
     notFound();
   }
 
@@ -23,6 +23,9 @@ interface Comment {
   parent_id?: number;
   parent_nickname?: string;
   replies?: Comment[];
+  reply_count?: number;
+  has_more?: boolean;
+  next_cursor?: string;
 }
 
 interface CommentSectionProps {
@@ -40,7 +43,11 @@ export default function CommentSection({
 }: CommentSectionProps) {
   const t = useTranslations('comment');
   const [comments, setComments] = useState<Comment[]>([]);
+  const [nextCursor, setNextCursor] = useState<string | undefined>();
+  const [hasMore, setHasMore] = useState(false);
   const [loading, setLoading] = useState(true);
+  const [loadingMore, setLoadingMore] = useState(false);
+  const [loadingRepliesId, setLoadingRepliesId] = useState<number | null>(null);
   const [submitting, setSubmitting] = useState(false);
   const [replyTo, setReplyTo] = useState<number | null>(null);
   const [replyToCommentId, setReplyToCommentId] = useState<number | null>(null);
@@ -68,39 +75,47 @@ export default function CommentSection({
     }
   }, []);
 
+  const getUserEmail = () => email || localStorage.getItem('kuaiyu_comment_email') || '';
+
+  const listComments = (cursor?: string) => {
+    // 确定评论类型和目标ID
+    let commentType: 'post' | 'life' | 'guestbook' | undefined;
+    let targetId: number | undefined;
+
+    if (isGuestbook) {
+      commentType = 'guestbook';
+      targetId = undefined;
+    } else if (postId) {
+      commentType = 'post';
+      targetId = postId;
+    } else if (lifeRecordId) {
+      commentType = 'life';
+      targetId = lifeRecordId;
+    }
+
+    return publicApi.comments.list({
+      comment_type: commentType,
+      target_id: targetId,
+      // 向后兼容参数
+      post_id: postId,
+      life_record_id: lifeRecordId,
+      is_guestbook: isGuestbook,
+      email: getUserEmail(),
+      cursor,
+    });
+  };
+
   const fetchComments = async (showLoading = true) => {
     if (showLoading) {
       setLoading(true);
     }
     try {
-      const userEmail = email || localStorage.getItem('kuaiyu_comment_email') || '';
-
-      // 确定评论类型和目标ID
-      let commentType: 'post' | 'life' | 'guestbook' | undefined;
-      let targetId: number | undefined;
-
-      if (isGuestbook) {
-        commentType = 'guestbook';
-        targetId = undefined;
-      } else if (postId) {
-        commentType = 'post';
-        targetId = postId;
-      } else if (lifeRecordId) {
-        commentType = 'life';
-        targetId = lifeRecordId;
-      }
-
-      const res = await publicApi.comments.list({
-        comment_type: commentType,
-        target_id: targetId,
-        // 向后兼容参数
-        post_id: postId,
-        life_record_id: lifeRecordId,
-        is_guestbook: isGuestbook,
-        email: userEmail,
-      });
-      setComments(res.data || []);
-      return res.data || [];
+      const res = await listComments();
+      const items = res.data?.items || [];
+      setComments(items);
+      setNextCursor(res.data?.next_cursor);
+      setHasMore(!!res.data?.has_more);
+      return items;
     } catch (error) {
       console.error('Failed to fetch comments:', error);
       return [];
@@ -115,6 +130,52 @@ export default function CommentSection({
     fetchComments();
   }, [postId, lifeRecordId, isGuestbook]);
 
+  // 加载下一页顶层评论
+  const loadMore = async () => {
+    if (!nextCursor || loadingMore) return;
+    setLoadingMore(true);
+    try {
+      const res = await listComments(nextCursor);
+      const items = res.data?.items || [];
+      setComments((prev) => [...prev, ...items.filter((item) => !prev.some((c) => c.id === item.id))]);
+      setNextCursor(res.data?.next_cursor);
+      setHasMore(!!res.data?.has_more);
+    } catch (error) {
+      console.error('Failed to load more comments:', error);
+    } finally {
+      setLoadingMore(false);
+    }
+  };
+
+  // 加载顶层评论的更多回复
+  const loadMoreReplies = async (comment: Comment) => {
+    if (!comment.next_cursor || loadingRepliesId !== null) return;
+    setLoadingRepliesId(comment.id);
+    try {
+      const res = await publicApi.comments.replies(comment.id, {
+        email: getUserEmail(),
+        cursor: comment.next_cursor,
+      });
+      const items = res.data?.items || [];
+      setComments((prev) =>
+        prev.map((c) =>
+          c.id === comment.id
+            ? {
+              ...c,
+              replies: [...(c.replies || []), ...items],
+              has_more: !!res.data?.has_more,
+              next_cursor: res.data?.next_cursor,
+            }
+            : c
+        )
+      );
+    } catch (error) {
+      console.error('Failed to load more replies:', error);
+    } finally {
+      setLoadingRepliesId(null);
+    }
+  };
+
   const handleSubmit = async (e: React.FormEvent) => {
     e.preventDefault();
     const currentContent = replyTo ? replyContent : content;
@@ -322,6 +383,20 @@ export default function CommentSection({
       </div>
 
       {comment.replies?.map((reply) => renderComment(reply, true, comment.id))}
+
+      {!isReply && comment.has_more && (
+        <div className="ml-12 mt-4">
+          <Button
+            type="button"
+            variant="ghost"
+            size="sm"
+            loading={loadingRepliesId === comment.id}
+            onClick={() => loadMoreReplies(comment)}
+          >
+            {t('viewMoreReplies')}
+          </Button>
+        </div>
+      )}
     </div>
   );
 
@@ -395,7 +470,16 @@ export default function CommentSection({
       {loading ? (
         <Loading />
       ) : comments.length > 0 ? (
-        <div>{comments.map((comment) => renderComment(comment))}</div>
+        <div>
+          {comments.map((comment) => renderComment(comment))}
+          {hasMore && (
+            <div className="flex justify-center">
+              <Button type="button" variant="secondary" loading={loadingMore} onClick={loadMore}>
+                {t('loadMore')}
+              </Button>
+            </div>
+          )}
+        </div>
       ) : (
         <Empty text={t('noComments')} />
       )}
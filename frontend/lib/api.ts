@@ -108,6 +108,10 @@ export interface Comment {
   post_id?: number; // 向后兼容
   life_record_id?: number; // 向后兼容
   parent_id?: number;
+  reply_to_id?: number;
+  root_id?: number;
+  depth?: number;
+  parent_nickname?: string;
   nickname: string;
   avatar: string;
   website: string;
@@ -118,6 +122,14 @@ export interface Comment {
   replies?: Comment[];
   reply_count?: number;
   has_more?: boolean;
+  next_cursor?: string; // 加载更多回复的游标
+}
+
+// 按游标分页的评论
+export interface CommentPage {
+  items: Comment[];
+  next_cursor?: string;
+  has_more: boolean;
 }
 
 // 配置类型
@@ -133,6 +145,14 @@ export interface ArchiveYear {
   posts: Post[];
 }
 
+// 重定向指示（旧 slug 返回 301/302，data 中给出新地址）
+export interface Redirect {
+  type: string;
+  slug?: string;
+  location: string;
+  permanent: boolean;
+}
+
 // ===========================================
 // API 方法
 // ===========================================
@@ -199,8 +219,14 @@ export const commentApi = {
     life_record_id?: number; // 向后兼容
     is_guestbook?: boolean; // 向后兼容
     email?: string;
+    cursor?: string;
+    limit?: number;
   }) =>
-    api.get<any, ApiResponse<Comment[]>>('/api/comments', { params }),
+    api.get<any, ApiResponse<CommentPage>>('/api/comments', { params }),
+
+  // 加载评论的更多回复
+  replies: (id: number, params?: { email?: string; cursor?: string; limit?: number }) =>
+    api.get<any, ApiResponse<CommentPage>>(`/api/comments/${id}/replies`, { params }),
 
   // 创建评论
   create: (data: {
@@ -269,6 +295,19 @@ export const contributionApi = {
   },
 };
 
+// 从请求错误中取出重定向指示，不是重定向时返回 null
+export function getRedirect(error: unknown): Redirect | null {
+  if (!axios.isAxiosError(error)) {
+    return null;
+  }
+  const status = error.response?.status;
+  if (status !== 301 && status !== 302) {
+    return null;
+  }
+  const redirect = (error.response?.data as ApiResponse<Redirect> | undefined)?.data;
+  return redirect?.location ? redirect : null;
+}
+
 // ===========================================
 // 公共 API 导出（用于服务端组件）
 // ===========================================
@@ -296,8 +335,11 @@ export const publicApi = {
       life_record_id?: number; // 向后兼容
       is_guestbook?: boolean; // 向后兼容
       email?: string;
+      cursor?: string;
+      limit?: number;
     }) =>
-      api.get<any, ApiResponse<Comment[]>>('/api/comments', { params }),
+      api.get<any, ApiResponse<CommentPage>>('/api/comments', { params }),
+    replies: commentApi.replies,
     create: commentApi.create,
   },
   analytics: analyticsApi,